@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the autocompletion script for the specified shell",
+	Long: `Generate the autocompletion script of git-contrib for the specified shell.
+
+To load completions in the current bash session:
+  source <(git-contrib completion bash)
+
+To load completions in the current zsh session:
+  source <(git-contrib completion zsh)
+
+To load completions in the current fish session:
+  git-contrib completion fish | source
+
+To load completions in the current PowerShell session:
+  git-contrib completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := writeCompletion(args[0], os.Stdout); err != nil {
+			fmt.Println("Error:", err)
+		}
+	},
+}
+
+// writeCompletion writes the completion script of the given shell.
+func writeCompletion(shell string, w io.Writer) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletionV2(w, true)
+	case "zsh":
+		return rootCmd.GenZshCompletion(w)
+	case "fish":
+		return rootCmd.GenFishCompletion(w, true)
+	case "powershell":
+		return rootCmd.GenPowerShellCompletionWithDesc(w)
+	}
+
+	return fmt.Errorf("unsupported shell %q", shell)
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
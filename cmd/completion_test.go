@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// TestWriteCompletion tests the writeCompletion function
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var out bytes.Buffer
+		if err := writeCompletion(shell, &out); err != nil {
+			t.Errorf("Unexpected error for %s: %v", shell, err)
+			continue
+		}
+		if !strings.Contains(out.String(), "git-contrib") {
+			t.Errorf("Expected the %s script to mention git-contrib", shell)
+		}
+	}
+
+	// Unsupported shells are reported
+	var out bytes.Buffer
+	if err := writeCompletion("tcsh", &out); err == nil {
+		t.Errorf("Expected an error for an unsupported shell, got nil")
+	}
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/acheddir/git-contrib/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the git-contrib configuration",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Display the effective settings of the stats command and where they come from",
+	Long: `Display the effective settings of the stats command and where each comes from.
+Settings are layered from lowest to highest precedence: defaults, the configuration
+file, GIT_CONTRIB_* environment variables, and command line flags.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := configFilePath()
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		settings, err := config.Load(path)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		if err := loadConfig(statsCmd); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		sources := config.Sources(statsCmd.Flags(), settings, os.LookupEnv)
+
+		fmt.Printf("Config file: %s\n\n", path)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "SETTING\tVALUE\tSOURCE\n")
+		statsCmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", flag.Name, flag.Value.String(), sources[flag.Name])
+		})
+		if err := w.Flush(); err != nil {
+			fmt.Println("Error:", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+}
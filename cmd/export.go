@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/fileutil"
+	"github.com/acheddir/git-contrib/pkg/stats"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the export command
+var (
+	exportDir         string
+	exportPaths       []string
+	exportAll         bool
+	exportEmail       string
+	exportSelf        bool
+	exportColorScheme string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write the contribution graph and counts to several files at once",
+	Long: `Analyze the repositories once and write the contribution graph as contrib.svg and contrib.png,
+and the daily counts as contrib.json and contrib.csv, to the --dir directory (created if missing).
+The history is walked once for all the formats. The files written are listed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The arguments are valid, so errors from here on don't call for the usage
+		cmd.SilenceUsage = true
+
+		if err := loadConfig(cmd); err != nil {
+			return err
+		}
+
+		if !fileutil.SliceContains(stats.ColorSchemeNames(), exportColorScheme) {
+			return fmt.Errorf("unknown color scheme %q, expected one of %s", exportColorScheme, strings.Join(stats.ColorSchemeNames(), ", "))
+		}
+
+		directories, err := analyzedDirectories(exportPaths, exportAll, "export")
+		if err != nil {
+			return err
+		}
+
+		filter := stats.Filter{}
+		if exportSelf {
+			exportEmail, err = selfEmail(commands.ScopeGlobal, ".")
+			if err != nil {
+				return err
+			}
+		}
+		if exportEmail != "" {
+			filter.Emails = []string{exportEmail}
+		}
+
+		return commands.Export(commands.ExportOptions{
+			Filter:      filter,
+			Directories: directories,
+			Dir:         exportDir,
+			Render:      stats.RenderOptions{ColorScheme: exportColorScheme},
+			Progress:    newProgress(),
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringVarP(&exportDir, "dir", "d", ".", "The directory the files are written to")
+	exportCmd.Flags().StringSliceVarP(&exportPaths, "path", "p", []string{"."}, "The directory to analyze, repeat to combine several repositories (default is the current working directory)")
+	exportCmd.Flags().BoolVarP(&exportAll, "all", "a", false, "Analyze all the repositories tracked with the scan command")
+	exportCmd.Flags().StringVarP(&exportEmail, "email", "e", "", "The email address to filter commits by (if empty, counts all users)")
+	exportCmd.Flags().BoolVarP(&exportSelf, "self", "s", false, "Use the current user's email from the global git config")
+	exportCmd.Flags().StringVar(&exportColorScheme, "color-scheme", stats.DefaultColorScheme, "The color scheme of the images: github, halloween or dracula")
+
+	_ = exportCmd.MarkFlagDirname("dir")
+	_ = exportCmd.MarkFlagDirname("path")
+	_ = exportCmd.RegisterFlagCompletionFunc("color-scheme", cobra.FixedCompletions(stats.ColorSchemeNames(), cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/spf13/cobra"
+)
+
+var forceFlag bool
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the git-contrib dotfile and a starter configuration file",
+	Long: `Create the .git-contrib dotfile (empty, or the file given with --dotfile) and a starter
+.git-contrib.yaml configuration file with commented defaults in your home directory.
+Existing files are left untouched unless --force is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Println("Error getting home directory:", err)
+			return
+		}
+
+		dotFile, err := dotFilePath()
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+
+		err = commands.Init(homeDir, dotFile, forceFlag)
+		if err != nil {
+			fmt.Println("Error:", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+
+	// Add the force flag to overwrite existing files
+	initCmd.Flags().BoolVarP(&forceFlag, "force", "f", false, "Overwrite existing files")
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/spf13/cobra"
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <file>",
+	Short: "Track the repositories listed in another dotfile",
+	Long: `Add the repositories listed in another .git-contrib dotfile (e.g. copied from another machine)
+to the tracked repositories. Paths are cleaned and repositories already tracked are not added twice.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The arguments are valid, so errors from here on don't call for the usage
+		cmd.SilenceUsage = true
+
+		dotFile, err := dotFilePath()
+		if err != nil {
+			return err
+		}
+
+		return commands.Merge(args[0], dotFile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+}
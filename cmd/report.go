@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/fileutil"
+	"github.com/acheddir/git-contrib/pkg/stats"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the report command
+var (
+	reportEmail           string
+	reportSelf            bool
+	reportFormat          string
+	reportSort            string
+	reportWorkingDaysOnly bool
+)
+
+// reportConfigKeys reads the flags of the report command from their own keys of the configuration file,
+// as its keys are shared by all the commands and the format of stats (e.g. json) isn't one of the report.
+var reportConfigKeys = map[string]string{"format": "report-format"}
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Print a table of the activity of every tracked repository",
+	Long: `Analyze the tracked repositories once and print a table with a line per repository:
+its name, the commits and active days of the last 6 months, the date of its last commit and its current streak,
+followed by a line for all of them together. Use --format csv to load the table in a spreadsheet
+(the CSV has no line for all the repositories together).
+
+The last commit is the most recent commit in the window, so the repositories without commits
+in the last 6 months, e.g. archived ones, show "-" (and an empty field in the CSV).
+The format is read from the report-format setting of the configuration file (or GIT_CONTRIB_REPORT_FORMAT),
+as the format setting belongs to the stats command.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The arguments are valid, so errors from here on don't call for the usage
+		cmd.SilenceUsage = true
+
+		if err := loadConfigKeys(cmd, reportConfigKeys); err != nil {
+			return err
+		}
+
+		if !fileutil.SliceContains(stats.ReportFormats, reportFormat) {
+			return fmt.Errorf("unknown report format %q, expected one of %s", reportFormat, strings.Join(stats.ReportFormats, ", "))
+		}
+		if !fileutil.SliceContains(stats.RepoOrders, reportSort) {
+			return fmt.Errorf("unknown repository order %q, expected one of %s", reportSort, strings.Join(stats.RepoOrders, ", "))
+		}
+
+		directories, err := trackedDirectories("report on")
+		if err != nil {
+			return err
+		}
+
+		filter := stats.Filter{}
+		if reportSelf {
+			reportEmail, err = selfEmail(commands.ScopeGlobal, ".")
+			if err != nil {
+				return err
+			}
+		}
+		if reportEmail != "" {
+			filter.Emails = []string{reportEmail}
+		}
+
+		return commands.Report(commands.ReportOptions{
+			Filter:          filter,
+			Directories:     directories,
+			Format:          reportFormat,
+			SortRepos:       reportSort,
+			WorkingDaysOnly: reportWorkingDaysOnly,
+			Progress:        newProgress(),
+		})
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().StringVarP(&reportEmail, "email", "e", "", "The email address to filter commits by (if empty, counts all users)")
+	reportCmd.Flags().BoolVarP(&reportSelf, "self", "s", false, "Use the current user's email from the global git config")
+	reportCmd.Flags().StringVar(&reportFormat, "format", stats.FormatTerminal, "The output format: terminal (aligned table) or csv (the report-format setting of the configuration file)")
+	reportCmd.Flags().StringVar(&reportSort, "sort-repos", stats.SortByCommits, "The order of the repositories: commits, path or recent")
+	reportCmd.Flags().BoolVar(&reportWorkingDaysOnly, "working-days-only", false, "Don't break streaks on weekends without commits (holidays are not considered)")
+
+	_ = reportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(stats.ReportFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = reportCmd.RegisterFlagCompletionFunc("sort-repos", cobra.FixedCompletions(stats.RepoOrders, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/stats"
+)
+
+// TestReportConfigKeys tests that the --format of report reads report-format, not the format of stats
+func TestReportConfigKeys(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("GIT_CONTRIB_FORMAT", stats.FormatJSON)
+	t.Cleanup(func() { reportFormat = stats.FormatTerminal })
+
+	// The format of stats is left to stats
+	config := filepath.Join(home, commands.ConfigFileName)
+	if err := os.WriteFile(config, []byte("format: json\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+	if err := loadConfigKeys(reportCmd, reportConfigKeys); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reportFormat != stats.FormatTerminal {
+		t.Errorf("Expected the default format, got %q", reportFormat)
+	}
+
+	// The format of the report is read from its own setting
+	if err := os.WriteFile(config, []byte("format: json\nreport-format: csv\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the configuration file: %v", err)
+	}
+	if err := loadConfigKeys(reportCmd, reportConfigKeys); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reportFormat != stats.FormatCSV {
+		t.Errorf("Expected the csv format, got %q", reportFormat)
+	}
+
+	// And from its own environment variable
+	t.Setenv("GIT_CONTRIB_REPORT_FORMAT", stats.FormatTerminal)
+	if err := loadConfigKeys(reportCmd, reportConfigKeys); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reportFormat != stats.FormatTerminal {
+		t.Errorf("Expected the terminal format of the environment, got %q", reportFormat)
+	}
+}
@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/fileutil"
+)
+
+// analyzedDirectories returns the repositories to analyze: the tracked ones with all, see trackedDirectories,
+// otherwise the paths made absolute, in the given order and without duplicates.
+// The purpose completes the error reported when no tracked repository is left, e.g. "analyze".
+func analyzedDirectories(paths []string, all bool, purpose string) ([]string, error) {
+	if all {
+		return trackedDirectories(purpose)
+	}
+
+	var directories []string
+	for _, path := range paths {
+		directory, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+		directories = fileutil.JoinSlices([]string{directory}, directories)
+	}
+	return directories, nil
+}
+
+// trackedDirectories returns the repositories listed in the dotfile, without the ignored ones.
+// The purpose completes the error reported when none are left, e.g. "no tracked repositories to export".
+func trackedDirectories(purpose string) ([]string, error) {
+	dotFile, err := dotFilePath()
+	if err != nil {
+		return nil, err
+	}
+	directories, err := fileutil.ParseFileLines(dotFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(directories) == 0 {
+		return nil, fmt.Errorf("no tracked repositories to %s, use 'git-contrib scan <folder>' to track some", purpose)
+	}
+
+	// Leave out the repositories listed in the ignore file
+	patterns, err := commands.LoadIgnorePatterns(dotFile)
+	if err != nil {
+		return nil, err
+	}
+	directories = commands.FilterIgnored(directories, patterns)
+	if len(directories) == 0 {
+		return nil, fmt.Errorf("all tracked repositories are ignored by %s, no repositories left to %s", commands.IgnoreFileName, purpose)
+	}
+
+	return directories, nil
+}
+
+// selfEmail returns the email of the user for the --self flags, from the git config of the given scope
+// (the local one being the configuration of the repository in dir), or an error if none is set.
+func selfEmail(scope string, dir string) (string, error) {
+	email, err := commands.SelfEmail(scope, dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user email from git config: %w", err)
+	}
+	if email == "" {
+		return "", fmt.Errorf("no email found in the %s git config, set it with 'git config --%s user.email \"your.email@example.com\"'", scope, scope)
+	}
+	return email, nil
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+)
+
+// TestAnalyzedDirectories tests that the paths and the tracked repositories are resolved the same for every command
+func TestAnalyzedDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, commands.DotFileName)
+	t.Setenv(commands.DotFileEnv, dotFile)
+
+	// The paths are made absolute, once each
+	directories, err := analyzedDirectories([]string{tempDir, ".", tempDir}, false, "analyze")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cwd, _ := filepath.Abs(".")
+	if !reflect.DeepEqual(directories, []string{tempDir, cwd}) {
+		t.Errorf("Expected %v, got %v", []string{tempDir, cwd}, directories)
+	}
+
+	// Without tracked repositories, the purpose is reported
+	if _, err := analyzedDirectories(nil, true, "export"); err == nil || !strings.Contains(err.Error(), "no tracked repositories to export") {
+		t.Errorf("Expected an error for no tracked repositories, got %v", err)
+	}
+
+	// The ignored repositories are left out
+	if err := os.WriteFile(dotFile, []byte("/code/app\n/code/vendor\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the dotfile: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, commands.IgnoreFileName), []byte("/code/vendor\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the ignore file: %v", err)
+	}
+	directories, err = analyzedDirectories([]string{"."}, true, "analyze")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(directories, []string{"/code/app"}) {
+		t.Errorf("Expected only /code/app, got %v", directories)
+	}
+
+	// All of them may be ignored
+	if err := os.WriteFile(filepath.Join(tempDir, commands.IgnoreFileName), []byte("/code/*\n"), 0644); err != nil {
+		t.Fatalf("Failed to write the ignore file: %v", err)
+	}
+	if _, err := analyzedDirectories(nil, true, "analyze"); err == nil || !strings.Contains(err.Error(), "all tracked repositories are ignored") {
+		t.Errorf("Expected an error for all repositories ignored, got %v", err)
+	}
+}
@@ -3,29 +3,102 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/config"
 	"github.com/spf13/cobra"
 )
 
+var dotFileFlag string
+
 var rootCmd = &cobra.Command{
 	Use:   "git-contrib",
 	Short: "Git-contrib is a tool for analyzing Git commits and displaying a contribution graph.",
 	Long:  fmt.Sprintf("Git-contrib is a tool for analyzing Git commits and displaying a contribution graph.\n%s", Version),
+	// Errors are printed once, by Execute
+	SilenceErrors: true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Println("Error:", err)
 		os.Exit(1)
 	}
 }
 
+// configFilePath returns the path of the configuration file in the home directory.
+func configFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, commands.ConfigFileName), nil
+}
+
+// dotFilePath returns the path of the dotfile, from the --dotfile flag, the environment or the home directory.
+func dotFilePath() (string, error) {
+	return commands.ResolveDotFile(dotFileFlag, os.LookupEnv)
+}
+
+// loadConfig applies the settings of the configuration file, then of the environment,
+// to the flags of the given command that weren't set on the command line.
+func loadConfig(cmd *cobra.Command) error {
+	return loadConfigKeys(cmd, nil)
+}
+
+// loadConfigKeys applies the settings like loadConfig, reading some flags from other keys of
+// the configuration file and the environment, e.g. the --format of report from report-format,
+// so they don't inherit the settings of the flags of the same name of other commands.
+//
+// Parameters:
+//   - cmd: The command whose flags are set
+//   - keys: The key read for each of these flags, by flag name (if nil, each flag reads its own name)
+//
+// Returns:
+//   - error: An error if the configuration file can't be read or a setting is invalid
+func loadConfigKeys(cmd *cobra.Command, keys map[string]string) error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+
+	settings, err := config.Load(path)
+	if err != nil {
+		return err
+	}
+
+	envNames := make(map[string]string, len(keys))
+	for flag, key := range keys {
+		value, ok := settings[key]
+		delete(settings, flag)
+		if ok {
+			settings[flag] = value
+		}
+		envNames[config.EnvName(flag)] = config.EnvName(key)
+	}
+
+	if err := config.Apply(cmd.Flags(), settings); err != nil {
+		return err
+	}
+
+	return config.ApplyEnv(cmd.Flags(), func(name string) (string, bool) {
+		if key, ok := envNames[name]; ok {
+			return os.LookupEnv(key)
+		}
+		return os.LookupEnv(name)
+	})
+}
+
 func init() {
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 
 	// rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.git-contrib.yaml)")
+	rootCmd.PersistentFlags().StringVar(&dotFileFlag, "dotfile", "", "The file listing the tracked repositories (default is ~/.git-contrib, or $"+commands.DotFileEnv+")")
+	rootCmd.PersistentFlags().BoolVar(&noProgressFlag, "no-progress", false, "Don't print the progress of long operations (it is only printed when the standard error is a terminal)")
 }
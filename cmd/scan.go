@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/fileutil"
+	"github.com/acheddir/git-contrib/pkg/scanner"
+	"github.com/spf13/cobra"
+)
+
+// Flags for the scan command
+var (
+	skipDirs     []string
+	dryRunFlag   bool
+	newerThan    time.Duration
+	orderFlag    bool
+	scanType     string
+	metadataFlag bool
+)
+
+var scanCmd = &cobra.Command{
+	Use:   "scan [folder]",
+	Short: "Scan a folder for Git repositories to track",
+	Long: `Recursively scan a folder (default is the current working directory) for Git repositories,
+including linked worktrees, and add them to the repositories tracked in the .git-contrib dotfile.
+The tracked repositories are analyzed together with 'git-contrib stats --all'.
+Folders whose name matches one of the --skip glob patterns are not descended into.
+Use --newer-than to only descend into folders modified recently, for fast incremental refreshes:
+the repositories already tracked stay tracked. Use --order-by-activity to list (and store) the repositories
+by the date of their last commit, most recent first. Use --dry-run to preview the repositories that would be added without writing the dotfile.
+Use --metadata to also record the remote URL and the date of the last commit of each repository found
+in a versioned JSON file named after the dotfile (~/.git-contrib.json by default), for tools built on top of git-contrib.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The arguments are valid, so errors from here on don't call for the usage
+		cmd.SilenceUsage = true
+
+		if err := loadConfig(cmd); err != nil {
+			return err
+		}
+
+		folder := "."
+		if len(args) == 1 {
+			folder = args[0]
+		}
+
+		dotFile, err := dotFilePath()
+		if err != nil {
+			return err
+		}
+
+		if !fileutil.SliceContains(scanner.RepoTypes, scanType) {
+			return fmt.Errorf("unknown repository type %q, expected one of %s", scanType, strings.Join(scanner.RepoTypes, ", "))
+		}
+
+		opts := scanner.Options{Skip: skipDirs, RepoType: scanType, OrderByActivity: orderFlag, Metadata: metadataFlag, Progress: newProgress()}
+		if newerThan > 0 {
+			opts.ModifiedSince = time.Now().Add(-newerThan)
+		}
+
+		return commands.Scan(folder, dotFile, opts, dryRunFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().StringSliceVar(&skipDirs, "skip", scanner.DefaultSkipDirs,
+		"Glob patterns of folder names not to descend into (e.g. 'build-*,*.egg-info')")
+	scanCmd.Flags().DurationVar(&newerThan, "newer-than", 0, "Only descend into folders modified within this duration (e.g. 168h), 0 scans everything")
+	scanCmd.Flags().BoolVar(&orderFlag, "order-by-activity", false, "Sort the repositories by the date of their HEAD commit, most recent first")
+	scanCmd.Flags().StringVar(&scanType, "repo-type", scanner.RepoTypeAll, "The repositories to track: remote (with a remote), local (without) or all")
+	scanCmd.Flags().BoolVar(&metadataFlag, "metadata", false, "Record the remote and the last commit of the repositories found in the dotfile path plus .json")
+	scanCmd.Flags().BoolVar(&dryRunFlag, "dry-run", false, "List the repositories that would be added without writing the dotfile")
+
+	_ = scanCmd.RegisterFlagCompletionFunc("repo-type", cobra.FixedCompletions(scanner.RepoTypes, cobra.ShellCompDirectiveNoFileComp))
+}
@@ -3,18 +3,96 @@ package cmd
 import (
 	"fmt"
 	"github.com/acheddir/git-contrib/pkg/commands"
+	"github.com/acheddir/git-contrib/pkg/fileutil"
+	"github.com/acheddir/git-contrib/pkg/scanner"
+	"github.com/acheddir/git-contrib/pkg/stats"
 	"github.com/spf13/cobra"
 	"os"
-	"os/exec"
-	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
-var workingDir string
+var workingDirs []string
+var allFlag bool
+var reposFrom string
+var subdir string
 var email string
+var emailsFile string
+var combineAuthors string
+var excludeEmails []string
 var selfFlag bool
+var autoSelfFlag bool
+var selfScope string
 var showCommitCountFlag bool
 var showDaysOfMonthFlag bool
+var showSummaryFlag bool
+var porcelainFlag bool
+var goal int
+var betweenCommitsFlag bool
+var burstsFlag bool
+var burstWindow time.Duration
+var byTimezoneFlag bool
+var showSizesFlag bool
+var showBreakdownFlag bool
+var sortRepos string
+var glyphsFlag bool
+var emptyChar string
+var graphStyle string
+var colorScheme string
+var colorsFlag string
+var relativeFlag bool
+var alignToTodayFlag bool
+var graphOnlyFlag bool
+var weeklyFlag bool
+var compactFlag bool
+var autoCompactFlag bool
+var weekNumbersFlag bool
+var collapseEmptyRowsFlag bool
+var stripeFlag bool
+var deltaFlag bool
+var binaryFlag bool
+var monoFlag bool
+var denseFlag bool
+var topN int
+var repoType string
+var label string
+var forceColorFlag bool
+var noColorFlag bool
+var maxCellValue int
+var annotateAbove int
+var groupBy string
+var requireFullFlag bool
+var jobs int
+var retries int
+var outputFormat string
+var outputFile string
+var clipboardFlag bool
+var noPagerFlag bool
+var jsonlFlag bool
+var anonymizeFlag bool
+var perAuthorFlag bool
+var quietEmptyFlag bool
+var strictEmailFlag bool
+var detailDate string
+var shortHashLength int
+var diffPeriods string
+var noBotsFlag bool
+var botPatterns []string
+var workingDaysOnlyFlag bool
+var excludeWeekendsFlag bool
+var coAuthorsFlag bool
+var submodulesFlag bool
+var headRevision string
+var allBranchesFlag bool
+var endAtHeadFlag bool
+var monthsFlag int
+var sinceDate string
+var weeks int
+var repoNameFlag bool
+var verboseFlag bool
+var tagsFlag bool
+var reflogFlag bool
 
 var statsCmd = &cobra.Command{
 	Use:   "stats",
@@ -22,59 +100,648 @@ var statsCmd = &cobra.Command{
 	Long: `Process Git repositories and display commits contribution graph for all users.
 This command will analyze the current working directory as a Git repository
 and generate statistics about commits made by all users.
-If an email is provided, it will show contributions from that email address only.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		// Check if both -c and -d flags are used together
-		if showCommitCountFlag && showDaysOfMonthFlag {
-			fmt.Println("Error: The -c (count) and -d (days) flags cannot be used together")
-			return
+If an email is provided, it will show contributions from that email address only.
+If an emails file is provided, it will show the combined contributions of every listed address.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateStatsFlags(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// The flags are valid, so errors from here on don't call for the usage
+		cmd.SilenceUsage = true
+
+		// A PNG image is no use printed on the terminal
+		if outputFormat == stats.FormatPNG && outputFile == "" && isTerminal(os.Stdout) {
+			return fmt.Errorf("the png format can't be printed on the terminal, use --output or redirect the output")
 		}
 
-		// Use the specified working directory, otherwise use the current directory
-		currentDir, err := filepath.Abs(workingDir)
+		// Use the specified working directories (the current directory by default), or the tracked repositories
+		directories, err := analyzedDirectories(workingDirs, allFlag, "analyze")
 		if err != nil {
-			fmt.Println("Error getting current directory:", err)
-			return
+			return err
+		}
+
+		// Analyze the repositories listed in a file, without touching the tracked ones
+		if reposFrom != "" {
+			directories, err = commands.LoadRepositories(reposFrom)
+			if err != nil {
+				return err
+			}
+			if len(directories) == 0 {
+				fmt.Printf("No repositories listed in %s.\n", reposFrom)
+				return nil
+			}
+		}
+
+		// Leave out the repositories with, or without, a remote
+		if repoType != scanner.RepoTypeAll {
+			directories = scanner.FilterByType(directories, repoType)
+			if len(directories) == 0 {
+				fmt.Printf("No %s repositories to analyze.\n", repoType)
+				return nil
+			}
 		}
 
 		// If the self-flag is set, get the email from git config
 		if selfFlag {
-			gitCmd := exec.Command("git", "config", "--global", "user.email")
-			output, err := gitCmd.Output()
+			// The local scope is the configuration of the (first) analyzed repository
+			dir := "."
+			if len(workingDirs) > 0 {
+				dir = workingDirs[0]
+			}
+
+			email, err = selfEmail(selfScope, dir)
 			if err != nil {
-				fmt.Println("Error getting user email from git config:", err)
-				return
+				return err
 			}
-			email = strings.TrimSpace(string(output))
-			if email == "" {
-				fmt.Println("No email found in git config. Please set your email with 'git config --global user.email \"your.email@example.com\"'")
-				return
+		}
+
+		filter := stats.Filter{
+			ExcludeEmails:   excludeEmails,
+			ExcludeBots:     noBotsFlag,
+			BotPatterns:     botPatterns,
+			CoAuthors:       coAuthorsFlag,
+			Subdir:          stats.CleanSubdir(subdir),
+			ExcludeWeekends: excludeWeekendsFlag,
+		}
+
+		// Explain on stderr why commits are left out
+		if verboseFlag {
+			filter.Trace = os.Stderr
+		}
+		if email != "" {
+			filter.Emails = append(filter.Emails, email)
+		}
+
+		// Add the emails listed in the emails file, if any
+		if emailsFile != "" {
+			emails, err := commands.LoadEmails(emailsFile)
+			if err != nil {
+				return err
+			}
+			filter.Emails = fileutil.JoinSlices(emails, filter.Emails)
+		}
+
+		// Combine the aliases of each author, for the filter and the counts per author
+		if combineAuthors != "" {
+			identities, err := commands.LoadIdentities(combineAuthors)
+			if err != nil {
+				return err
 			}
+			filter.Identities = identities
 		}
 
-		err = commands.Stats(email, currentDir, showCommitCountFlag, showDaysOfMonthFlag)
+		// The window of the graph, six months unless --months or --since is given
+		window, err := graphRange()
 		if err != nil {
-			fmt.Println("Error:", err)
+			return err
+		}
+
+		// Use the email most commits of the analyzed repositories were authored with
+		if autoSelfFlag {
+			author, total, err := commands.PrimaryEmail(stats.Options{
+				Filter:       filter,
+				Repositories: directories,
+				Jobs:         jobs,
+				Retries:      retries,
+				Submodules:   submodulesFlag,
+				Head:         headRevision,
+				AllBranches:  allBranchesFlag,
+				EndAtHead:    endAtHeadFlag,
+				Range:        window,
+				Progress:     newProgress(),
+			})
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Using %s, the author of %d of the %d commits\n", author.Email, author.Count, total)
+			filter.Emails = []string{author.Email}
+		}
+
+		// Parse the day to list the commits of, if any
+		var detail time.Time
+		if detailDate != "" {
+			detail, err = time.Parse("2006-01-02", detailDate)
+			if err != nil {
+				return fmt.Errorf("invalid --detail date %q, expected YYYY-MM-DD", detailDate)
+			}
+		}
+
+		// One graph per author needs authors to begin with
+		if perAuthorFlag && len(filter.Emails) == 0 {
+			return fmt.Errorf("the --per-author flag requires --email, --emails-file or --self")
+		}
+		if strictEmailFlag && len(filter.Emails) == 0 {
+			return fmt.Errorf("the --strict-email flag requires --email, --emails-file or --self")
+		}
+
+		// The clipboard gets the output instead of the terminal, so it has no colors (unless forced) nor width
+		tty := isTerminal(os.Stdout) && !clipboardFlag
+		width := 0
+		if !clipboardFlag {
+			width = terminalWidth(os.Stdout)
 		}
+
+		// Only the terminal gets paged, not the files nor the pipes
+		usePager := tty && outputFile == "" && !noPagerFlag
+		height := 0
+		if usePager {
+			height = terminalHeight(os.Stdout)
+		}
+
+		// The window of the bursts only matters with --bursts
+		var bursts time.Duration
+		if burstsFlag {
+			bursts = burstWindow
+		}
+
+		// Without --colors, the color capability is guessed from the terminal
+		colors := colorsFlag
+		if colors == "" {
+			colors = stats.DetectColors(os.Getenv("TERM"), os.Getenv("COLORTERM"))
+		}
+
+		return commands.Stats(commands.StatsOptions{
+			Filter:      filter,
+			Directories: directories,
+			Render: stats.RenderOptions{
+				ShowCommitCount:   showCommitCountFlag,
+				ShowDaysOfMonth:   showDaysOfMonthFlag,
+				Glyphs:            glyphsFlag,
+				EmptyCell:         emptyChar,
+				Style:             graphStyle,
+				ColorScheme:       colorScheme,
+				Colors:            colors,
+				Relative:          relativeFlag,
+				AlignToToday:      alignToTodayFlag,
+				GraphOnly:         graphOnlyFlag,
+				Weekly:            weeklyFlag,
+				Compact:           compactFlag,
+				WeekNumbers:       weekNumbersFlag,
+				CollapseEmptyRows: collapseEmptyRowsFlag,
+				Stripe:            stripeFlag,
+				Weeks:             weeks,
+				Delta:             deltaFlag,
+				Binary:            binaryFlag,
+				Mono:              monoFlag,
+				NoColor:           monoFlag || !stats.UseColor(forceColorFlag, noColorFlag, os.Getenv("NO_COLOR"), tty),
+				MaxCellValue:      maxCellValue,
+				AnnotateAbove:     annotateAbove,
+			},
+			Label:           label,
+			TerminalWidth:   width,
+			AutoCompact:     autoCompactFlag,
+			Dense:           denseFlag,
+			TopN:            topN,
+			ShowSummary:     showSummaryFlag,
+			Porcelain:       porcelainFlag,
+			Goal:            goal,
+			BetweenCommits:  betweenCommitsFlag,
+			BurstWindow:     bursts,
+			ByTimezone:      byTimezoneFlag,
+			ShowSizes:       showSizesFlag,
+			Tags:            tagsFlag,
+			Reflog:          reflogFlag,
+			ShowBreakdown:   showBreakdownFlag,
+			SortRepos:       sortRepos,
+			WorkingDaysOnly: workingDaysOnlyFlag,
+			PerAuthor:       perAuthorFlag,
+			QuietEmpty:      quietEmptyFlag,
+			StrictEmail:     strictEmailFlag,
+			DetailDate:      detail,
+			ShortHashLength: shortHashLength,
+			GroupBy:         groupBy,
+			Compare:         diffPeriods,
+			RequireFull:     requireFullFlag,
+			Submodules:      submodulesFlag,
+			Head:            headRevision,
+			AllBranches:     allBranchesFlag,
+			EndAtHead:       endAtHeadFlag,
+			Range:           window,
+			RepoNames:       repoNameFlag,
+			Jobs:            jobs,
+			Retries:         retries,
+			Format:          outputFormat,
+			Output:          outputFile,
+			Clipboard:       clipboardFlag,
+			Pager:           usePager,
+			TerminalHeight:  height,
+			Progress:        newProgress(),
+			Anonymize:       anonymizeFlag,
+		})
 	},
 }
 
+// graphRange returns the window of the graph chosen with --months or --since,
+// or the empty window (the default six months) without them.
+func graphRange() (stats.GraphRange, error) {
+	today := stats.GetBeginningOfDay(time.Now())
+	switch {
+	case monthsFlag > 0:
+		return stats.MonthsRange(monthsFlag, today), nil
+	case sinceDate != "":
+		since, err := time.Parse("2006-01-02", sinceDate)
+		if err != nil {
+			return stats.GraphRange{}, fmt.Errorf("invalid --since date %q, expected YYYY-MM-DD", sinceDate)
+		}
+		if !since.Before(today) {
+			return stats.GraphRange{}, fmt.Errorf("the --since date must be before today")
+		}
+		return stats.SinceRange(since, today), nil
+	}
+	return stats.GraphRange{}, nil
+}
+
+// validateStatsFlags applies the configuration file to the flags of the stats command,
+// then checks their values and rejects the combinations that don't make sense,
+// so misuse fails with the usage and a non-zero exit code before anything is analyzed.
+func validateStatsFlags(cmd *cobra.Command) error {
+	// Apply the settings of the configuration file
+	if err := loadConfig(cmd); err != nil {
+		return err
+	}
+
+	if annotateAbove < 0 {
+		return fmt.Errorf("the --annotate-above flag must not be negative")
+	}
+
+	// A threshold only makes sense with counts, so it turns them on. It is checked by its own name first,
+	// so the conflicts of the counts don't report a --count flag that wasn't given.
+	if annotateAbove > 0 && (showDaysOfMonthFlag || binaryFlag || deltaFlag || compactFlag) {
+		return fmt.Errorf("the --annotate-above flag cannot be used with --days, --binary, --delta or --compact")
+	}
+	if annotateAbove > 0 {
+		showCommitCountFlag = true
+	}
+
+	// The cells have room for either the count or the day of the month
+	if showCommitCountFlag && showDaysOfMonthFlag {
+		return fmt.Errorf("the --count and --days flags cannot be used together")
+	}
+
+	// The capped count must fit in the cell
+	if maxCellValue < 1 || maxCellValue > stats.DefaultMaxCellValue {
+		return fmt.Errorf("the --max-cell-value flag must be between 1 and %d", stats.DefaultMaxCellValue)
+	}
+
+	// The empty cell character must fit in the cell
+	if cmd.Flags().Changed("empty-char") && utf8.RuneCountInString(emptyChar) != 1 {
+		return fmt.Errorf("the --empty-char flag must be a single character")
+	}
+
+	// Check the graph style is supported
+	if !fileutil.SliceContains(stats.Styles, graphStyle) {
+		return fmt.Errorf("unknown graph style %q, expected one of %s", graphStyle, strings.Join(stats.Styles, ", "))
+	}
+
+	// Check the color scheme is supported
+	if !fileutil.SliceContains(stats.ColorSchemeNames(), colorScheme) {
+		return fmt.Errorf("unknown color scheme %q, expected one of %s", colorScheme, strings.Join(stats.ColorSchemeNames(), ", "))
+	}
+
+	// Check the color capability is supported
+	if colorsFlag != "" && !fileutil.SliceContains(stats.ColorDepths, colorsFlag) {
+		return fmt.Errorf("unknown color capability %q, expected one of %s", colorsFlag, strings.Join(stats.ColorDepths, ", "))
+	}
+
+	// Check the breakdown order is supported
+	if !fileutil.SliceContains(stats.RepoOrders, sortRepos) {
+		return fmt.Errorf("unknown repository order %q, expected one of %s", sortRepos, strings.Join(stats.RepoOrders, ", "))
+	}
+
+	// Check the git config scope is supported
+	if !fileutil.SliceContains(commands.SelfScopes, selfScope) {
+		return fmt.Errorf("unknown git config scope %q, expected one of %s", selfScope, strings.Join(commands.SelfScopes, ", "))
+	}
+
+	// Check the grouping is supported
+	if groupBy != "" && !fileutil.SliceContains(stats.GroupByModes, groupBy) {
+		return fmt.Errorf("unknown grouping %q, expected one of %s", groupBy, strings.Join(stats.GroupByModes, ", "))
+	}
+
+	// --jsonl is a shorthand for --format jsonl
+	if jsonlFlag {
+		if outputFormat != stats.FormatTerminal && outputFormat != stats.FormatJSONL {
+			return fmt.Errorf("the --jsonl flag cannot be used with --format %s", outputFormat)
+		}
+		outputFormat = stats.FormatJSONL
+	}
+
+	// Check the output format is supported, and only combined with the options it applies to
+	if !fileutil.SliceContains(stats.Formats, outputFormat) {
+		return fmt.Errorf("unknown format %q, expected one of %s", outputFormat, strings.Join(stats.Formats, ", "))
+	}
+	if outputFormat == stats.FormatTerminal && outputFile != "" {
+		return fmt.Errorf("the --output flag requires a --format other than terminal")
+	}
+	if outputFormat != stats.FormatTerminal && (detailDate != "" || groupBy != "" || perAuthorFlag) {
+		return fmt.Errorf("the --detail, --group-by and --per-author flags cannot be used with --format %s", outputFormat)
+	}
+
+	if clipboardFlag && outputFile != "" {
+		return fmt.Errorf("the --clipboard and --output flags cannot be used together")
+	}
+	if clipboardFlag && outputFormat == stats.FormatPNG {
+		return fmt.Errorf("the --clipboard flag cannot be used with --format png")
+	}
+
+	if forceColorFlag && noColorFlag {
+		return fmt.Errorf("the --force-color and --no-color flags cannot be used together")
+	}
+
+	// The revision names a commit of the given repositories, not of their submodules or tags
+	if headRevision != "" && (submodulesFlag || tagsFlag) {
+		return fmt.Errorf("the --head flag cannot be used with --submodules or --tags")
+	}
+
+	// Every branch already includes the one of --head
+	if allBranchesFlag && (headRevision != "" || tagsFlag || reflogFlag) {
+		return fmt.Errorf("the --all-branches flag cannot be used with --head, --tags or --reflog")
+	}
+
+	// The window ends on the day of the last commit matched from HEAD, the other histories and the periods relative to today don't follow
+	if endAtHeadFlag && (allBranchesFlag || tagsFlag || reflogFlag) {
+		return fmt.Errorf("the --limit-window-to-head-date flag cannot be used with --all-branches, --tags or --reflog")
+	}
+	if endAtHeadFlag && (detailDate != "" || groupBy == stats.GroupByQuarter || diffPeriods != "" || goal > 0) {
+		return fmt.Errorf("the --limit-window-to-head-date flag cannot be used with --detail, --group-by quarter, --diff or --goal")
+	}
+
+	// The window has a single length, and a start date is relative to today
+	if monthsFlag < 0 {
+		return fmt.Errorf("the --months flag must not be negative")
+	}
+	if monthsFlag > 0 && sinceDate != "" {
+		return fmt.Errorf("the --months and --since flags cannot be used together")
+	}
+	if sinceDate != "" && endAtHeadFlag {
+		return fmt.Errorf("the --since flag cannot be used with --limit-window-to-head-date")
+	}
+	window, err := graphRange()
+	if err != nil {
+		return err
+	}
+
+	// Check the repository type is supported
+	if !fileutil.SliceContains(scanner.RepoTypes, repoType) {
+		return fmt.Errorf("unknown repository type %q, expected one of %s", repoType, strings.Join(scanner.RepoTypes, ", "))
+	}
+
+	// The binary graph tells active days only, the numbers of commits belong to the count-based view
+	if binaryFlag && (showCommitCountFlag || relativeFlag || deltaFlag || goal > 0 || porcelainFlag || outputFormat != stats.FormatTerminal) {
+		return fmt.Errorf("the --binary flag cannot be used with --count, --relative, --delta, --goal, --porcelain or --format")
+	}
+
+	// The characters of the monochrome graph replace the colors and glyphs of the other views
+	if monoFlag && (forceColorFlag || deltaFlag || binaryFlag || relativeFlag || glyphsFlag) {
+		return fmt.Errorf("the --mono flag cannot be used with --force-color, --delta, --binary, --relative or --glyphs")
+	}
+
+	// The cells show changes, not commits
+	if deltaFlag && (showCommitCountFlag || relativeFlag || weeklyFlag) {
+		return fmt.Errorf("the --delta flag cannot be used with --count, --relative or --weekly")
+	}
+
+	// The weekly graph has a single row
+	if collapseEmptyRowsFlag && weeklyFlag {
+		return fmt.Errorf("the --collapse-empty-rows and --weekly flags cannot be used together")
+	}
+
+	// Compact cells have room for a single character
+	if compactFlag && (showCommitCountFlag || showDaysOfMonthFlag || weekNumbersFlag) {
+		return fmt.Errorf("the --compact flag cannot be used with --count, --days or --week-numbers")
+	}
+
+	// Git itself never abbreviates hashes below 4 characters, and SHA-1 hashes have 40
+	if shortHashLength < 4 || shortHashLength > 40 {
+		return fmt.Errorf("the --short-hash-length flag must be between 4 and 40")
+	}
+
+	if topN < 0 {
+		return fmt.Errorf("the --top-n flag must not be negative")
+	}
+	if retries < 0 {
+		return fmt.Errorf("the --retries flag must not be negative")
+	}
+	if goal < 0 {
+		return fmt.Errorf("the --goal flag must not be negative")
+	}
+	if burstWindow <= 0 {
+		return fmt.Errorf("the --burst-window flag must be positive")
+	}
+	// The bursts are part of the summary, printed with --summary or written in the JSON
+	if burstsFlag && !showSummaryFlag && outputFormat != stats.FormatJSON {
+		return fmt.Errorf("the --bursts flag requires --summary or --format json")
+	}
+	if weeks < 0 {
+		return fmt.Errorf("the --weeks flag must not be negative")
+	}
+	if denseFlag && (outputFormat != stats.FormatTerminal || groupBy != "" || detailDate != "") {
+		return fmt.Errorf("the --dense flag cannot be used with --format, --group-by or --detail")
+	}
+
+	// The summary line replaces every other output
+	if porcelainFlag && (outputFormat != stats.FormatTerminal || detailDate != "" || groupBy != "" || denseFlag || perAuthorFlag) {
+		return fmt.Errorf("the --porcelain flag cannot be used with --format, --detail, --group-by, --dense or --per-author")
+	}
+
+	// The reflog is another source than the history, its commits may be gone
+	if reflogFlag && (tagsFlag || headRevision != "" || showSizesFlag || subdir != "" || coAuthorsFlag) {
+		return fmt.Errorf("the --reflog flag cannot be used with --tags, --head, --sizes, --subdir or --co-authors")
+	}
+
+	// Tags have no diff to measure
+	if tagsFlag && (showSizesFlag || subdir != "") {
+		return fmt.Errorf("the --tags flag cannot be used with --sizes or --subdir")
+	}
+
+	// The periods are compared instead of drawing the graph
+	if diffPeriods != "" {
+		if _, _, err := stats.ParseComparison(diffPeriods, window); err != nil {
+			return err
+		}
+		if outputFormat != stats.FormatTerminal || groupBy != "" || detailDate != "" || denseFlag || porcelainFlag {
+			return fmt.Errorf("the --diff flag cannot be used with --format, --group-by, --detail, --dense or --porcelain")
+		}
+	}
+
+	// Commit subjects can't be anonymized
+	if anonymizeFlag && detailDate != "" {
+		return fmt.Errorf("the --anonymize and --detail flags cannot be used together")
+	}
+
+	// Hosts are read from the remotes of the repositories, whose paths are anonymized
+	if anonymizeFlag && groupBy == stats.GroupByHost {
+		return fmt.Errorf("the --anonymize flag cannot be used with --group-by host")
+	}
+
+	// The email comes from a single source
+	if autoSelfFlag && (selfFlag || email != "" || emailsFile != "") {
+		return fmt.Errorf("the --auto-self flag cannot be used with --self, --email or --emails-file")
+	}
+
+	// The repositories come from a single source
+	if reposFrom != "" && (allFlag || cmd.Flags().Changed("path")) {
+		return fmt.Errorf("the --repos-from flag cannot be used with --all or --path")
+	}
+
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(statsCmd)
 
 	// Add the working directory flag to the stats command
-	statsCmd.Flags().StringVarP(&workingDir, "path", "p", ".", "The directory to analyze (default is the current working directory)")
+	statsCmd.Flags().StringSliceVarP(&workingDirs, "path", "p", []string{"."}, "The directory to analyze, repeat to combine several repositories (default is the current working directory)")
+
+	// Add the all flag to analyze the repositories tracked in the dotfile
+	statsCmd.Flags().BoolVarP(&allFlag, "all", "a", false, "Analyze all the repositories tracked with the scan command")
+	statsCmd.Flags().StringVar(&subdir, "subdir", "", "Only count the commits changing files under this directory of the repositories (e.g. packages/ui)")
+	statsCmd.Flags().StringVar(&reposFrom, "repos-from", "", "Analyze the repositories listed in a file, one path per line, instead of the tracked ones")
 
 	// Add the email flag to the stats command (no default value)
 	statsCmd.Flags().StringVarP(&email, "email", "e", "", "The email address to filter commits by (if empty, shows all users)")
 
+	// Add the emails file flag to combine the commits of several email addresses
+	statsCmd.Flags().StringVar(&emailsFile, "emails-file", "", "A file listing email addresses to filter commits by, one per line")
+	statsCmd.Flags().StringVar(&combineAuthors, "combine-authors", "", "A file combining the emails of each author, one author per line as 'canonical@example.com: alias@example.com, ...'")
+
+	// Add the strict email flag to fail when the filtered emails match no commit
+	statsCmd.Flags().BoolVar(&strictEmailFlag, "strict-email", false, "Fail with a non-zero exit code when no commit matches the filtered emails, instead of printing an empty graph")
+
+	// Add the quiet empty flag to render the empty graph without a message
+	statsCmd.Flags().BoolVar(&quietEmptyFlag, "quiet-empty", false, "Render the empty graph instead of a message when no commits are found")
+
+	// Add the detail flag to list the commits of a single day
+	statsCmd.Flags().StringVar(&detailDate, "detail", "", "List the commits of the given day (YYYY-MM-DD) instead of the graph")
+	statsCmd.Flags().IntVar(&shortHashLength, "short-hash-length", stats.DefaultShortHashLength, "The number of characters of the hashes listed with --detail")
+
+	// Add the diff flag to compare two periods instead of drawing the graph
+	statsCmd.Flags().StringVar(&diffPeriods, "diff", "", "Compare two periods side by side instead of the graph: "+strings.Join(stats.CompareModes, ", ")+" (the current one so far vs the previous one) or FROM..TO,FROM..TO")
+
+	// Add the per-author flag to render one graph per email instead of a combined one
+	statsCmd.Flags().BoolVar(&perAuthorFlag, "per-author", false, "Display one labeled graph per email instead of a combined graph")
+
+	// Add the exclude email flag to drop specific authors
+	statsCmd.Flags().StringSliceVar(&excludeEmails, "exclude-email", nil, "An email address to skip commits from (repeatable)")
+
+	// Add flags to exclude commits authored by bots
+	statsCmd.Flags().BoolVar(&noBotsFlag, "no-bots", false, "Exclude commits authored by bots (dependabot, renovate, github-actions, ...)")
+	statsCmd.Flags().StringSliceVar(&botPatterns, "bot-patterns", nil, "Additional bot email patterns for --no-bots, '*' matches anything (repeatable)")
+
+	// Add the flag to report the skipped commits
+	statsCmd.Flags().BoolVarP(&verboseFlag, "verbose", "v", false, "Report every skipped commit and the reason on stderr (email mismatch, bot, out of range, ...)")
+
+	// Add the flag to count the releases cut instead of the commits
+	statsCmd.Flags().BoolVar(&tagsFlag, "tags", false, "Count the annotated tags created (by tagger email) instead of the commits")
+	statsCmd.Flags().BoolVar(&reflogFlag, "reflog", false, "Experimental: count the commits created locally (including amended and rebased ones) from the HEAD reflog instead of the history")
+
+	// Add the flags to choose the window of the graph instead of the last six months
+	statsCmd.Flags().IntVar(&monthsFlag, "months", 0, "The number of months of the graph window before today (default is 6)")
+	statsCmd.Flags().StringVar(&sinceDate, "since", "", "Start the graph window on this day (YYYY-MM-DD) instead of 6 months ago")
+
+	// Add the flag to end the graph on the most recent matched commit instead of today
+	statsCmd.Flags().BoolVar(&endAtHeadFlag, "limit-window-to-head-date", false, "End the graph on the day of the most recent matched commit instead of today, e.g. for archived repositories")
+
+	// Add the flag to walk the history of every branch instead of HEAD
+	statsCmd.Flags().BoolVar(&allBranchesFlag, "all-branches", false, "Walk the history of every branch, remote branch and tag instead of HEAD, like git log --all")
+
+	// Add the head flag to walk the history from another revision than HEAD
+	statsCmd.Flags().StringVar(&headRevision, "head", "", "The branch, tag or commit to walk the history from (e.g. origin/develop, v2.0), default is HEAD")
+
+	// Add the flag to count the commits of the submodules too
+	statsCmd.Flags().BoolVar(&submodulesFlag, "submodules", false, "Also count the commits of the checked-out submodules (each commit once)")
+
+	// Add the flag to count the commits credited through Co-authored-by trailers
+	statsCmd.Flags().BoolVar(&coAuthorsFlag, "co-authors", false, "Also count the commits whose Co-authored-by trailers match the filtered emails")
+
 	// Add the self-flag to use the current user's email from git config
 	statsCmd.Flags().BoolVarP(&selfFlag, "self", "s", false, "Use the current user's email from git config")
+	statsCmd.Flags().BoolVar(&autoSelfFlag, "auto-self", false, "Use the email most commits of the analyzed repositories were authored with")
+	statsCmd.Flags().StringVar(&selfScope, "self-scope", commands.ScopeGlobal, "The git config scope --self reads the email from ("+strings.Join(commands.SelfScopes, ", ")+"), local reads the first --path")
 
 	// Add flags to show the commit count on cells and days of the month
 	statsCmd.Flags().BoolVarP(&showCommitCountFlag, "count", "c", false, "Display the number of commits on each cell")
+	statsCmd.Flags().IntVar(&annotateAbove, "annotate-above", 0, "Only display the counts higher than this number, implies --count")
+	statsCmd.Flags().IntVar(&maxCellValue, "max-cell-value", stats.DefaultMaxCellValue, "The highest count displayed with --count, higher counts display as '<max>+'")
 	statsCmd.Flags().BoolVarP(&showDaysOfMonthFlag, "days", "d", false, "Display the days of the month on the graph calendar")
 
+	// Add the color scheme flag to select the palette of the graph
+	statsCmd.Flags().StringVar(&colorScheme, "color-scheme", stats.DefaultColorScheme, "The color scheme of the graph: github, halloween or dracula")
+	statsCmd.Flags().StringVar(&colorsFlag, "colors", "", "The color capability of the terminal: 8, 16, 256 or truecolor (default: detected from $TERM and $COLORTERM)")
+
+	// Add the relative flag to scale the colors to the busiest day
+	statsCmd.Flags().BoolVar(&relativeFlag, "relative", false, "Scale the colors to the busiest day of the graph (better contrast, but not comparable across repositories)")
+	statsCmd.Flags().BoolVar(&alignToTodayFlag, "align-to-today", false, "Make the current week the last column of the graph, ending with today")
+	statsCmd.Flags().BoolVar(&forceColorFlag, "force-color", false, "Always print colors, even when not writing to a terminal or with NO_COLOR set (e.g. for less -R)")
+	statsCmd.Flags().BoolVar(&noColorFlag, "no-color", false, "Print the graph without colors, telling the levels apart with glyphs")
+	statsCmd.Flags().StringVar(&label, "label", "", "A heading printed above the graph (with --per-author, followed by each email)")
+	statsCmd.Flags().BoolVar(&denseFlag, "dense", false, "Display one compact strip of weekly commits per repository instead of the graph")
+	statsCmd.Flags().IntVar(&topN, "top-n", 0, "Only display the N most active repositories in --dense and --breakdown, 0 displays all")
+	statsCmd.Flags().BoolVar(&weekNumbersFlag, "week-numbers", false, "Display the ISO week number of each column below the month labels")
+	statsCmd.Flags().BoolVar(&compactFlag, "compact", false, "Draw each cell with a single character, halving the width of the graph")
+	statsCmd.Flags().BoolVar(&autoCompactFlag, "auto-compact", false, "Switch to --compact when the graph is wider than the terminal, instead of warning")
+	statsCmd.Flags().BoolVar(&monoFlag, "mono", false, "Draw each cell with a character of the ramp ' .:-=+*#%@' by its number of commits, without any color (e-ink and monochrome terminals)")
+	statsCmd.Flags().BoolVar(&binaryFlag, "binary", false, "Color every day with commits the same, whatever their number, and count active days in the summary (habit tracking)")
+	statsCmd.Flags().BoolVar(&deltaFlag, "delta", false, "Color each day by the change of commits from the day before (green for ramps, red for drops)")
+	statsCmd.Flags().BoolVar(&collapseEmptyRowsFlag, "collapse-empty-rows", false, "Hide the days of the week without commits in the whole graph (e.g. weekends)")
+	statsCmd.Flags().BoolVar(&stripeFlag, "stripe", false, "Draw a heavier separator before the first column of each month, to follow the columns of the graph")
+	statsCmd.Flags().IntVar(&weeks, "weeks", 0, "The exact number of week columns of the graph, padding the oldest with empty weeks or leaving them out (default is the weeks of the window)")
+	statsCmd.Flags().BoolVar(&weeklyFlag, "weekly", false, "Display one cell per week with the week's total instead of one per day")
+	statsCmd.Flags().BoolVar(&graphOnlyFlag, "graph-only", false, "Print the cell rows only, without month header, day labels, legend or summary")
+	statsCmd.Flags().IntVarP(&jobs, "jobs", "j", 0, "The number of repositories processed concurrently (default is the number of CPUs)")
+	statsCmd.Flags().IntVar(&retries, "retries", 0, "The number of times opening a repository is tried again on transient errors (e.g. of network filesystems), with backoff")
+	statsCmd.Flags().BoolVar(&requireFullFlag, "require-full", false, "Fail on shallow clones instead of warning that their commits may be partial")
+	statsCmd.Flags().StringVar(&outputFormat, "format", stats.FormatTerminal, "The output format ("+strings.Join(stats.Formats, ", ")+")")
+	statsCmd.Flags().BoolVar(&clipboardFlag, "clipboard", false, "Copy the graph (or the json, csv or jsonl output) to the clipboard instead of printing it")
+	statsCmd.Flags().BoolVar(&noPagerFlag, "no-pager", false, "Don't page the output through $PAGER (or less -R) when it is longer than the terminal")
+	statsCmd.Flags().StringVarP(&outputFile, "output", "o", "", "The file to write the output of a --format other than terminal to (default is the standard output)")
+	statsCmd.Flags().BoolVar(&jsonlFlag, "jsonl", false, "Stream one JSON object per line, per day or per repository with --breakdown (same as --format jsonl)")
+	statsCmd.Flags().BoolVar(&anonymizeFlag, "anonymize", false, "Replace the emails and repository paths of the output with pseudonyms, for sharing")
+	statsCmd.Flags().StringVar(&groupBy, "group-by", "", "Print the commits per period or remote host instead of the graph ("+strings.Join(stats.GroupByModes, ", ")+")")
+
+	// Add flags to render the cells with characters, for screen readers and color-blind users
+	statsCmd.Flags().BoolVar(&glyphsFlag, "glyphs", false, "Draw a distinct character per intensity level in each cell")
+	statsCmd.Flags().StringVar(&emptyChar, "empty-char", "", "The character to draw in cells without commits (default is a space)")
+
+	// Add the graph style flag to draw solid blocks where background colors render poorly
+	statsCmd.Flags().StringVar(&graphStyle, "graph-style", stats.StyleCells, "The style of the graph: cells (colored backgrounds) or blocks (solid colored blocks)")
+
+	// Add flags to print the summary below the graph
+	statsCmd.Flags().BoolVar(&showSummaryFlag, "summary", false, "Display totals and streaks below the graph")
+	statsCmd.Flags().IntVar(&goal, "goal", 0, "A daily number of commits to aim for, today's progress towards it is printed below the graph (0 disables it)")
+	statsCmd.Flags().BoolVar(&porcelainFlag, "porcelain", false, "Print a single stable line for scripts instead of the graph: total=N streak=N longest=N active_days=N")
+	statsCmd.Flags().BoolVar(&betweenCommitsFlag, "between-commits", false, "Display the average and median time between consecutive commits below the graph")
+	statsCmd.Flags().BoolVar(&burstsFlag, "bursts", false, "Add the share of commits made within --burst-window of another one to the summary (with --summary or --format json), suggesting amend or fixup churn")
+	statsCmd.Flags().DurationVar(&burstWindow, "burst-window", stats.DefaultBurstWindow, "The time within which consecutive commits are counted as a burst with --bursts")
+	statsCmd.Flags().BoolVar(&byTimezoneFlag, "by-timezone", false, "Display the distribution of the UTC offsets the commits were authored in below the graph")
+	statsCmd.Flags().BoolVar(&excludeWeekendsFlag, "exclude-weekends", false, "Don't count the commits authored on Saturdays and Sundays, in the graph nor the totals")
+	statsCmd.Flags().BoolVar(&workingDaysOnlyFlag, "working-days-only", false, "Don't break streaks on weekends without commits (holidays are not considered)")
+
+	// Add the flag to print the distribution of commit sizes below the graph
+	statsCmd.Flags().BoolVar(&showSizesFlag, "sizes", false, "Display the distribution of commit sizes below the graph (slow, diffs every commit)")
+
+	// Add flags to print the commits of each repository below the graph
+	statsCmd.Flags().BoolVar(&showBreakdownFlag, "breakdown", false, "Display the commits of each repository below the graph")
+	statsCmd.Flags().StringVar(&repoType, "repo-type", scanner.RepoTypeAll, "The repositories to analyze: remote (with a remote), local (without) or all")
+	statsCmd.Flags().BoolVar(&repoNameFlag, "repo-name", false, "Show the repositories of the breakdown and the json output by remote (e.g. github.com/me/foo) instead of path")
+	statsCmd.Flags().StringVar(&sortRepos, "sort-repos", stats.SortByCommits, "The order of the breakdown: commits, path or recent")
+
+	// Complete the flags with sensible values
+	_ = statsCmd.MarkFlagDirname("path")
+	_ = statsCmd.MarkFlagFilename("emails-file")
+	_ = statsCmd.MarkFlagFilename("combine-authors")
+	_ = statsCmd.RegisterFlagCompletionFunc("email", cobra.NoFileCompletions)
+	_ = statsCmd.RegisterFlagCompletionFunc("exclude-email", cobra.NoFileCompletions)
+	_ = statsCmd.RegisterFlagCompletionFunc("repo-type", cobra.FixedCompletions(scanner.RepoTypes, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("graph-style", cobra.FixedCompletions(stats.Styles, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("color-scheme", cobra.FixedCompletions(stats.ColorSchemeNames(), cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("colors", cobra.FixedCompletions(stats.ColorDepths, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("sort-repos", cobra.FixedCompletions(stats.RepoOrders, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(stats.Formats, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("self-scope", cobra.FixedCompletions(commands.SelfScopes, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("group-by", cobra.FixedCompletions(stats.GroupByModes, cobra.ShellCompDirectiveNoFileComp))
+	_ = statsCmd.RegisterFlagCompletionFunc("diff", cobra.FixedCompletions(stats.CompareModes, cobra.ShellCompDirectiveNoFileComp))
+
 	// Make stats the default command when no subcommand is specified
 	cobra.OnInitialize(func() {
 		// If no subcommand is specified, run the stats command
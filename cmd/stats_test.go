@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateStatsFlags tests that incompatible flags are rejected with an error
+func TestValidateStatsFlags(t *testing.T) {
+	// Keep the configuration file of the user out of the test
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+
+	// The valid defaults are accepted
+	if err := validateStatsFlags(statsCmd); err != nil {
+		t.Fatalf("Unexpected error for the defaults: %v", err)
+	}
+
+	// Test case 1: The count and the days don't fit in the same cell
+	showCommitCountFlag, showDaysOfMonthFlag = true, true
+	t.Cleanup(func() { showCommitCountFlag, showDaysOfMonthFlag = false, false })
+
+	err := validateStatsFlags(statsCmd)
+	if err == nil || !strings.Contains(err.Error(), "--count and --days") {
+		t.Errorf("Expected an error for --count with --days, got %v", err)
+	}
+
+	// Test case 2: Unknown values are reported
+	showCommitCountFlag, showDaysOfMonthFlag = false, false
+	previousStyle := graphStyle
+	graphStyle = "dots"
+	t.Cleanup(func() { graphStyle = previousStyle })
+
+	err = validateStatsFlags(statsCmd)
+	if err == nil || !strings.Contains(err.Error(), `unknown graph style "dots"`) {
+		t.Errorf("Expected an error for an unknown style, got %v", err)
+	}
+
+	// Test case 3: A threshold turns the counts on, but its conflicts name it rather than --count
+	graphStyle = previousStyle
+	annotateAbove, showDaysOfMonthFlag = 3, true
+	t.Cleanup(func() { annotateAbove, showDaysOfMonthFlag, showCommitCountFlag = 0, false, false })
+
+	err = validateStatsFlags(statsCmd)
+	if err == nil || !strings.Contains(err.Error(), "--annotate-above flag cannot be used with --days") {
+		t.Errorf("Expected an error for --annotate-above with --days, got %v", err)
+	}
+
+	// Test case 4: The bursts are part of the summary, so they need one to show in
+	annotateAbove, showDaysOfMonthFlag, showCommitCountFlag = 0, false, false
+	burstsFlag = true
+	t.Cleanup(func() { burstsFlag, showSummaryFlag = false, false })
+
+	err = validateStatsFlags(statsCmd)
+	if err == nil || !strings.Contains(err.Error(), "--bursts flag requires --summary") {
+		t.Errorf("Expected an error for --bursts without --summary, got %v", err)
+	}
+	showSummaryFlag = true
+	if err := validateStatsFlags(statsCmd); err != nil {
+		t.Errorf("Unexpected error for --bursts with --summary: %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/acheddir/git-contrib/pkg/progress"
+	"golang.org/x/term"
+)
+
+// noProgressFlag disables the progress line of every command
+var noProgressFlag bool
+
+// isTerminal reports whether a file is a terminal rather than a pipe or a regular file.
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// terminalWidth returns the width of the terminal a file is, or 0 if it isn't a terminal.
+func terminalWidth(f *os.File) int {
+	width, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// terminalHeight returns the height of the terminal a file is, or 0 if it isn't a terminal.
+func terminalHeight(f *os.File) int {
+	_, height, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return height
+}
+
+// newProgress returns the reporter of the progress of long operations, printed on the standard error
+// so the output stays clean. It is nil, reporting nothing, with --no-progress or when the standard
+// error isn't a terminal.
+func newProgress() *progress.Reporter {
+	if !progress.Enabled(noProgressFlag, isTerminal(os.Stderr)) {
+		return nil
+	}
+	return progress.New(os.Stderr)
+}
@@ -0,0 +1,68 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard tool is installed.
+var ErrUnavailable = errors.New("no clipboard tool found, install xclip, xsel or wl-clipboard (wl-copy)")
+
+// Command returns the command line of the clipboard tool of the system:
+// pbcopy on macOS, clip on Windows, and wl-copy (Wayland), xclip or xsel elsewhere.
+//
+// Returns:
+//   - []string: The clipboard tool and its arguments
+//   - error: ErrUnavailable if no clipboard tool is installed
+func Command() ([]string, error) {
+	return command(runtime.GOOS, os.Getenv("WAYLAND_DISPLAY") != "", exec.LookPath)
+}
+
+// command picks the clipboard tool of the given system among the installed ones, see Command.
+func command(goos string, wayland bool, lookPath func(string) (string, error)) ([]string, error) {
+	var candidates [][]string
+	switch goos {
+	case "darwin":
+		candidates = [][]string{{"pbcopy"}}
+	case "windows":
+		candidates = [][]string{{"clip"}}
+	default:
+		// wl-copy only works in a Wayland session, xclip and xsel with an X server (or XWayland)
+		if wayland {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		candidates = append(candidates, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+
+	for _, candidate := range candidates {
+		if _, err := lookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, ErrUnavailable
+}
+
+// Copy copies text to the system clipboard, see Command.
+//
+// Parameters:
+//   - text: The text to copy
+//
+// Returns:
+//   - error: ErrUnavailable if no clipboard tool is installed, or an error if the tool fails
+func Copy(text string) error {
+	args, err := Command()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to copy to the clipboard with %s: %w %s", args[0], err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package clipboard
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// TestCommand tests the command function
+func TestCommand(t *testing.T) {
+	// installed returns a lookPath finding only the given tools
+	installed := func(tools ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, tool := range tools {
+				if tool == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	testCases := []struct {
+		goos     string
+		wayland  bool
+		tools    []string
+		expected []string
+	}{
+		{"darwin", false, []string{"pbcopy"}, []string{"pbcopy"}},
+		{"windows", false, []string{"clip"}, []string{"clip"}},
+		{"linux", true, []string{"wl-copy", "xclip"}, []string{"wl-copy"}},
+		{"linux", false, []string{"wl-copy", "xclip"}, []string{"xclip", "-selection", "clipboard"}},
+		{"freebsd", false, []string{"xsel"}, []string{"xsel", "--clipboard", "--input"}},
+	}
+
+	for _, tc := range testCases {
+		result, err := command(tc.goos, tc.wayland, installed(tc.tools...))
+		if err != nil {
+			t.Errorf("command(%q, %v) with %v: unexpected error: %v", tc.goos, tc.wayland, tc.tools, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("command(%q, %v) with %v: expected %v, got %v", tc.goos, tc.wayland, tc.tools, tc.expected, result)
+		}
+	}
+
+	// No clipboard tool installed
+	if _, err := command("linux", true, installed()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
@@ -1,27 +1,1265 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/clipboard"
+	"github.com/acheddir/git-contrib/pkg/fileutil"
+	"github.com/acheddir/git-contrib/pkg/pager"
+	"github.com/acheddir/git-contrib/pkg/progress"
+	"github.com/acheddir/git-contrib/pkg/scanner"
 	"github.com/acheddir/git-contrib/pkg/stats"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 )
 
+// StatsOptions holds the settings of the stats command.
+type StatsOptions struct {
+	// Filter decides which commits to count
+	Filter stats.Filter
+	// Directories lists the directories to analyze (should be Git repositories)
+	Directories []string
+	// Render controls how the graph is rendered
+	Render stats.RenderOptions
+	// Label is printed as a heading above the graph (if empty, none, except with PerAuthor
+	// where each graph is labeled with its email)
+	Label string
+	// LabelAuthor colors the label after that author, see stats.AuthorColor (if empty, uncolored)
+	LabelAuthor string
+	// ShowSummary prints totals and streaks below the graph
+	ShowSummary bool
+	// Goal prints a nudge comparing the commits of today to this daily goal below the graph, or below
+	// the message of an empty graph and the outputs printed instead of the graph (if zero, none)
+	Goal int
+	// Porcelain prints a single line of key=value totals and streaks for scripts instead of the graph,
+	// see stats.WritePorcelain
+	Porcelain bool
+	// BetweenCommits prints the average and median time between consecutive commits below the graph
+	BetweenCommits bool
+	// BurstWindow adds the share of commits made within this window of another one to the summary,
+	// suggesting amend or fixup churn (if zero, not printed)
+	BurstWindow time.Duration
+	// ByTimezone prints the number of commits authored in each UTC offset below the graph
+	ByTimezone bool
+	// Tags counts the annotated tags created (by tagger) instead of the commits
+	Tags bool
+	// Reflog counts the commits created locally from the HEAD reflog instead of the history (experimental)
+	Reflog bool
+	// ShowSizes prints the distribution of commit sizes below the graph (slow, it diffs every commit)
+	ShowSizes bool
+	// ShowBreakdown prints the commits of each repository below the graph
+	ShowBreakdown bool
+	// TerminalWidth is the width of the terminal the graph is printed to (if zero, unknown)
+	TerminalWidth int
+	// AutoCompact switches to compact cells when the graph is wider than the terminal, instead of warning
+	AutoCompact bool
+	// Dense prints one compact strip per repository instead of the graph, see stats.PrintSmallMultiples
+	Dense bool
+	// TopN keeps the most active repositories in the dense view and the breakdown (if zero, all of them)
+	TopN int
+	// SortRepos orders the breakdown, stats.SortByCommits, stats.SortByPath or stats.SortByRecent
+	SortRepos string
+	// WorkingDaysOnly keeps quiet weekends from breaking streaks
+	WorkingDaysOnly bool
+	// PerAuthor renders one labeled graph per filtered email instead of a combined one
+	PerAuthor bool
+	// QuietEmpty renders the empty graph instead of a message when no commits are found
+	QuietEmpty bool
+	// StrictEmail fails when no commit of the analyzed repositories matches the filtered emails,
+	// so a mistyped address doesn't go unnoticed as an empty graph
+	StrictEmail bool
+	// Anonymize replaces the emails and repository paths of the output with pseudonyms
+	Anonymize bool
+	// DetailDate lists the commits of that day instead of the graph (if zero, shows the graph)
+	DetailDate time.Time
+	// ShortHashLength is the number of characters of the hashes listed with DetailDate (if zero, stats.DefaultShortHashLength)
+	ShortHashLength int
+	// Jobs is the number of repositories processed concurrently (if zero, the number of CPUs)
+	Jobs int
+	// Retries is the number of times opening a repository is tried again on transient errors
+	Retries int
+	// RepoNames shows the repositories of the breakdown and the JSON output by remote name instead of path
+	RepoNames bool
+	// Head is the revision the history of each directory is walked from (if empty, HEAD)
+	Head string
+	// AllBranches walks the history of every branch, remote branch and tag instead of HEAD
+	AllBranches bool
+	// EndAtHead ends the graph on the day of the most recent matched commit instead of today, see stats.Options.EndAtHead
+	EndAtHead bool
+	// Range is the window of the graph (if empty, stats.DefaultGraphRange)
+	Range stats.GraphRange
+	// Submodules also counts the commits of the checked-out submodules of the directories
+	Submodules bool
+	// RequireFull fails on shallow clones instead of warning that their commits may be partial
+	RequireFull bool
+	// Format is the output format, one of stats.Formats (if empty, terminal)
+	Format string
+	// Output is the file the JSON or CSV output is written to (if empty, the standard output)
+	Output string
+	// Progress reports the progress of the analysis (if nil, nothing is reported)
+	Progress *progress.Reporter
+	// Clipboard copies the output (graph, JSON or CSV) to the system clipboard instead of printing it
+	Clipboard bool
+	// Pager prints the output through the pager when it is longer than TerminalHeight
+	Pager bool
+	// TerminalHeight is the height of the terminal the output is printed to (if zero, unknown)
+	TerminalHeight int
+	// GroupBy prints the commits per period or host instead of the graph, stats.GroupByQuarter
+	// or stats.GroupByHost (if empty, shows the graph)
+	GroupBy string
+	// Compare prints the activity of two periods side by side instead of the graph,
+	// see stats.ParseComparison (if empty, shows the graph)
+	Compare string
+	// Out is where the output is printed (if nil, the standard output)
+	Out io.Writer
+}
+
 // Stats process Git repositories and display commit statistics.
-// If an email is provided, it filters commits by that email address.
+// If the filter lists email addresses, only commits authored by one of them are counted.
 // If no email is provided, it includes commits from all users.
 //
 // Parameters:
-//   - email: The email address to filter commits by (if empty, includes all commits)
-//   - directory: The directory to analyze (should be a Git repository)
-//   - showCommitCount: Whether to display the number of commits on each cell
-//   - showDaysOfMonth: Whether to display the days of the month on the graph calendar
+//   - opts: The settings of the stats command
 //
 // Returns:
 //   - error: An error if any occurred during processing
-func Stats(email string, directory string, showCommitCount bool, showDaysOfMonth bool) error {
-	commits, err := stats.ProcessRepositories(email, directory)
+func Stats(opts StatsOptions) error {
+	if opts.Out == nil {
+		opts.Out = os.Stdout
+	}
+
+	// Render everything as usual, then copy it instead of printing it
+	if opts.Clipboard {
+		opts.Clipboard = false
+		return copyOutput(opts.Out, func(w io.Writer) error {
+			opts.Out = w
+			return Stats(opts)
+		})
+	}
+
+	// Render everything as usual, then page it if it doesn't fit on the screen
+	if opts.Pager {
+		opts.Pager = false
+		return pageOutput(opts.Out, func(w io.Writer) error {
+			opts.Out = w
+			return Stats(opts)
+		}, opts.TerminalHeight)
+	}
+
+	// Make clear the graph only covers a subdirectory
+	if opts.Filter.Subdir != "" && opts.Label == "" {
+		opts.Label = fmt.Sprintf("Contributions to %s/", opts.Filter.Subdir)
+	}
+
+	// Render one graph per author, stacked
+	if opts.PerAuthor && len(opts.Filter.Emails) > 0 {
+		for i, email := range opts.Filter.Emails {
+			authorOpts := opts
+			authorOpts.PerAuthor = false
+			authorOpts.Filter.Emails = []string{email}
+
+			if i > 0 {
+				fmt.Fprintf(opts.Out, "\n")
+			}
+			author := email
+			if opts.Anonymize {
+				author = stats.AnonymizeEmail(email)
+			}
+			authorOpts.Label = "Contributions of " + author
+			authorOpts.LabelAuthor = author
+			if opts.Label != "" {
+				authorOpts.Label = fmt.Sprintf("%s (%s)", opts.Label, author)
+			}
+
+			if err := Stats(authorOpts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	result, err := stats.Analyze(stats.Options{
+		Filter:          opts.Filter,
+		Repositories:    opts.Directories,
+		WorkingDaysOnly: opts.WorkingDaysOnly,
+		RequireFull:     opts.RequireFull,
+		Jobs:            opts.Jobs,
+		CommitSizes:     opts.ShowSizes,
+		Tags:            opts.Tags,
+		Reflog:          opts.Reflog,
+		Submodules:      opts.Submodules,
+		Head:            opts.Head,
+		AllBranches:     opts.AllBranches,
+		EndAtHead:       opts.EndAtHead,
+		Range:           opts.Range,
+		RepoNames:       opts.RepoNames,
+		Progress:        opts.Progress,
+		Retries:         opts.Retries,
+		BurstWindow:     opts.BurstWindow,
+	})
+	if err != nil {
+		return err
+	}
+
+	// The graph covers the analysis window
+	opts.Render.Until = result.Until
+	opts.Render.Range = result.Range
+
+	// Scrub the metadata before anything is printed
+	if opts.Anonymize {
+		stats.AnonymizeRepositories(result.Repositories)
+	}
+
+	// Warn about incomplete histories, without polluting the output
+	for _, repo := range result.Repositories {
+		if repo.Shallow {
+			fmt.Fprintf(os.Stderr, "Warning: %s is a shallow clone, its commits may be partial (use --require-full to fail instead)\n", repo.Path)
+		}
+	}
+
+	// Fail on an email matching nothing rather than printing an empty graph
+	if opts.StrictEmail && result.Summary.Total == 0 {
+		return fmt.Errorf("no commits of %s found in %s of the analyzed repositories, check the email address",
+			strings.Join(opts.Filter.Emails, ", "), result.Range)
+	}
+
+	// List the commits of a single day instead of the graph
+	if !opts.DetailDate.IsZero() {
+		daysAgo := stats.CountDaysSinceDate(opts.DetailDate, result.Range)
+		if daysAgo < 0 || daysAgo == stats.OutOfRange {
+			return fmt.Errorf("%s is outside of the graph window", opts.DetailDate.Format("2006-01-02"))
+		}
+
+		stats.PrintCommitDetails(opts.Out, opts.DetailDate, result.Commits[daysAgo], opts.ShortHashLength)
+		return nil
+	}
+
+	// Print the summary line for scripts instead of the graph
+	if opts.Porcelain {
+		return stats.WritePorcelain(opts.Out, result)
+	}
+
+	// Write machine-readable outputs instead of the graph
+	switch opts.Format {
+	case stats.FormatJSON, stats.FormatCSV, stats.FormatJSONL, stats.FormatSVG, stats.FormatPNG, stats.FormatHTML:
+		if opts.ShowBreakdown {
+			stats.SortRepositories(result.Repositories, opts.SortRepos)
+		}
+		return writeResult(result, opts)
+	}
+
+	if opts.Label != "" && !opts.Render.GraphOnly {
+		label := opts.Label
+		if opts.LabelAuthor != "" {
+			label = opts.Render.ColorAuthor(label, opts.LabelAuthor)
+		}
+		fmt.Fprintf(opts.Out, "%s\n", label)
+	}
+
+	// Compare two periods instead of the graph, even without commits as 0 against 0 is an answer too
+	if opts.Compare != "" {
+		previous, current, err := stats.ParseComparison(opts.Compare, result.Range)
+		if err != nil {
+			return err
+		}
+		stats.PrintComparison(opts.Out, stats.ComparePeriods(result.Counts, previous, current))
+		printGoal(result, opts)
+		return nil
+	}
+
+	// Explain an empty graph rather than leaving the user wondering
+	if result.Summary.Total == 0 && !opts.QuietEmpty && !opts.Render.GraphOnly {
+		authors := "any author"
+		if len(opts.Filter.Emails) > 0 && opts.Anonymize {
+			authors = strings.Join(stats.AnonymizeEmails(opts.Filter.Emails), ", ")
+		} else if len(opts.Filter.Emails) > 0 {
+			authors = strings.Join(opts.Filter.Emails, ", ")
+		}
+		noun := "commits"
+		if opts.Tags {
+			noun = "tags"
+		}
+		if opts.Reflog {
+			noun = "commits in the reflog"
+		}
+		fmt.Fprintf(opts.Out, "No %s found for %s in %s.\n", noun, authors, result.Range)
+		printGoal(result, opts)
+		return nil
+	}
+
+	// Print one strip per repository instead of the graph
+	if opts.Dense {
+		stats.PrintSmallMultiples(opts.Out, stats.TopRepositories(result.Repositories, opts.TopN), opts.Render)
+		printGoal(result, opts)
+		return nil
+	}
+
+	// Print totals per period instead of the graph
+	switch opts.GroupBy {
+	case stats.GroupByQuarter:
+		stats.PrintPeriods(opts.Out, stats.CountByQuarter(result.Counts, result.Range))
+		printGoal(result, opts)
+		return nil
+	case stats.GroupByHost:
+		stats.PrintHosts(opts.Out, stats.CountByHost(result.Repositories))
+		printGoal(result, opts)
+		return nil
+	}
+
+	// A graph wider than the terminal wraps and looks broken
+	if opts.TerminalWidth > 0 && opts.Render.GridWidth() > opts.TerminalWidth {
+		if opts.AutoCompact && !opts.Render.Compact && opts.Render.CanCompact() {
+			opts.Render.Compact = true
+		}
+		if width := opts.Render.GridWidth(); width > opts.TerminalWidth {
+			fmt.Fprintf(os.Stderr, "Warning: The graph is %d columns wide but the terminal only %d, use --compact (or --auto-compact) or a wider window\n", width, opts.TerminalWidth)
+		}
+	}
+
+	if opts.Render.Weekly {
+		// Relative levels are computed from the weekly totals
+		if opts.Render.Relative {
+			opts.Render.MaxCount = stats.MaxCount(stats.WeeklyCounts(result.Counts, opts.Render))
+		}
+		stats.PrintWeeklyStats(opts.Out, result.Counts, opts.Render)
+	} else {
+		if opts.Render.Relative {
+			opts.Render.MaxCount = stats.MaxCount(result.Counts)
+		}
+
+		// Color the change from the day before instead of the commits,
+		// or only whether each day has commits
+		counts := result.Counts
+		if opts.Render.Delta {
+			counts = stats.Deltas(counts, result.Range)
+		}
+		if opts.Render.Binary {
+			counts = stats.BinaryCounts(counts)
+		}
+		stats.PrintCommitsStats(opts.Out, counts, opts.Render)
+	}
+
+	// Nothing but the cell rows, for piping or embedding
+	if opts.Render.GraphOnly {
+		return nil
+	}
+
+	// Explain the glyphs, since they don't rely on color, the relative levels, the diverging scale and the binary cells
+	if opts.Render.Relative || opts.Render.Delta || opts.Render.Binary || opts.Render.Mono || (opts.Render.Style != stats.StyleBlocks && (opts.Render.Glyphs || opts.Render.EmptyCell != "")) {
+		stats.PrintLegend(opts.Out, opts.Render)
+	}
+
+	if opts.ShowSummary && opts.Render.Binary {
+		stats.PrintBinarySummary(opts.Out, result.Summary, result.Range)
+	} else if opts.ShowSummary {
+		stats.PrintSummary(opts.Out, result.Summary)
+	}
+
+	printGoal(result, opts)
+
+	if opts.BetweenCommits {
+		stats.PrintIntervals(opts.Out, result.Intervals)
+	}
+
+	if opts.ByTimezone {
+		stats.PrintTimezones(opts.Out, stats.CountByTimezone(result.Commits))
+	}
+
+	if opts.ShowSizes {
+		stats.PrintSizeHistogram(opts.Out, stats.SizeHistogram(result.Commits))
+	}
+
+	if opts.ShowBreakdown {
+		repos := result.Repositories
+		if opts.TopN > 0 {
+			repos = stats.TopRepositories(repos, opts.TopN)
+		}
+		stats.SortRepositories(repos, opts.SortRepos)
+		stats.PrintBreakdown(opts.Out, repos)
+	}
+
+	return nil
+}
+
+// printGoal prints the nudge of the daily goal, if any, below the graph or what is printed instead of it,
+// so a day without commits still gets its warning.
+//
+// Parameters:
+//   - result: The result of the analysis, giving the commits of today
+//   - opts: The options of the stats command, giving the goal and the output
+func printGoal(result *stats.Result, opts StatsOptions) {
+	if opts.Goal > 0 {
+		fmt.Fprintf(opts.Out, "\n%s\n", stats.GoalMessage(result.Counts[0], opts.Goal))
+	}
+}
+
+// writeResult writes the result in a machine-readable format to a file, or to the output of the options.
+//
+// Parameters:
+//   - result: The result of the analysis
+//   - opts: The options of the stats command, giving the format, the output file and the breakdown mode
+//
+// Returns:
+//   - error: An error if the result couldn't be written
+func writeResult(result *stats.Result, opts StatsOptions) error {
+	write := func(w io.Writer) error {
+		switch opts.Format {
+		case stats.FormatCSV:
+			return stats.WriteCSV(w, result)
+		case stats.FormatJSONL:
+			return stats.WriteJSONL(w, result, opts.ShowBreakdown, opts.Render)
+		case stats.FormatSVG:
+			return stats.WriteSVG(w, result, opts.Render)
+		case stats.FormatPNG:
+			return stats.WritePNG(w, result, opts.Render)
+		case stats.FormatHTML:
+			return stats.WriteHTML(w, result, opts.Render)
+		}
+		return stats.WriteJSON(w, result, opts.Render)
+	}
+
+	if opts.Output == "" {
+		return write(opts.Out)
+	}
+	return writeFile(opts.Output, write)
+}
+
+// writeFile creates (or truncates) the file at path and writes to it with write.
+func writeFile(path string, write func(w io.Writer) error) (err error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer func() {
+		if closeErr := f.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close %s: %w", path, closeErr)
+		}
+	}()
+
+	return write(f)
+}
+
+// copyOutput copies what run prints to the writer it is given to the clipboard,
+// then confirms it on out. Warnings printed on the standard error still show.
+func copyOutput(out io.Writer, run func(w io.Writer) error) error {
+	// Fail before the (slow) analysis when there is no clipboard to copy to
+	if _, err := clipboard.Command(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := run(&buf); err != nil {
+		return err
+	}
+
+	output := buf.String()
+	if err := clipboard.Copy(output); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Copied %d lines to the clipboard.\n", strings.Count(output, "\n"))
+	return nil
+}
+
+// pageOutput prints what run prints to the writer it is given on out, through the pager
+// when it doesn't fit on a screen of the given height, or directly otherwise.
+// Without a pager, the output is printed directly too.
+func pageOutput(out io.Writer, run func(w io.Writer) error, height int) error {
+	var buf bytes.Buffer
+	if err := run(&buf); err != nil {
+		return err
+	}
+
+	output := buf.String()
+	if pager.Needed(output, height) {
+		if err := pager.Page(out, output); !errors.Is(err, pager.ErrUnavailable) {
+			return err
+		}
+	}
+	_, err := io.WriteString(out, output)
+	return err
+}
+
+// LoadEmails reads a list of email addresses from a file, one per line.
+// Surrounding whitespace is trimmed, blank lines are ignored and duplicates are removed.
+//
+// Parameters:
+//   - path: The path to the file listing the email addresses
+//
+// Returns:
+//   - []string: The deduplicated email addresses, in file order
+//   - error: An error if the file does not exist or can't be read
+func LoadEmails(path string) ([]string, error) {
+	// ParseFileLines creates missing files, so check for existence first
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read emails file %s: %w", path, err)
+	}
+
+	lines, err := fileutil.ParseFileLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read emails file %s: %w", path, err)
+	}
+
+	var emails []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		emails = fileutil.JoinSlices([]string{line}, emails)
+	}
+
+	return emails, nil
+}
+
+// LoadIdentities reads a file combining the emails of authors, one author per line
+// as "canonical@example.com: alias1@example.com, alias2@example.com".
+// Blank lines and lines starting with # are ignored.
+//
+// Parameters:
+//   - path: The path to the file
+//
+// Returns:
+//   - map[string]string: The canonical email of each normalized email, for stats.Filter.Identities
+//   - error: An error if the file can't be read, a line is malformed or an alias has two authors
+func LoadIdentities(path string) (map[string]string, error) {
+	// ParseFileLines creates missing files, so check for existence first
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read authors file %s: %w", path, err)
+	}
+
+	lines, err := fileutil.ParseFileLines(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authors file %s: %w", path, err)
+	}
+
+	identities := make(map[string]string)
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		canonical, aliases, ok := strings.Cut(line, ":")
+		canonical = strings.TrimSpace(canonical)
+		if !ok || canonical == "" {
+			return nil, fmt.Errorf("invalid line %d of authors file %s, expected 'canonical@example.com: alias@example.com, ...'", i+1, path)
+		}
+
+		// The canonical email is an alias of itself, so its case variants are combined too
+		for _, alias := range append([]string{canonical}, strings.Split(aliases, ",")...) {
+			alias = stats.NormalizeEmail(alias)
+			if alias == "" {
+				continue
+			}
+			if previous, ok := identities[alias]; ok && previous != canonical {
+				return nil, fmt.Errorf("%s is combined with both %s and %s in authors file %s", alias, previous, canonical, path)
+			}
+			identities[alias] = canonical
+		}
+	}
+
+	return identities, nil
+}
+
+// Git configuration scopes --self can read the user email from
+const (
+	ScopeGlobal = "global"
+	ScopeLocal  = "local"
+	ScopeSystem = "system"
+)
+
+// SelfScopes lists the supported configuration scopes, for validation and completion.
+var SelfScopes = []string{ScopeGlobal, ScopeLocal, ScopeSystem}
+
+// SelfEmail reads the user email (user.email) from the Git configuration of the given scope.
+// The configuration files are read directly, so the git binary isn't needed.
+//
+// Parameters:
+//   - scope: The configuration scope, ScopeGlobal, ScopeLocal or ScopeSystem
+//   - dir: The repository whose configuration is read with ScopeLocal
+//
+// Returns:
+//   - string: The user email, empty if unset
+//   - error: An error if the configuration couldn't be read
+func SelfEmail(scope string, dir string) (string, error) {
+	var cfg *config.Config
+	var err error
+
+	switch scope {
+	case ScopeLocal:
+		var repo *git.Repository
+		repo, err = git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+		if err != nil {
+			return "", fmt.Errorf("failed to open repository at %s: %w", dir, err)
+		}
+		cfg, err = repo.Config()
+	case ScopeSystem:
+		cfg, err = config.LoadConfig(config.SystemScope)
+	default:
+		cfg, err = config.LoadConfig(config.GlobalScope)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read user.email from the %s git config: %w", scope, err)
+	}
+
+	return strings.TrimSpace(cfg.User.Email), nil
+}
+
+// Names of the files created in the home directory by Init
+const (
+	DotFileName    = ".git-contrib"
+	ConfigFileName = ".git-contrib.yaml"
+)
+
+// DotFileEnv is the environment variable overriding the location of the dotfile.
+const DotFileEnv = "GIT_CONTRIB_FILE"
+
+// IgnoreFileName is the name of the file listing the tracked repositories to leave out,
+// kept alongside the dotfile.
+const IgnoreFileName = ".git-contrib-ignore"
+
+// MetadataFileSuffix is appended to the path of the dotfile to name the file recording the remote
+// and the last commit of its tracked repositories, written by Scan with scanner.Options.Metadata.
+const MetadataFileSuffix = ".json"
+
+// starterConfig is the content of the configuration file created by Init.
+var starterConfig = []string{
+	"# git-contrib configuration file",
+	"# Uncomment and edit the settings below to change the defaults of the stats command.",
+	"",
+	"# path: [.]",
+	"# repos-from: \"\"",
+	"# subdir: \"\"",
+	"# email: \"\"",
+	"# emails-file: \"\"",
+	"# combine-authors: \"\"",
+	"# exclude-email: []",
+	"# count: false",
+	"# max-cell-value: 99",
+	"# annotate-above: 0",
+	"# days: false",
+	"# summary: false",
+	"# between-commits: false",
+	"# bursts: false",
+	"# burst-window: 5m",
+	"# reflog: false",
+	"# porcelain: false",
+	"# goal: 0",
+	"# by-timezone: false",
+	"# quiet-empty: false",
+	"# strict-email: false",
+	"# sizes: false",
+	"# breakdown: false",
+	"# sort-repos: commits",
+	"# working-days-only: false",
+	"# graph-style: cells",
+	"# color-scheme: github",
+	"# colors: \"\"",
+	"# relative: false",
+	"# align-to-today: false",
+	"# graph-only: false",
+	"# weekly: false",
+	"# compact: false",
+	"# auto-compact: false",
+	"# week-numbers: false",
+	"# collapse-empty-rows: false",
+	"# stripe: false",
+	"# weeks: 0",
+	"# delta: false",
+	"# binary: false",
+	"# mono: false",
+	"# dense: false",
+	"# top-n: 0",
+	"# label: \"\"",
+	"# force-color: false",
+	"# no-color: false",
+	"# clipboard: false",
+	"# no-pager: false",
+	"# no-progress: false",
+	"# group-by: \"\"",
+	"# diff: \"\"",
+	"# short-hash-length: 7",
+	"# require-full: false",
+	"# jobs: 0",
+	"# retries: 0",
+	"# format: terminal",
+	"# anonymize: false",
+	"# glyphs: false",
+	"# empty-char: \" \"",
+	"# no-bots: false",
+	"# bot-patterns: []",
+	"# exclude-weekends: false",
+	"# self-scope: global",
+	"# auto-self: false",
+	"# co-authors: false",
+	"# tags: false",
+	"# submodules: false",
+	"# head: \"\"",
+	"# all-branches: false",
+	"# limit-window-to-head-date: false",
+	"# months: 0",
+	"# since: \"\"",
+	"# repo-name: false",
+	"",
+	"# Settings of the scan command",
+	"# skip: [vendor, node_modules]",
+	"# order-by-activity: false",
+	"",
+	"# Settings of the report command",
+	"# report-format: terminal",
+	"",
+	"# Settings of both the scan and stats commands",
+	"# repo-type: all",
+}
+
+// Init creates the dotfile (empty) and a starter configuration file with commented defaults.
+// Existing files are left untouched unless force is set, so running it twice is harmless.
+//
+// Parameters:
+//   - homeDir: The directory to create the configuration file in (usually the user's home directory)
+//   - dotFile: The path of the dotfile, see ResolveDotFile
+//   - force: Whether to overwrite existing files
+//
+// Returns:
+//   - error: An error if any occurred while checking the files
+func Init(homeDir string, dotFile string, force bool) error {
+	files := []struct {
+		path    string
+		content []string
+	}{
+		{dotFile, []string{}},
+		{filepath.Join(homeDir, ConfigFileName), starterConfig},
+	}
+
+	for _, file := range files {
+		path := file.path
+
+		_, err := os.Stat(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to check %s: %w", path, err)
+		}
+
+		if err == nil && !force {
+			fmt.Printf("Skipped %s (already exists, use --force to overwrite)\n", path)
+			continue
+		}
+
+		fileutil.DumpStringsToFile(file.content, path)
+		fmt.Printf("Created %s\n", path)
+	}
+
+	return nil
+}
+
+// DotFilePath returns the path of the dotfile listing the tracked repositories.
+//
+// Returns:
+//   - string: The path of the dotfile in the user's home directory
+//   - error: An error if the home directory couldn't be determined
+func DotFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+
+	return filepath.Join(homeDir, DotFileName), nil
+}
+
+// ResolveDotFile returns the path of the dotfile, from the --dotfile flag, then the
+// GIT_CONTRIB_FILE environment variable, then the default location in the home directory.
+// This allows keeping per-project lists of tracked repositories, or the dotfile in ~/.config.
+//
+// Parameters:
+//   - override: The path given with the --dotfile flag (if empty, not given)
+//   - lookupEnv: The function looking up environment variables, usually os.LookupEnv
+//
+// Returns:
+//   - string: The absolute path of the dotfile, with a leading ~ and environment variables expanded
+//   - error: An error if the path couldn't be resolved
+func ResolveDotFile(override string, lookupEnv func(string) (string, bool)) (string, error) {
+	if override == "" {
+		override, _ = lookupEnv(DotFileEnv)
+	}
+	if override == "" {
+		return DotFilePath()
+	}
+
+	return expandPath(override)
+}
+
+// DiscoverRepositories looks for Git repositories in a folder and its subfolders.
+//
+// Parameters:
+//   - folder: The folder to scan
+//   - opts: The options controlling which subfolders are descended into
+//
+// Returns:
+//   - []string: The absolute paths of the repositories found
+//   - error: An error if the folder doesn't exist, isn't a folder or its path couldn't be resolved
+func DiscoverRepositories(folder string, opts scanner.Options) ([]string, error) {
+	// Check the folder up front, a typo would otherwise silently find nothing
+	info, err := os.Stat(folder)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("folder %s does not exist", folder)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read folder %s: %w", folder, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a folder", folder)
+	}
+
+	folder, err = filepath.Abs(folder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path of %s: %w", folder, err)
+	}
+
+	return scanner.ScanFolder(folder, opts), nil
+}
+
+// UntrackedRepositories returns the repositories not listed in the dotfile yet.
+// Unlike fileutil.ParseFileLines, a missing dotfile is not created.
+//
+// Parameters:
+//   - repos: The repositories found
+//   - dotFile: The path of the dotfile
+//
+// Returns:
+//   - []string: The repositories that aren't tracked yet, in the given order
+//   - error: An error if the dotfile exists but can't be read
+func UntrackedRepositories(repos []string, dotFile string) ([]string, error) {
+	var tracked []string
+	if _, err := os.Stat(dotFile); err == nil {
+		tracked, err = fileutil.ParseFileLines(dotFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var untracked []string
+	for _, repo := range repos {
+		if !fileutil.SliceContains(tracked, repo) {
+			untracked = append(untracked, repo)
+		}
+	}
+
+	return untracked, nil
+}
+
+// LoadIgnorePatterns reads the glob patterns of the ignore file kept alongside the dotfile.
+// Blank lines and lines starting with '#' are skipped. A missing file means no patterns.
+//
+// Parameters:
+//   - dotFile: The path of the dotfile
+//
+// Returns:
+//   - []string: The patterns of the repositories to leave out
+//   - error: An error if the ignore file exists but can't be read
+func LoadIgnorePatterns(dotFile string) ([]string, error) {
+	ignoreFile := filepath.Join(filepath.Dir(dotFile), IgnoreFileName)
+
+	// ParseFileLines creates missing files, so check for existence first
+	if _, err := os.Stat(ignoreFile); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	lines, err := fileutil.ParseFileLines(ignoreFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var patterns []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+
+	return patterns, nil
+}
+
+// FilterIgnored leaves out the repositories matching one of the patterns.
+// Patterns follow filepath.Match and are matched against the full path of the repository
+// (e.g. /home/me/code/monorepo or /home/me/forks/*) and against its name (e.g. linux).
+//
+// Parameters:
+//   - repos: The tracked repositories
+//   - patterns: The patterns of the repositories to leave out
+//
+// Returns:
+//   - []string: The repositories not matching any pattern, in the given order
+func FilterIgnored(repos []string, patterns []string) []string {
+	var kept []string
+
+	for _, repo := range repos {
+		ignored := false
+		for _, pattern := range patterns {
+			matchedPath, _ := filepath.Match(pattern, repo)
+			matchedName, _ := filepath.Match(pattern, filepath.Base(repo))
+			if matchedPath || matchedName {
+				ignored = true
+				break
+			}
+		}
+
+		if !ignored {
+			kept = append(kept, repo)
+		}
+	}
+
+	return kept
+}
+
+// Scan looks for Git repositories in a folder and adds them to the tracked repositories
+// listed in the dotfile, keeping the repositories already tracked (including those in
+// subfolders pruned by the options). With opts.Metadata, it also records the remote and the last commit of the
+// repositories found in the metadata file next to the dotfile.
+// With dryRun, it only lists the repositories that would be added, without writing anything.
+//
+// Parameters:
+//   - folder: The folder to scan
+//   - dotFile: The path of the dotfile
+//   - opts: The options controlling which subfolders are descended into
+//   - dryRun: Whether to preview the repositories to add instead of writing the dotfile
+//
+// Returns:
+//   - error: An error if any occurred during scanning
+func Scan(folder string, dotFile string, opts scanner.Options, dryRun bool) error {
+	repos, err := DiscoverRepositories(folder, opts)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		untracked, err := UntrackedRepositories(repos, dotFile)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Would add %d repositories (dry run, %s left untouched):\n", len(untracked), dotFile)
+		for _, repo := range untracked {
+			fmt.Printf("  %s\n", repo)
+		}
+		return nil
+	}
+
+	fmt.Printf("Found %d repositories:\n", len(repos))
+	for _, repo := range repos {
+		fmt.Printf("  %s\n", repo)
+	}
+
+	// Never overwrite a dotfile that couldn't be read, its repositories would be lost
+	existing, err := fileutil.ParseFileLines(dotFile)
+	if err != nil {
+		return err
+	}
+	tracked := fileutil.JoinSlices(repos, existing)
+	if opts.OrderByActivity {
+		// Keep the whole dotfile ordered, not only the repositories just found
+		tracked = scanner.SortByActivity(tracked)
+	}
+	fileutil.DumpStringsToFile(tracked, dotFile)
+
+	if opts.Metadata {
+		return recordMetadata(repos, tracked, MetadataFile(dotFile))
+	}
+	return nil
+}
+
+// MetadataFile returns the path of the metadata file of a dotfile, e.g. ~/.git-contrib.json for ~/.git-contrib,
+// so that dotfiles kept in the same directory don't share their metadata.
+//
+// Parameters:
+//   - dotFile: The path of the dotfile
+//
+// Returns:
+//   - string: The path of its metadata file
+func MetadataFile(dotFile string) string {
+	return dotFile + MetadataFileSuffix
+}
+
+// recordMetadata updates the metadata file with the repositories just found, dropping the untracked ones.
+//
+// Parameters:
+//   - repos: The repositories found by the scan
+//   - tracked: All the tracked repositories
+//   - path: The path of the metadata file
+//
+// Returns:
+//   - error: An error if the metadata file can't be read or written
+func recordMetadata(repos []string, tracked []string, path string) error {
+	// Never overwrite a metadata file that couldn't be read, e.g. written by a newer version
+	metadata, err := scanner.ReadMetadata(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	found := make([]scanner.RepoMetadata, 0, len(repos))
+	for _, repo := range repos {
+		found = append(found, scanner.CollectMetadata(repo, now))
+	}
+	metadata.Update(found, tracked)
+
+	if err := scanner.WriteMetadata(path, metadata); err != nil {
+		return err
+	}
+	fmt.Printf("Recorded the metadata of %d repositories in %s\n", len(found), path)
+	return nil
+}
+
+// canonicalPaths cleans the paths of a repository list, skipping blank lines and duplicates.
+func canonicalPaths(lines []string) []string {
+	var paths []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		paths = fileutil.JoinSlices([]string{filepath.Clean(line)}, paths)
+	}
+	return paths
+}
+
+// Merge adds the repositories tracked in another dotfile to the active one,
+// e.g. to sync the repositories tracked on several machines.
+// The paths of both files are cleaned, so the same repository isn't tracked twice.
+//
+// Parameters:
+//   - file: The path of the dotfile to merge
+//   - dotFile: The path of the active dotfile
+//
+// Returns:
+//   - error: An error if either file can't be read
+func Merge(file string, dotFile string) error {
+	// ParseFileLines creates missing files, so check for existence first
+	if _, err := os.Stat(file); err != nil {
+		return fmt.Errorf("failed to read %s: %w", file, err)
+	}
+
+	other, err := fileutil.ParseFileLines(file)
+	if err != nil {
+		return err
+	}
+
+	// Never overwrite a dotfile that couldn't be read, its repositories would be lost
+	existing, err := fileutil.ParseFileLines(dotFile)
+	if err != nil {
+		return err
+	}
+
+	tracked := canonicalPaths(existing)
+	merged := fileutil.JoinSlices(canonicalPaths(other), tracked)
+	fileutil.DumpStringsToFile(merged, dotFile)
+
+	fmt.Printf("Added %d repositories from %s (%d tracked)\n", len(merged)-len(tracked), file, len(merged))
+	return nil
+}
+
+// LoadRepositories reads a list of repositories to analyze from a file, one path per line,
+// independently of the tracked repositories. Blank lines and lines starting with '#' are ignored,
+// a leading ~ and environment variables ($HOME, ${CI_PROJECT_DIR}...) are expanded,
+// and relative paths are resolved against the current directory. Duplicates are removed.
+//
+// Parameters:
+//   - path: The path to the file listing the repositories
+//
+// Returns:
+//   - []string: The absolute paths of the repositories, in file order
+//   - error: An error if the file does not exist or can't be read
+func LoadRepositories(path string) ([]string, error) {
+	// ParseFileLines creates missing files, so check for existence first
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read repositories file %s: %w", path, err)
+	}
+
+	lines, err := fileutil.ParseFileLines(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var repos []string
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		repo, err := expandPath(line)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, repo)
+	}
+
+	return canonicalPaths(repos), nil
+}
+
+// expandPath expands a leading ~ and the environment variables of a path, and makes it absolute.
+func expandPath(path string) (string, error) {
+	path = os.ExpandEnv(path)
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %s: %w", path, err)
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand %s: %w", path, err)
+	}
+	return abs, nil
+}
+
+// PrimaryEmail finds the email the most commits were authored with in the given repositories,
+// for users who commit with another email than the one of their git config.
+// The repositories are analyzed once more for it, with the filter's emails left out.
+//
+// Parameters:
+//   - opts: The repositories and commits to process, see stats.Analyze
+//
+// Returns:
+//   - stats.AuthorCount: The most frequent email and its number of commits
+//   - int: The number of commits of all the authors
+//   - error: An error if a repository can't be processed, or has no commits in the graph window
+func PrimaryEmail(opts stats.Options) (stats.AuthorCount, int, error) {
+	opts.Filter.Emails = nil
+	opts.Filter.Trace = nil
+
+	result, err := stats.Analyze(opts)
+	if err != nil {
+		return stats.AuthorCount{}, 0, err
+	}
+
+	authors := stats.CountByAuthor(result.Commits)
+	if len(authors) == 0 {
+		return stats.AuthorCount{}, 0, fmt.Errorf("no commits found in %s to detect your email from", result.Range)
+	}
+	return authors[0], result.Summary.Total, nil
+}
+
+// ExportOptions holds the settings of the export command.
+type ExportOptions struct {
+	// Filter decides which commits to count
+	Filter stats.Filter
+	// Directories lists the directories to analyze (should be Git repositories)
+	Directories []string
+	// Dir is the directory the files are written to (created if missing)
+	Dir string
+	// Render controls the colors of the SVG and PNG graphs
+	Render stats.RenderOptions
+	// Jobs is the number of repositories processed concurrently (if zero, the number of CPUs)
+	Jobs int
+	// Progress reports the progress of the analysis (if nil, nothing is reported)
+	Progress *progress.Reporter
+}
+
+// ExportFiles lists the files written by Export, in the order they are written.
+var ExportFiles = []string{"contrib.svg", "contrib.png", "contrib.json", "contrib.csv"}
+
+// Export analyzes the repositories once and writes the graph as SVG and PNG images
+// and the counts as JSON and CSV to a directory, then lists the files written.
+//
+// Parameters:
+//   - opts: The settings of the export
+//
+// Returns:
+//   - error: An error if the analysis failed or a file couldn't be written
+func Export(opts ExportOptions) error {
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", opts.Dir, err)
+	}
+
+	result, err := stats.Analyze(stats.Options{
+		Filter:       opts.Filter,
+		Repositories: opts.Directories,
+		Jobs:         opts.Jobs,
+		Progress:     opts.Progress,
+	})
 	if err != nil {
 		return err
 	}
 
-	stats.PrintCommitsStats(commits, showCommitCount, showDaysOfMonth)
+	// Every format is written from the same result, the history is walked once
+	writers := map[string]func(w io.Writer) error{
+		"contrib.svg":  func(w io.Writer) error { return stats.WriteSVG(w, result, opts.Render) },
+		"contrib.png":  func(w io.Writer) error { return stats.WritePNG(w, result, opts.Render) },
+		"contrib.json": func(w io.Writer) error { return stats.WriteJSON(w, result, opts.Render) },
+		"contrib.csv":  func(w io.Writer) error { return stats.WriteCSV(w, result) },
+	}
+	for _, name := range ExportFiles {
+		path := filepath.Join(opts.Dir, name)
+		if err := writeFile(path, writers[name]); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote %s\n", path)
+	}
+
 	return nil
 }
+
+// ReportOptions holds the settings of the report command.
+type ReportOptions struct {
+	// Filter decides which commits to count
+	Filter stats.Filter
+	// Directories lists the directories to analyze (should be Git repositories)
+	Directories []string
+	// Format is the output format, stats.FormatTerminal or stats.FormatCSV (if empty, terminal)
+	Format string
+	// SortRepos orders the repositories, stats.SortByCommits, stats.SortByPath or stats.SortByRecent
+	SortRepos string
+	// WorkingDaysOnly keeps quiet weekends from breaking streaks
+	WorkingDaysOnly bool
+	// Jobs is the number of repositories processed concurrently (if zero, the number of CPUs)
+	Jobs int
+	// Progress reports the progress of the analysis (if nil, nothing is reported)
+	Progress *progress.Reporter
+	// Out is where the report is printed (if nil, the standard output)
+	Out io.Writer
+}
+
+// Report analyzes the repositories once and prints a table of the activity of each of them:
+// the commits and active days in the graph window, the last commit in the window and the current streak.
+//
+// Parameters:
+//   - opts: The settings of the report
+//
+// Returns:
+//   - error: An error if the analysis failed or the report couldn't be written
+func Report(opts ReportOptions) error {
+	result, err := stats.Analyze(stats.Options{
+		Filter:          opts.Filter,
+		Repositories:    opts.Directories,
+		WorkingDaysOnly: opts.WorkingDaysOnly,
+		Jobs:            opts.Jobs,
+		RepoNames:       true,
+		Progress:        opts.Progress,
+	})
+	if err != nil {
+		return err
+	}
+
+	stats.SortRepositories(result.Repositories, opts.SortRepos)
+	report := stats.BuildReport(result, opts.WorkingDaysOnly)
+
+	out := opts.Out
+	if out == nil {
+		out = os.Stdout
+	}
+	if opts.Format == stats.FormatCSV {
+		return stats.WriteReportCSV(out, report)
+	}
+	return stats.WriteReport(out, report)
+}
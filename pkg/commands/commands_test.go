@@ -1,9 +1,23 @@
 package commands
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/config"
+	"github.com/acheddir/git-contrib/pkg/scanner"
+	"github.com/acheddir/git-contrib/pkg/stats"
+	"github.com/acheddir/git-contrib/pkg/testutil"
+	"github.com/go-git/go-git/v5"
 )
 
 // TestStats tests the Stats function
@@ -20,7 +34,10 @@ func TestStats(t *testing.T) {
 
 	// Call the Stats function with a non-existent email
 	// This should not find any commits but should not error
-	err = Stats("nonexistent@example.com", tempDir, false, false)
+	err = Stats(StatsOptions{
+		Filter:      stats.Filter{Emails: []string{"nonexistent@example.com"}},
+		Directories: []string{tempDir},
+	})
 
 	// We expect an error since the directory is not a valid Git repository
 	if err == nil {
@@ -28,6 +45,742 @@ func TestStats(t *testing.T) {
 	}
 }
 
+// TestStatsEmailFilter tests that Stats only counts the commits of the filtered emails
+func TestStatsEmailFilter(t *testing.T) {
+	now := time.Now()
+	repo := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Old", When: now.AddDate(-1, 0, 0)},
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: now.AddDate(0, 0, -1)},
+		testutil.Commit{Email: "other@example.com", Message: "Theirs", When: now},
+	)
+	output := filepath.Join(t.TempDir(), "stats.json")
+
+	err := Stats(StatsOptions{
+		Filter:      stats.Filter{Emails: []string{"me@example.com"}},
+		Directories: []string{repo},
+		Format:      stats.FormatJSON,
+		Output:      output,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+
+	var result struct {
+		Total int `json:"total"`
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		t.Fatalf("Failed to decode output: %v", err)
+	}
+
+	// The commit of another author and the one out of range aren't counted
+	if result.Total != 1 {
+		t.Errorf("Expected a total of 1 commit, got %d", result.Total)
+	}
+}
+
+// TestStatsOut tests that the graph and the summary are printed to the writer of the options
+func TestStatsOut(t *testing.T) {
+	repo := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: time.Now()},
+	)
+
+	var out bytes.Buffer
+	err := Stats(StatsOptions{
+		Directories: []string{repo},
+		Label:       "My graph",
+		ShowSummary: true,
+		Render:      stats.RenderOptions{NoColor: true},
+		Out:         &out,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output := out.String(); !strings.HasPrefix(output, "My graph\n") || !strings.Contains(output, "Total commits:  1\n") {
+		t.Errorf("Expected the label, the graph and the summary, got %q", output)
+	}
+}
+
+// TestStatsStrictEmail tests that an email matching no commit fails with --strict-email
+func TestStatsStrictEmail(t *testing.T) {
+	repo := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: time.Now()},
+	)
+	output := filepath.Join(t.TempDir(), "stats.json")
+
+	opts := StatsOptions{
+		Filter:      stats.Filter{Emails: []string{"me@exmaple.com"}},
+		Directories: []string{repo},
+		Format:      stats.FormatJSON,
+		Output:      output,
+		StrictEmail: true,
+	}
+	err := Stats(opts)
+	if err == nil || !strings.Contains(err.Error(), "me@exmaple.com") {
+		t.Errorf("Expected an error naming the mistyped email, got %v", err)
+	}
+
+	// The right email passes
+	opts.Filter.Emails = []string{"me@example.com"}
+	if err := Stats(opts); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+// TestStatsGoal tests that the nudge of the daily goal is printed below the graph, and below the message of an empty graph
+func TestStatsGoal(t *testing.T) {
+	repo := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: time.Now()},
+	)
+
+	var out bytes.Buffer
+	opts := StatsOptions{
+		Filter:      stats.Filter{Emails: []string{"me@example.com"}},
+		Directories: []string{repo},
+		Goal:        3,
+		Render:      stats.RenderOptions{NoColor: true},
+		Out:         &out,
+	}
+	if err := Stats(opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output := out.String(); !strings.HasSuffix(output, "\n1/3 commits today, 2 to go.\n") {
+		t.Errorf("Expected the nudge below the graph, got %q", output)
+	}
+
+	// Without commits in the window, the warning follows the message of the empty graph
+	out.Reset()
+	opts.Filter.Emails = []string{"nobody@example.com"}
+	if err := Stats(opts); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if output := out.String(); !strings.HasPrefix(output, "No commits found") || !strings.HasSuffix(output, "\n⚠️ 0 commits today.\n") {
+		t.Errorf("Expected the warning below the message of the empty graph, got %q", output)
+	}
+}
+
+// TestStatsCompareEmpty tests that the comparison of two periods is printed even without commits in the window
+func TestStatsCompareEmpty(t *testing.T) {
+	repo := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: time.Now()},
+	)
+
+	var out bytes.Buffer
+	err := Stats(StatsOptions{
+		Filter:      stats.Filter{Emails: []string{"nobody@example.com"}},
+		Directories: []string{repo},
+		Compare:     stats.CompareWeek,
+		Out:         &out,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	output := out.String()
+	if strings.Contains(output, "No commits found") || !regexp.MustCompile(`Commits +0 +0 `).MatchString(output) {
+		t.Errorf("Expected the comparison of 0 against 0 commits, got %q", output)
+	}
+}
+
+// TestLoadRepositories tests the LoadRepositories function
+func TestLoadRepositories(t *testing.T) {
+	tempDir := t.TempDir()
+	reposFile := filepath.Join(tempDir, "repos.txt")
+
+	// Test case 1: File doesn't exist, and isn't created
+	if _, err := LoadRepositories(reposFile); err == nil {
+		t.Errorf("Expected an error for non-existent file, got nil")
+	}
+	if _, err := os.Stat(reposFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the file not to be created, got %v", err)
+	}
+
+	// Test case 2: Comments, blank lines, expansions and duplicates
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CODE_DIR", "/code")
+	content := "# generated\n/code/app\n\n  $CODE_DIR/lib/  \n~/notes\n/code/./app\nrelative\n"
+	if err := os.WriteFile(reposFile, []byte(content), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	repos, err := LoadRepositories(reposFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get the current directory: %v", err)
+	}
+	expected := []string{"/code/app", "/code/lib", filepath.Join(home, "notes"), filepath.Join(cwd, "relative")}
+	if !reflect.DeepEqual(repos, expected) {
+		t.Errorf("Expected %v, got %v", expected, repos)
+	}
+}
+
+// TestPrimaryEmail tests the detection of the most frequent author email
+func TestPrimaryEmail(t *testing.T) {
+	now := time.Now()
+	work := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@work.example", Message: "One", When: now.AddDate(0, 0, -2)},
+		testutil.Commit{Email: "Me@Work.example", Message: "Two", When: now.AddDate(0, 0, -1)},
+		testutil.Commit{Email: "colleague@work.example", Message: "Three", When: now},
+	)
+	side := testutil.NewRepo(t, testutil.Commit{Email: "me@home.example", Message: "Side", When: now})
+
+	// The filter's emails don't restrict the detection
+	author, total, err := PrimaryEmail(stats.Options{
+		Filter:       stats.Filter{Emails: []string{"me@home.example"}},
+		Repositories: []string{work, side},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if author.Email != "me@work.example" || author.Count != 2 || total != 4 {
+		t.Errorf("Expected me@work.example with 2 of 4 commits, got %s with %d of %d", author.Email, author.Count, total)
+	}
+
+	// Nothing to detect without commits
+	empty := testutil.NewRepo(t, testutil.Commit{Email: "me@work.example", Message: "Old", When: now.AddDate(-1, 0, 0)})
+	if _, _, err := PrimaryEmail(stats.Options{Repositories: []string{empty}}); err == nil || !strings.Contains(err.Error(), "the last 6 months") {
+		t.Errorf("Expected an error without commits in the last 6 months, got %v", err)
+	}
+
+	// A longer window reaches the older commits
+	yearAgo := stats.MonthsRange(13, now)
+	if author, _, err := PrimaryEmail(stats.Options{Repositories: []string{empty}, Range: yearAgo}); err != nil || author.Email != "me@work.example" {
+		t.Errorf("Expected me@work.example in the last 13 months, got %s (%v)", author.Email, err)
+	}
+}
+
+// TestLoadEmails tests the LoadEmails function
+func TestLoadEmails(t *testing.T) {
+	tempDir := t.TempDir()
+	emailsFile := filepath.Join(tempDir, "team.txt")
+
+	// Test case 1: File doesn't exist
+	_, err := LoadEmails(emailsFile)
+	if err == nil {
+		t.Errorf("Expected an error for non-existent file, got nil")
+	}
+
+	// Test case 2: Whitespace, blank lines and duplicates
+	err = os.WriteFile(emailsFile, []byte("  alice@example.com\n\nbob@example.com \nalice@example.com\n"), 0666)
+	if err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	emails, err := LoadEmails(emailsFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"alice@example.com", "bob@example.com"}
+	if !reflect.DeepEqual(emails, expected) {
+		t.Errorf("Expected %v, got %v", expected, emails)
+	}
+}
+
+// TestLoadIdentities tests the LoadIdentities function
+func TestLoadIdentities(t *testing.T) {
+	tempDir := t.TempDir()
+	authorsFile := filepath.Join(tempDir, "authors.txt")
+
+	// Test case 1: File doesn't exist
+	if _, err := LoadIdentities(authorsFile); err == nil {
+		t.Errorf("Expected an error for non-existent file, got nil")
+	}
+
+	// Test case 2: Comments, blank lines and case variants
+	err := os.WriteFile(authorsFile, []byte("# Alice\nalice@me.com: Alice@Work.com, alice@old.org\n\nbob@me.com:\n"), 0666)
+	if err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	identities, err := LoadIdentities(authorsFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"alice@me.com":   "alice@me.com",
+		"alice@work.com": "alice@me.com",
+		"alice@old.org":  "alice@me.com",
+		"bob@me.com":     "bob@me.com",
+	}
+	if !reflect.DeepEqual(identities, expected) {
+		t.Errorf("Expected %v, got %v", expected, identities)
+	}
+
+	// Test case 3: Malformed lines and aliases of two authors are rejected
+	for _, content := range []string{"alice@work.com\n", "alice@me.com: shared@x.com\nbob@me.com: shared@x.com\n"} {
+		if err := os.WriteFile(authorsFile, []byte(content), 0666); err != nil {
+			t.Fatalf("Failed to write to test file: %v", err)
+		}
+		if _, err := LoadIdentities(authorsFile); err == nil {
+			t.Errorf("Expected an error for %q, got nil", content)
+		}
+	}
+}
+
+// TestInit tests the Init function
+func TestInit(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, DotFileName)
+	configFile := filepath.Join(tempDir, ConfigFileName)
+
+	// Test case 1: Files are created
+	if err := Init(tempDir, dotFile, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, path := range []string{dotFile, configFile} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be created: %v", path, err)
+		}
+	}
+
+	// Test case 2: Existing files are not clobbered without force
+	err := os.WriteFile(dotFile, []byte("/some/repo"), 0666)
+	if err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+	if err := Init(tempDir, dotFile, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, _ := os.ReadFile(dotFile)
+	if string(content) != "/some/repo" {
+		t.Errorf("Expected dotfile to be kept, got %q", string(content))
+	}
+
+	// Test case 3: Existing files are overwritten with force
+	if err := Init(tempDir, dotFile, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, _ = os.ReadFile(dotFile)
+	if string(content) != "" {
+		t.Errorf("Expected dotfile to be overwritten, got %q", string(content))
+	}
+
+	// Test case 4: The keys are flat, so each is listed once and the uncommented configuration loads
+	content, _ = os.ReadFile(configFile)
+	uncommented := regexp.MustCompile(`(?m)^# ([a-z-]+:)`).ReplaceAllString(string(content), "$1")
+	if err := os.WriteFile(configFile, []byte(uncommented), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+	if _, err := config.Load(configFile); err != nil {
+		t.Errorf("Expected the uncommented configuration to load, got %v", err)
+	}
+}
+
+// TestResolveDotFile tests the precedence of the dotfile locations
+func TestResolveDotFile(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("USERPROFILE", tempDir)
+
+	env := map[string]string{}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	// Test case 1: The home directory by default
+	if path, err := ResolveDotFile("", lookupEnv); err != nil || path != filepath.Join(tempDir, DotFileName) {
+		t.Errorf("Expected the default dotfile, got %q (%v)", path, err)
+	}
+
+	// Test case 2: The environment overrides the default, with ~ expanded
+	env[DotFileEnv] = "~/.config/git-contrib/repos"
+	if path, err := ResolveDotFile("", lookupEnv); err != nil || path != filepath.Join(tempDir, ".config", "git-contrib", "repos") {
+		t.Errorf("Expected the dotfile of the environment, got %q (%v)", path, err)
+	}
+
+	// Test case 3: The flag overrides the environment
+	flag := filepath.Join(tempDir, "project", ".git-contrib")
+	if path, err := ResolveDotFile(flag, lookupEnv); err != nil || path != flag {
+		t.Errorf("Expected the dotfile of the flag, got %q (%v)", path, err)
+	}
+}
+
+// TestScan tests the Scan function
+func TestScan(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, DotFileName)
+
+	// Create a repository to find, and track another one already
+	repo := filepath.Join(tempDir, "code", "repo")
+	if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatalf("Failed to create .git directory: %v", err)
+	}
+	if err := os.WriteFile(dotFile, []byte("/tracked/repo"), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	if err := Scan(filepath.Join(tempDir, "code"), dotFile, scanner.Options{Skip: scanner.DefaultSkipDirs}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Fatalf("Failed to read dotfile: %v", err)
+	}
+	expected := "/tracked/repo\n" + repo
+	if string(content) != expected {
+		t.Errorf("Expected content %q, got %q", expected, string(content))
+	}
+
+	// A missing folder, or a file, is reported up front
+	for _, folder := range []string{filepath.Join(tempDir, "typo"), dotFile} {
+		if err := Scan(folder, dotFile, scanner.Options{}, false); err == nil {
+			t.Errorf("Expected an error for %s, got nil", folder)
+		}
+	}
+
+	// A dotfile that can't be read is reported rather than overwritten
+	if err := Scan(filepath.Join(tempDir, "code"), tempDir, scanner.Options{Skip: scanner.DefaultSkipDirs}, false); err == nil {
+		t.Errorf("Expected an error for an unreadable dotfile, got nil")
+	}
+}
+
+// TestScanMetadata tests that the scan records the repositories found in the metadata file only when asked
+func TestScanMetadata(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, DotFileName)
+	metadataFile := MetadataFile(dotFile)
+	repo := testutil.NewRepo(t, testutil.Commit{Email: "dev@example.com", Message: "Initial", When: time.Now()})
+	folder := filepath.Dir(repo)
+
+	if err := Scan(folder, dotFile, scanner.Options{}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(metadataFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no metadata file without the option, got %v", err)
+	}
+
+	if err := Scan(folder, dotFile, scanner.Options{Metadata: true}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	metadata, err := scanner.ReadMetadata(metadataFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(metadata.Repositories) != 1 || metadata.Repositories[0].Path != repo || metadata.Repositories[0].LastCommit.IsZero() {
+		t.Errorf("Expected the metadata of %s, got %+v", repo, metadata.Repositories)
+	}
+
+	// Another dotfile in the same directory gets its own metadata file
+	otherDotFile := filepath.Join(tempDir, "work")
+	if err := Scan(folder, otherDotFile, scanner.Options{Metadata: true}, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "work.json")); err != nil {
+		t.Errorf("Expected the metadata file of the other dotfile, got %v", err)
+	}
+	if metadataFile != filepath.Join(tempDir, DotFileName+".json") {
+		t.Errorf("Expected the metadata file next to the dotfile, got %s", metadataFile)
+	}
+}
+
 // Note: These tests are minimal and primarily ensure the functions don't panic.
 // In a real-world scenario, we would use dependency injection or mocking to test
 // these functions more thoroughly without relying on external dependencies.
+
+// TestScanDryRun tests that a dry run doesn't write the dotfile
+func TestScanDryRun(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, DotFileName)
+
+	tracked := filepath.Join(tempDir, "code", "tracked")
+	untracked := filepath.Join(tempDir, "code", "untracked")
+	for _, repo := range []string{tracked, untracked} {
+		if err := os.MkdirAll(filepath.Join(repo, ".git"), 0755); err != nil {
+			t.Fatalf("Failed to create .git directory: %v", err)
+		}
+	}
+
+	// A missing dotfile isn't created
+	if err := Scan(filepath.Join(tempDir, "code"), dotFile, scanner.Options{Skip: scanner.DefaultSkipDirs}, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(dotFile); !os.IsNotExist(err) {
+		t.Errorf("Expected the dotfile not to be created, got %v", err)
+	}
+
+	// Only the repositories that aren't tracked yet would be added
+	if err := os.WriteFile(dotFile, []byte(tracked), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+	repos, err := DiscoverRepositories(filepath.Join(tempDir, "code"), scanner.Options{Skip: scanner.DefaultSkipDirs})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result, err := UntrackedRepositories(repos, dotFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(result, []string{untracked}) {
+		t.Errorf("Expected %v, got %v", []string{untracked}, result)
+	}
+
+	if err := Scan(filepath.Join(tempDir, "code"), dotFile, scanner.Options{Skip: scanner.DefaultSkipDirs}, true); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Fatalf("Failed to read dotfile: %v", err)
+	}
+	if string(content) != tracked {
+		t.Errorf("Expected the dotfile to be untouched, got %q", string(content))
+	}
+}
+
+// TestMerge tests merging another dotfile into the active one
+func TestMerge(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, DotFileName)
+	other := filepath.Join(tempDir, "laptop")
+
+	if err := os.WriteFile(dotFile, []byte("/code/app\n/code/lib/"), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+	if err := os.WriteFile(other, []byte("/code/lib\n\n  /code/tools  \n/code/./app"), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	if err := Merge(other, dotFile); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(dotFile)
+	if err != nil {
+		t.Fatalf("Failed to read dotfile: %v", err)
+	}
+	expected := "/code/app\n/code/lib\n/code/tools"
+	if string(content) != expected {
+		t.Errorf("Expected content %q, got %q", expected, string(content))
+	}
+
+	// A missing file is reported, not created
+	if err := Merge(filepath.Join(tempDir, "missing"), dotFile); err == nil {
+		t.Errorf("Expected an error for a missing file, got nil")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Expected the missing file not to be created, got %v", err)
+	}
+}
+
+// TestIgnorePatterns tests the LoadIgnorePatterns and FilterIgnored functions
+func TestIgnorePatterns(t *testing.T) {
+	tempDir := t.TempDir()
+	dotFile := filepath.Join(tempDir, DotFileName)
+
+	// A missing ignore file means no patterns, and isn't created
+	if patterns, err := LoadIgnorePatterns(dotFile); err != nil || len(patterns) != 0 {
+		t.Errorf("Expected no patterns, got %v (%v)", patterns, err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, IgnoreFileName)); !os.IsNotExist(err) {
+		t.Errorf("Expected the ignore file not to be created, got %v", err)
+	}
+
+	content := "# Large repositories\n/code/monorepo\n\n  linux  \n/forks/*\n"
+	if err := os.WriteFile(filepath.Join(tempDir, IgnoreFileName), []byte(content), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	patterns, err := LoadIgnorePatterns(dotFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []string{"/code/monorepo", "linux", "/forks/*"}
+	if !reflect.DeepEqual(patterns, expected) {
+		t.Fatalf("Expected %v, got %v", expected, patterns)
+	}
+
+	repos := []string{"/code/monorepo", "/code/app", "/src/linux", "/forks/go", "/forks/go/nested"}
+	result := FilterIgnored(repos, patterns)
+	expected = []string{"/code/app", "/forks/go/nested"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestSelfEmail tests the SelfEmail function
+func TestSelfEmail(t *testing.T) {
+	// Isolate the global configuration
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+
+	repoDir := t.TempDir()
+	repo, err := git.PlainInit(repoDir, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	cfg, err := repo.Config()
+	if err != nil {
+		t.Fatalf("Failed to read repository config: %v", err)
+	}
+	cfg.User.Email = "work@example.com"
+	if err := repo.SetConfig(cfg); err != nil {
+		t.Fatalf("Failed to write repository config: %v", err)
+	}
+
+	// Test case 1: Unset values aren't errors
+	email, err := SelfEmail(ScopeGlobal, repoDir)
+	if err != nil || email != "" {
+		t.Errorf("Expected no email and no error, got %q, %v", email, err)
+	}
+
+	// Test case 2: Global and local values are read from their own scope
+	if err := os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[user]\n\temail = me@example.com\n"), 0666); err != nil {
+		t.Fatalf("Failed to write global config: %v", err)
+	}
+	if email, err := SelfEmail(ScopeGlobal, repoDir); err != nil || email != "me@example.com" {
+		t.Errorf("Expected me@example.com, got %q, %v", email, err)
+	}
+	if email, err := SelfEmail(ScopeLocal, repoDir); err != nil || email != "work@example.com" {
+		t.Errorf("Expected work@example.com, got %q, %v", email, err)
+	}
+}
+
+// TestWriteResult tests that machine-readable outputs are written to the output file
+func TestWriteResult(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "contributions.csv")
+	result := &stats.Result{Days: []stats.DayCount{{Date: time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC), Count: 3}}}
+
+	if err := writeResult(result, StatsOptions{Format: stats.FormatCSV, Output: output}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	expected := "date,count\n2023-05-15,3\n"
+	if string(content) != expected {
+		t.Errorf("Expected content %q, got %q", expected, string(content))
+	}
+
+	// The images are written like the other formats
+	var out bytes.Buffer
+	if err := writeResult(result, StatsOptions{Format: stats.FormatSVG, Out: &out}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "<svg ") || !strings.Contains(out.String(), "3 commits on 2023-05-15") {
+		t.Errorf("Expected an SVG image, got %q", out.String())
+	}
+	out.Reset()
+	if err := writeResult(result, StatsOptions{Format: stats.FormatPNG, Out: &out}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("\x89PNG")) {
+		t.Errorf("Expected a PNG image, got %d bytes", out.Len())
+	}
+	out.Reset()
+	if err := writeResult(result, StatsOptions{Format: stats.FormatHTML, Out: &out}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "<!DOCTYPE html>") || !strings.Contains(out.String(), "<svg ") {
+		t.Errorf("Expected an HTML page with the graph, got %q", out.String())
+	}
+
+	// Missing directories are reported
+	if err := writeResult(result, StatsOptions{Format: stats.FormatJSON, Output: filepath.Join(output, "missing", "out.json")}); err == nil {
+		t.Errorf("Expected an error for an invalid output path, got nil")
+	}
+}
+
+// TestPageOutput tests that long output goes through the pager and short output doesn't
+func TestPageOutput(t *testing.T) {
+	// The pager marks the output, to tell it from the direct one
+	t.Setenv("PAGER", "sed s/^/paged:/")
+	run := func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "line 1\nline 2\n")
+		return err
+	}
+
+	tests := []struct {
+		height   int
+		expected string
+	}{
+		{24, "line 1\nline 2\n"},
+		{2, "paged:line 1\npaged:line 2\n"},
+		{0, "line 1\nline 2\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+		if err := pageOutput(&out, run, test.height); err != nil {
+			t.Fatalf("Unexpected error with a height of %d: %v", test.height, err)
+		}
+		if output := out.String(); output != test.expected {
+			t.Errorf("With a height of %d, expected %q, got %q", test.height, test.expected, output)
+		}
+	}
+}
+
+// TestExport tests that every format is written from a single analysis
+func TestExport(t *testing.T) {
+	repo := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: time.Now()},
+		testutil.Commit{Email: "other@example.com", Message: "Theirs", When: time.Now()},
+	)
+	dir := filepath.Join(t.TempDir(), "out")
+
+	err := Export(ExportOptions{
+		Filter:      stats.Filter{Emails: []string{"me@example.com"}},
+		Directories: []string{repo},
+		Dir:         dir,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, name := range ExportFiles {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.Size() == 0 {
+			t.Errorf("Expected %s to be written, got %v", name, err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "contrib.csv"))
+	if err != nil {
+		t.Fatalf("Failed to read the CSV: %v", err)
+	}
+	if !bytes.HasSuffix(content, []byte(",1\n")) {
+		t.Errorf("Expected 1 commit today, got %q", string(content))
+	}
+}
+
+// TestReport tests that the report lists each repository with its own commits
+func TestReport(t *testing.T) {
+	busy := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "First", When: time.Now().AddDate(0, 0, -1)},
+		testutil.Commit{Email: "me@example.com", Message: "Second", When: time.Now()},
+		testutil.Commit{Email: "other@example.com", Message: "Theirs", When: time.Now()},
+	)
+	quiet := testutil.NewRepo(t, testutil.Commit{Email: "other@example.com", Message: "Theirs", When: time.Now()})
+
+	var out bytes.Buffer
+	err := Report(ReportOptions{
+		Filter:      stats.Filter{Emails: []string{"me@example.com"}},
+		Directories: []string{quiet, busy},
+		Format:      stats.FormatCSV,
+		SortRepos:   stats.SortByCommits,
+		Out:         &out,
+	})
+	output := out.String()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Repositories without remotes are named after their directory
+	today := time.Now().Format("2006-01-02")
+	expected := "repository,commits,active_days,last_commit,current_streak\n" +
+		filepath.Base(busy) + ",2,2," + today + ",2\n" +
+		filepath.Base(quiet) + ",0,0,,0\n"
+	if output != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, output)
+	}
+}
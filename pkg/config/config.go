@@ -0,0 +1,157 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of the effective settings, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "config file"
+	SourceEnv     = "environment"
+	SourceFlag    = "command line"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the settings.
+const EnvPrefix = "GIT_CONTRIB_"
+
+// Load reads a YAML configuration file and returns its settings keyed by name.
+// List values are joined with commas, matching the syntax of repeatable flags.
+// If the file doesn't exist, it returns an empty map.
+//
+// Parameters:
+//   - path: The path to the configuration file
+//
+// Returns:
+//   - map[string]string: The settings read from the file
+//   - error: An error if the file couldn't be read or parsed
+func Load(path string) (map[string]string, error) {
+	settings := make(map[string]string)
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(content, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			settings[key] = strings.Join(items, ",")
+		default:
+			settings[key] = fmt.Sprint(v)
+		}
+	}
+
+	return settings, nil
+}
+
+// Apply sets the flags that weren't given on the command line from the settings.
+// Settings without a matching flag are ignored, so one file can serve every command.
+//
+// Parameters:
+//   - flags: The flag set of the command being run
+//   - settings: The settings read from the configuration file
+//
+// Returns:
+//   - error: An error if a setting has an invalid value for its flag
+func Apply(flags *pflag.FlagSet, settings map[string]string) error {
+	for name, value := range settings {
+		flag := flags.Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		if err := flag.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid value %q for setting %s: %w", value, name, err)
+		}
+	}
+
+	return nil
+}
+
+// EnvName returns the name of the environment variable overriding a setting,
+// e.g. GIT_CONTRIB_WORKING_DAYS_ONLY for working-days-only.
+//
+// Parameters:
+//   - name: The name of the setting
+//
+// Returns:
+//   - string: The name of the environment variable
+func EnvName(name string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// ApplyEnv sets the flags that weren't given on the command line from the environment.
+// It must be called after Apply so the environment takes precedence over the file.
+//
+// Parameters:
+//   - flags: The flag set of the command being run
+//   - lookupEnv: The function looking up environment variables, usually os.LookupEnv
+//
+// Returns:
+//   - error: An error if a variable has an invalid value for its flag
+func ApplyEnv(flags *pflag.FlagSet, lookupEnv func(string) (string, bool)) error {
+	var err error
+	flags.VisitAll(func(flag *pflag.Flag) {
+		value, ok := lookupEnv(EnvName(flag.Name))
+		if !ok || flag.Changed || err != nil {
+			return
+		}
+
+		if setErr := flag.Value.Set(value); setErr != nil {
+			err = fmt.Errorf("invalid value %q for %s: %w", value, EnvName(flag.Name), setErr)
+		}
+	})
+
+	return err
+}
+
+// Sources returns where the effective value of each flag comes from.
+//
+// Parameters:
+//   - flags: The flag set of the command being run
+//   - settings: The settings read from the configuration file
+//   - lookupEnv: The function looking up environment variables, usually os.LookupEnv
+//
+// Returns:
+//   - map[string]string: The source of each flag, SourceDefault, SourceFile, SourceEnv or SourceFlag
+func Sources(flags *pflag.FlagSet, settings map[string]string, lookupEnv func(string) (string, bool)) map[string]string {
+	sources := make(map[string]string)
+
+	flags.VisitAll(func(flag *pflag.Flag) {
+		_, inEnv := lookupEnv(EnvName(flag.Name))
+		_, inFile := settings[flag.Name]
+
+		switch {
+		case flag.Changed:
+			sources[flag.Name] = SourceFlag
+		case inEnv:
+			sources[flag.Name] = SourceEnv
+		case inFile:
+			sources[flag.Name] = SourceFile
+		default:
+			sources[flag.Name] = SourceDefault
+		}
+	})
+
+	return sources
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+// TestLoad tests the Load function
+func TestLoad(t *testing.T) {
+	tempDir := t.TempDir()
+	configFile := filepath.Join(tempDir, "config.yaml")
+
+	// Test case 1: File doesn't exist
+	settings, err := Load(configFile)
+	if err != nil || len(settings) != 0 {
+		t.Errorf("Expected empty settings for non-existent file, got %v (%v)", settings, err)
+	}
+
+	// Test case 2: Scalars, lists and comments
+	content := "# comment\nemail: me@example.com\ncount: true\nbot-patterns:\n  - \"*[bot]*\"\n  - ci@example.com\n"
+	if err := os.WriteFile(configFile, []byte(content), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	settings, err = Load(configFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := map[string]string{
+		"email":        "me@example.com",
+		"count":        "true",
+		"bot-patterns": "*[bot]*,ci@example.com",
+	}
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("Expected %v, got %v", expected, settings)
+	}
+
+	// Test case 3: Invalid YAML
+	if err := os.WriteFile(configFile, []byte("email: [unclosed"), 0666); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+	if _, err := Load(configFile); err == nil {
+		t.Errorf("Expected an error for invalid YAML, got nil")
+	}
+}
+
+// TestApply tests the Apply function
+func TestApply(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	email := flags.String("email", "", "")
+	count := flags.Bool("count", false, "")
+	days := flags.Bool("days", false, "")
+
+	// The email is given on the command line, so the setting must not override it
+	if err := flags.Parse([]string{"--email", "cli@example.com"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	settings := map[string]string{
+		"email":   "config@example.com",
+		"count":   "true",
+		"unknown": "ignored",
+	}
+	if err := Apply(flags, settings); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if *email != "cli@example.com" {
+		t.Errorf("Expected command line email to win, got %q", *email)
+	}
+	if !*count {
+		t.Errorf("Expected count to be set from the config")
+	}
+	if *days {
+		t.Errorf("Expected days to keep its default")
+	}
+
+	// Invalid values are reported
+	if err := Apply(flags, map[string]string{"days": "maybe"}); err == nil {
+		t.Errorf("Expected an error for an invalid boolean, got nil")
+	}
+}
+
+// TestEnvName tests the EnvName function
+func TestEnvName(t *testing.T) {
+	if EnvName("working-days-only") != "GIT_CONTRIB_WORKING_DAYS_ONLY" {
+		t.Errorf("Unexpected environment variable %s", EnvName("working-days-only"))
+	}
+}
+
+// TestApplyEnvAndSources tests the ApplyEnv and Sources functions
+func TestApplyEnvAndSources(t *testing.T) {
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	email := flags.String("email", "", "")
+	count := flags.Bool("count", false, "")
+	days := flags.Bool("days", false, "")
+	flags.Bool("summary", false, "")
+
+	if err := flags.Parse([]string{"--days"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	settings := map[string]string{"email": "file@example.com", "count": "true"}
+	env := map[string]string{"GIT_CONTRIB_EMAIL": "env@example.com", "GIT_CONTRIB_DAYS": "false"}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	if err := Apply(flags, settings); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := ApplyEnv(flags, lookupEnv); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The environment wins over the file, the command line wins over both
+	if *email != "env@example.com" || !*count || !*days {
+		t.Errorf("Unexpected values: email=%q count=%v days=%v", *email, *count, *days)
+	}
+
+	expected := map[string]string{
+		"email":   SourceEnv,
+		"count":   SourceFile,
+		"days":    SourceFlag,
+		"summary": SourceDefault,
+	}
+	if sources := Sources(flags, settings, lookupEnv); !reflect.DeepEqual(sources, expected) {
+		t.Errorf("Expected %v, got %v", expected, sources)
+	}
+
+	// Invalid values are reported
+	env["GIT_CONTRIB_SUMMARY"] = "maybe"
+	if err := ApplyEnv(flags, lookupEnv); err == nil {
+		t.Errorf("Expected an error for an invalid boolean, got nil")
+	}
+}
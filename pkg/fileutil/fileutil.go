@@ -2,6 +2,7 @@ package fileutil
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"log"
 	"os"
@@ -10,39 +11,42 @@ import (
 )
 
 // ParseFileLines reads a file and returns its contents as a slice of strings, one per line.
-// If the file doesn't exist, it returns an empty slice.
+// If the file doesn't exist, it is created empty and an empty slice is returned.
+// A file that exists but can't be read (e.g. permission denied) is an error rather than
+// an empty slice, so callers don't go on to overwrite it with nothing.
 //
 // Parameters:
 //   - filePath: The path to the file to read
 //
 // Returns:
 //   - A slice of strings, one for each line in the file
-func ParseFileLines(filePath string) []string {
+//   - error: An error if the file exists but can't be read, or can't be created
+func ParseFileLines(filePath string) ([]string, error) {
 	// Check if a file exists first
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+	_, err := os.Stat(filePath)
+	if os.IsNotExist(err) {
 		// Ensure the directory exists
 		dir := filepath.Dir(filePath)
 		if err := os.MkdirAll(dir, 0755); err != nil {
-			log.Printf("Failed to create directory %s: %v", dir, err)
-			return []string{}
+			return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 		// Create an empty file
 		file, err := os.Create(filePath)
 		if err != nil {
-			log.Printf("Failed to create file %s: %v", filePath, err)
-			return []string{}
+			return nil, fmt.Errorf("failed to create file %s: %w", filePath, err)
 		}
-		err = file.Close()
-		if err != nil {
-			return nil
+		if err := file.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close file %s: %w", filePath, err)
 		}
-		return []string{}
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
 	file, err := os.Open(filePath)
 	if err != nil {
-		log.Printf("Failed to open file %s: %v", filePath, err)
-		return []string{}
+		return nil, fmt.Errorf("failed to open file %s: %w", filePath, err)
 	}
 
 	defer func(file *os.File) {
@@ -59,13 +63,11 @@ func ParseFileLines(filePath string) []string {
 		lines = append(lines, scanner.Text())
 	}
 
-	if err := scanner.Err(); err != nil {
-		if err != io.EOF {
-			log.Printf("Error scanning file %s: %v", filePath, err)
-		}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
 	}
 
-	return lines
+	return lines, nil
 }
 
 // DumpStringsToFile writes a slice of strings to a file, one per line.
@@ -1,6 +1,8 @@
 package fileutil
 
 import (
+	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -14,19 +16,25 @@ func TestParseFileLines(t *testing.T) {
 	tempFile := filepath.Join(tempDir, "test.txt")
 
 	// Test case 1: File doesn't exist
-	lines := ParseFileLines(tempFile)
+	lines, err := ParseFileLines(tempFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if len(lines) != 0 {
 		t.Errorf("Expected empty slice for non-existent file, got %v", lines)
 	}
 
 	// Test case 2: File exists with content
 	content := []string{"line1", "line2", "line3"}
-	err := os.WriteFile(tempFile, []byte("line1\nline2\nline3"), 0666)
+	err = os.WriteFile(tempFile, []byte("line1\nline2\nline3"), 0666)
 	if err != nil {
 		t.Fatalf("Failed to write to test file: %v", err)
 	}
 
-	lines = ParseFileLines(tempFile)
+	lines, err = ParseFileLines(tempFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if !reflect.DeepEqual(lines, content) {
 		t.Errorf("Expected %v, got %v", content, lines)
 	}
@@ -37,10 +45,34 @@ func TestParseFileLines(t *testing.T) {
 		t.Fatalf("Failed to write to test file: %v", err)
 	}
 
-	lines = ParseFileLines(tempFile)
+	lines, err = ParseFileLines(tempFile)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
 	if len(lines) != 0 {
 		t.Errorf("Expected empty slice for empty file, got %v", lines)
 	}
+
+	// Test case 4: A path that exists but can't be read as a file is an error, not an empty file
+	if lines, err := ParseFileLines(tempDir); err == nil {
+		t.Errorf("Expected an error for an unreadable file, got %v", lines)
+	}
+}
+
+// TestParseFileLinesPermissionDenied tests that an unreadable file isn't mistaken for an empty one
+func TestParseFileLinesPermissionDenied(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can read any file")
+	}
+
+	tempFile := filepath.Join(t.TempDir(), "test.txt")
+	if err := os.WriteFile(tempFile, []byte("line1"), 0000); err != nil {
+		t.Fatalf("Failed to write to test file: %v", err)
+	}
+
+	if _, err := ParseFileLines(tempFile); !errors.Is(err, fs.ErrPermission) {
+		t.Errorf("Expected a permission error, got %v", err)
+	}
 }
 
 // TestDumpStringsToFile tests the DumpStringsToFile function
@@ -0,0 +1,79 @@
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnavailable is returned when $PAGER isn't set and neither less nor more is installed.
+var ErrUnavailable = errors.New("no pager found, set $PAGER or install less")
+
+// Command returns the command line of the pager: $PAGER if set, otherwise less with -R
+// so the colors are kept, otherwise more.
+//
+// Returns:
+//   - []string: The pager and its arguments
+//   - error: ErrUnavailable if no pager is found
+func Command() ([]string, error) {
+	return command(os.LookupEnv, exec.LookPath)
+}
+
+// command picks the pager from the environment or among the installed ones, see Command.
+func command(lookupEnv func(string) (string, bool), lookPath func(string) (string, error)) ([]string, error) {
+	if pager, ok := lookupEnv("PAGER"); ok && strings.TrimSpace(pager) != "" {
+		return strings.Fields(pager), nil
+	}
+
+	for _, candidate := range [][]string{{"less", "-R"}, {"more"}} {
+		if _, err := lookPath(candidate[0]); err == nil {
+			return candidate, nil
+		}
+	}
+	return nil, ErrUnavailable
+}
+
+// Needed reports whether text is too long to fit on a screen of the given height,
+// keeping a line for the prompt of the shell.
+//
+// Parameters:
+//   - text: The text to print
+//   - height: The height of the screen in lines (if zero or less, unknown)
+//
+// Returns:
+//   - bool: true if the text would scroll off the screen
+func Needed(text string, height int) bool {
+	return height > 0 && strings.Count(text, "\n") >= height
+}
+
+// Page prints text through the pager, see Command, and waits for the user to quit it.
+// When $LESS isn't set, it is set to R so a $PAGER of less keeps the colors too.
+//
+// Parameters:
+//   - w: The writer the pager prints to, usually the terminal (os.Stdout)
+//   - text: The text to print
+//
+// Returns:
+//   - error: ErrUnavailable if no pager is found, or an error if the pager fails
+func Page(w io.Writer, text string) error {
+	args, err := Command()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=R")
+	}
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to page the output with %s: %w", args[0], err)
+	}
+	return nil
+}
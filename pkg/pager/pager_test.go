@@ -0,0 +1,74 @@
+package pager
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestCommand tests the command function
+func TestCommand(t *testing.T) {
+	// installed returns a lookPath finding only the given tools
+	installed := func(tools ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, tool := range tools {
+				if tool == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+
+	// env returns a lookupEnv finding only the given variables
+	env := func(vars map[string]string) func(string) (string, bool) {
+		return func(name string) (string, bool) {
+			value, ok := vars[name]
+			return value, ok
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		vars     map[string]string
+		tools    []string
+		expected []string
+	}{
+		{"pager of the environment", map[string]string{"PAGER": "most -s"}, []string{"less"}, []string{"most", "-s"}},
+		{"empty pager", map[string]string{"PAGER": " "}, []string{"less", "more"}, []string{"less", "-R"}},
+		{"less", nil, []string{"less", "more"}, []string{"less", "-R"}},
+		{"more", nil, []string{"more"}, []string{"more"}},
+	}
+
+	for _, tc := range testCases {
+		result, err := command(env(tc.vars), installed(tc.tools...))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, result)
+		}
+	}
+
+	// No pager at all
+	if _, err := command(env(nil), installed()); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Expected ErrUnavailable, got %v", err)
+	}
+}
+
+// TestNeeded tests the Needed function
+func TestNeeded(t *testing.T) {
+	text := strings.Repeat("line\n", 10)
+
+	if Needed(text, 24) {
+		t.Errorf("Expected 10 lines to fit on 24")
+	}
+	if !Needed(text, 10) {
+		t.Errorf("Expected 10 lines not to fit on 10, the prompt needs one")
+	}
+	if Needed(text, 0) {
+		t.Errorf("Expected no pager with an unknown height")
+	}
+}
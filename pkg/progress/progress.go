@@ -0,0 +1,87 @@
+// Package progress prints a status line updated in place while long operations run.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// interval is the minimum time between two updates of the status line, so fast loops don't flood the terminal.
+const interval = 100 * time.Millisecond
+
+// clearLine moves the cursor back to the beginning of the line and erases it.
+const clearLine = "\r\033[K"
+
+// Reporter prints a single status line, e.g. "Analyzing repositories 3/12", rewritten on each update.
+// It is meant for terminals only: the caller decides whether to create one, see Enabled.
+// A nil Reporter prints nothing, so callers don't need to check whether progress is enabled.
+// It is safe for concurrent use.
+type Reporter struct {
+	w       io.Writer
+	mu      sync.Mutex
+	last    time.Time
+	printed bool
+}
+
+// Enabled reports whether progress should be printed: never when disabled (e.g. with --no-progress),
+// and otherwise only on terminals, so piped or redirected output isn't corrupted.
+//
+// Parameters:
+//   - disabled: Whether progress was disabled by the user
+//   - terminal: Whether the progress output (the standard error) is a terminal
+//
+// Returns:
+//   - bool: true if progress must be printed
+func Enabled(disabled bool, terminal bool) bool {
+	return !disabled && terminal
+}
+
+// New creates a Reporter printing to w, typically the standard error.
+//
+// Parameters:
+//   - w: The writer the status line is printed to
+//
+// Returns:
+//   - *Reporter: The reporter
+func New(w io.Writer) *Reporter {
+	return &Reporter{w: w}
+}
+
+// Update replaces the status line, unless it was updated less than 100ms ago.
+//
+// Parameters:
+//   - format: The format of the status line, see fmt.Printf
+//   - args: The arguments of the format
+func (r *Reporter) Update(format string, args ...any) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if r.printed && now.Sub(r.last) < interval {
+		return
+	}
+	r.last = now
+	r.printed = true
+	_, _ = fmt.Fprintf(r.w, clearLine+format, args...)
+}
+
+// Done erases the status line, so the output that follows starts on a clean line.
+func (r *Reporter) Done() {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.printed {
+		_, _ = fmt.Fprint(r.w, clearLine)
+		r.printed = false
+	}
+}
@@ -0,0 +1,57 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+)
+
+// TestEnabled tests the Enabled function
+func TestEnabled(t *testing.T) {
+	testCases := []struct {
+		disabled bool
+		terminal bool
+		expected bool
+	}{
+		{false, true, true},
+		{false, false, false},
+		{true, true, false},
+		{true, false, false},
+	}
+
+	for _, tc := range testCases {
+		if result := Enabled(tc.disabled, tc.terminal); result != tc.expected {
+			t.Errorf("Enabled(%v, %v): expected %v, got %v", tc.disabled, tc.terminal, tc.expected, result)
+		}
+	}
+}
+
+// TestReporter tests that the status line is throttled and erased
+func TestReporter(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(&buf)
+
+	r.Update("Scanning %d folders", 1)
+	r.Update("Scanning %d folders", 2) // Too soon, skipped
+	if buf.String() != clearLine+"Scanning 1 folders" {
+		t.Errorf("Expected a single status line, got %q", buf.String())
+	}
+
+	r.Done()
+	if buf.String() != clearLine+"Scanning 1 folders"+clearLine {
+		t.Errorf("Expected the status line to be erased, got %q", buf.String())
+	}
+
+	// Nothing to erase
+	buf.Reset()
+	r.Done()
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing to be printed, got %q", buf.String())
+	}
+}
+
+// TestNilReporter tests that a nil Reporter prints nothing
+func TestNilReporter(t *testing.T) {
+	var r *Reporter
+	r.Update("Scanning")
+	r.Done()
+}
@@ -0,0 +1,59 @@
+package scanner
+
+import (
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// LastCommitTime returns the committer date of the commit HEAD points at.
+//
+// Parameters:
+//   - path: The path of the repository
+//
+// Returns:
+//   - time.Time: The date of the HEAD commit
+//   - error: An error if the repository can't be opened or has no commits
+func LastCommitTime(path string) (time.Time, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return commit.Committer.When, nil
+}
+
+// SortByActivity sorts repositories by the date of their HEAD commit, most recent first.
+// Repositories whose HEAD can't be read (e.g. without commits) come last, in their original order.
+// The sort is stable, so repositories with the same date keep their original order too.
+//
+// Parameters:
+//   - repos: The paths of the repositories, sorted in place
+//
+// Returns:
+//   - []string: The sorted repositories
+func SortByActivity(repos []string) []string {
+	dates := make(map[string]time.Time, len(repos))
+	for _, repo := range repos {
+		if when, err := LastCommitTime(repo); err == nil {
+			dates[repo] = when
+		}
+	}
+
+	sort.SliceStable(repos, func(i, j int) bool {
+		return dates[repos[i]].After(dates[repos[j]])
+	})
+
+	return repos
+}
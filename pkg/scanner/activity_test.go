@@ -0,0 +1,55 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// initRepo creates a repository with a single commit made at the given date
+func initRepo(t *testing.T, path string, when time.Time) {
+	t.Helper()
+	repo, err := git.PlainInit(path, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	signature := &object.Signature{Name: "dev", Email: "dev@example.com", When: when}
+	_, err = worktree.Commit("Commit", &git.CommitOptions{Author: signature, Committer: signature, AllowEmptyCommits: true})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+}
+
+// TestScanFolderOrderByActivity tests that the most recently committed repositories come first
+func TestScanFolderOrderByActivity(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+
+	old := filepath.Join(root, "a-old")
+	recent := filepath.Join(root, "b-recent")
+	empty := filepath.Join(root, "c-empty")
+	initRepo(t, old, now.AddDate(0, -3, 0))
+	initRepo(t, recent, now)
+	if _, err := git.PlainInit(empty, false); err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+
+	result := ScanFolder(root, Options{OrderByActivity: true})
+	expected := []string{recent, old, empty}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if _, err := LastCommitTime(empty); err == nil {
+		t.Errorf("Expected an error for a repository without commits")
+	}
+}
@@ -0,0 +1,128 @@
+package scanner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// MetadataVersion is the version of the metadata file format written by WriteMetadata.
+// It is increased when a change would break existing readers, and older readers refuse newer files.
+const MetadataVersion = 1
+
+// RepoMetadata holds what the scan learned about a repository.
+type RepoMetadata struct {
+	// Path is the path of the repository, as listed in the dotfile
+	Path string `json:"path"`
+	// RemoteURL is the URL of the origin remote, or of the first remote (empty without remotes)
+	RemoteURL string `json:"remote_url,omitempty"`
+	// LastCommit is the date of the HEAD commit (zero for repositories without commits)
+	LastCommit time.Time `json:"last_commit,omitzero"`
+	// ScannedAt is the date of the scan that recorded the repository
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// Metadata is the content of the metadata file: the repositories found by the scans, sorted by path.
+type Metadata struct {
+	// Version is the version of the file format, see MetadataVersion
+	Version int `json:"version"`
+	// Repositories lists the metadata of the tracked repositories
+	Repositories []RepoMetadata `json:"repositories"`
+}
+
+// CollectMetadata reads the remote and the last commit of a repository.
+//
+// Parameters:
+//   - path: The path of the repository
+//   - now: The date of the scan
+//
+// Returns:
+//   - RepoMetadata: The metadata of the repository
+func CollectMetadata(path string, now time.Time) RepoMetadata {
+	meta := RepoMetadata{Path: path, RemoteURL: RemoteURL(path), ScannedAt: now}
+	if last, err := LastCommitTime(path); err == nil {
+		meta.LastCommit = last
+	}
+	return meta
+}
+
+// Update replaces the metadata of the given repositories, and drops the repositories no longer tracked.
+//
+// Parameters:
+//   - repos: The metadata of the repositories just scanned
+//   - tracked: The paths of all the tracked repositories
+func (m *Metadata) Update(repos []RepoMetadata, tracked []string) {
+	byPath := make(map[string]RepoMetadata, len(m.Repositories)+len(repos))
+	for _, repo := range m.Repositories {
+		byPath[repo.Path] = repo
+	}
+	for _, repo := range repos {
+		byPath[repo.Path] = repo
+	}
+
+	m.Repositories = m.Repositories[:0]
+	for _, path := range tracked {
+		if repo, ok := byPath[path]; ok {
+			m.Repositories = append(m.Repositories, repo)
+			delete(byPath, path)
+		}
+	}
+	sort.Slice(m.Repositories, func(i, j int) bool { return m.Repositories[i].Path < m.Repositories[j].Path })
+}
+
+// ReadMetadata reads a metadata file. A missing file is read as empty metadata.
+//
+// Parameters:
+//   - path: The path of the metadata file
+//
+// Returns:
+//   - Metadata: The content of the file
+//   - error: An error if the file can't be read, is malformed or was written by a newer version
+func ReadMetadata(path string) (Metadata, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Metadata{Version: MetadataVersion}, nil
+	}
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to read metadata file %s: %w", path, err)
+	}
+
+	var m Metadata
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Metadata{}, fmt.Errorf("failed to parse metadata file %s: %w", path, err)
+	}
+	if m.Version > MetadataVersion {
+		return Metadata{}, fmt.Errorf("metadata file %s has version %d, this version of git-contrib supports up to %d",
+			path, m.Version, MetadataVersion)
+	}
+	m.Version = MetadataVersion
+
+	return m, nil
+}
+
+// WriteMetadata writes a metadata file as indented JSON.
+//
+// Parameters:
+//   - path: The path of the metadata file
+//   - m: The metadata to write
+//
+// Returns:
+//   - error: An error if the file can't be written
+func WriteMetadata(path string, m Metadata) error {
+	m.Version = MetadataVersion
+	if m.Repositories == nil {
+		m.Repositories = []RepoMetadata{}
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0666); err != nil {
+		return fmt.Errorf("failed to write metadata file %s: %w", path, err)
+	}
+	return nil
+}
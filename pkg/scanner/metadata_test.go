@@ -0,0 +1,135 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+)
+
+// TestCollectMetadata tests that the remote and the last commit of a repository are read
+func TestCollectMetadata(t *testing.T) {
+	root := t.TempDir()
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(root, "repo")
+	initRepo(t, path, when)
+
+	repo, err := git.PlainOpen(path)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "upstream", URLs: []string{"https://example.com/repo.git"}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	now := time.Now()
+	meta := CollectMetadata(path, now)
+	if meta.Path != path || meta.RemoteURL != "https://example.com/repo.git" || !meta.LastCommit.Equal(when) || !meta.ScannedAt.Equal(now) {
+		t.Errorf("Unexpected metadata %+v", meta)
+	}
+
+	// A repository without commits nor remotes only has its path and scan date
+	empty := filepath.Join(root, "empty")
+	if _, err := git.PlainInit(empty, false); err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	if meta := CollectMetadata(empty, now); meta.RemoteURL != "" || !meta.LastCommit.IsZero() {
+		t.Errorf("Expected no remote nor last commit, got %+v", meta)
+	}
+}
+
+// TestMetadataUpdate tests that scanned repositories replace their entries and untracked ones are dropped
+func TestMetadataUpdate(t *testing.T) {
+	old := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := old.AddDate(0, 1, 0)
+	m := Metadata{Version: MetadataVersion, Repositories: []RepoMetadata{
+		{Path: "/a", ScannedAt: old},
+		{Path: "/b", ScannedAt: old},
+		{Path: "/gone", ScannedAt: old},
+	}}
+
+	m.Update([]RepoMetadata{{Path: "/c", ScannedAt: now}, {Path: "/b", RemoteURL: "u", ScannedAt: now}}, []string{"/c", "/b", "/a"})
+
+	expected := []RepoMetadata{
+		{Path: "/a", ScannedAt: old},
+		{Path: "/b", RemoteURL: "u", ScannedAt: now},
+		{Path: "/c", ScannedAt: now},
+	}
+	if !reflect.DeepEqual(m.Repositories, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, m.Repositories)
+	}
+}
+
+// TestReadWriteMetadata tests that the metadata file round-trips and that newer versions are refused
+func TestReadWriteMetadata(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".git-contrib.json")
+
+	// A missing file is empty metadata
+	m, err := ReadMetadata(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if m.Version != MetadataVersion || len(m.Repositories) != 0 {
+		t.Errorf("Expected empty metadata, got %+v", m)
+	}
+
+	m.Repositories = []RepoMetadata{{
+		Path:       "/code/repo",
+		RemoteURL:  "git@github.com:me/repo.git",
+		LastCommit: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		ScannedAt:  time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC),
+	}, {
+		Path:      "/code/empty",
+		ScannedAt: time.Date(2024, 6, 1, 8, 30, 0, 0, time.UTC),
+	}}
+	if err := WriteMetadata(path, m); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	read, err := ReadMetadata(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(read, m) {
+		t.Errorf("Expected %+v, got %+v", m, read)
+	}
+
+	// The empty fields are left out of the file
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read metadata file: %v", err)
+	}
+	expected := `{
+  "version": 1,
+  "repositories": [
+    {
+      "path": "/code/repo",
+      "remote_url": "git@github.com:me/repo.git",
+      "last_commit": "2024-05-01T12:00:00Z",
+      "scanned_at": "2024-06-01T08:30:00Z"
+    },
+    {
+      "path": "/code/empty",
+      "scanned_at": "2024-06-01T08:30:00Z"
+    }
+  ]
+}
+`
+	if string(content) != expected {
+		t.Errorf("Expected content %s, got %s", expected, content)
+	}
+
+	// A file written by a newer version, or malformed, is an error
+	for _, content := range []string{`{"version": 2, "repositories": []}`, `{"version": `} {
+		if err := os.WriteFile(path, []byte(content), 0666); err != nil {
+			t.Fatalf("Failed to write metadata file: %v", err)
+		}
+		if _, err := ReadMetadata(path); err == nil {
+			t.Errorf("Expected an error for %s, got nil", content)
+		}
+	}
+}
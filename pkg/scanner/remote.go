@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Types of repositories the scan and the analysis can be limited to
+const (
+	// RepoTypeAll keeps every repository
+	RepoTypeAll = "all"
+	// RepoTypeRemote keeps the repositories with at least one remote, e.g. cloned projects
+	RepoTypeRemote = "remote"
+	// RepoTypeLocal keeps the repositories without remotes, e.g. local experiments
+	RepoTypeLocal = "local"
+)
+
+// RepoTypes lists the supported types of repositories, for validation and completion.
+var RepoTypes = []string{RepoTypeAll, RepoTypeRemote, RepoTypeLocal}
+
+// HasRemote reports whether a repository has at least one configured remote.
+//
+// Parameters:
+//   - path: The path of the repository
+//
+// Returns:
+//   - bool: true if the repository has a remote
+//   - error: An error if the repository or its configuration can't be read
+func HasRemote(path string) (bool, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return false, err
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return false, err
+	}
+
+	return len(cfg.Remotes) > 0, nil
+}
+
+// FilterByType keeps the repositories of the given type, in their original order.
+// Repositories whose configuration can't be read are considered local.
+//
+// Parameters:
+//   - repos: The paths of the repositories
+//   - repoType: RepoTypeRemote, RepoTypeLocal or RepoTypeAll (if empty, all)
+//
+// Returns:
+//   - []string: The repositories of the given type
+func FilterByType(repos []string, repoType string) []string {
+	if repoType == "" || repoType == RepoTypeAll {
+		return repos
+	}
+
+	var kept []string
+	for _, repo := range repos {
+		remote, _ := HasRemote(repo)
+		if remote == (repoType == RepoTypeRemote) {
+			kept = append(kept, repo)
+		}
+	}
+
+	return kept
+}
+
+// RemoteURL returns the URL of the origin remote of a repository, or of its first remote
+// by name without origin. It returns an empty string when the repository has no remote.
+//
+// Parameters:
+//   - path: The path of the repository
+//
+// Returns:
+//   - string: The URL of the remote, or an empty string
+func RemoteURL(path string) string {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return ""
+	}
+
+	cfg, err := repo.Config()
+	if err != nil || len(cfg.Remotes) == 0 {
+		return ""
+	}
+
+	remote, ok := cfg.Remotes["origin"]
+	if !ok {
+		// Pick the first remote by name, so the choice is deterministic
+		var names []string
+		for name := range cfg.Remotes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		remote = cfg.Remotes[names[0]]
+	}
+	if len(remote.URLs) == 0 {
+		return ""
+	}
+	return remote.URLs[0]
+}
@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+)
+
+// TestFilterByType tests that repositories are kept by whether they have a remote
+func TestFilterByType(t *testing.T) {
+	root := t.TempDir()
+	local := filepath.Join(root, "local")
+	cloned := filepath.Join(root, "cloned")
+	initRepo(t, local, time.Now())
+	initRepo(t, cloned, time.Now())
+
+	repo, err := git.PlainOpen(cloned)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	if _, err := repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"https://example.com/cloned.git"}}); err != nil {
+		t.Fatalf("Failed to create remote: %v", err)
+	}
+
+	repos := []string{local, cloned}
+	testCases := []struct {
+		repoType string
+		expected []string
+	}{
+		{RepoTypeAll, repos},
+		{"", repos},
+		{RepoTypeRemote, []string{cloned}},
+		{RepoTypeLocal, []string{local}},
+	}
+
+	for _, tc := range testCases {
+		if result := FilterByType(repos, tc.repoType); !reflect.DeepEqual(result, tc.expected) {
+			t.Errorf("%q: expected %v, got %v", tc.repoType, tc.expected, result)
+		}
+	}
+
+	// The type is also applied while scanning
+	if result := ScanFolder(root, Options{RepoType: RepoTypeRemote}); !reflect.DeepEqual(result, []string{cloned}) {
+		t.Errorf("Expected only %s to be found, got %v", cloned, result)
+	}
+}
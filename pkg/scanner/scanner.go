@@ -0,0 +1,124 @@
+package scanner
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/progress"
+)
+
+// DefaultSkipDirs lists the directories not descended into while scanning, unless overridden.
+var DefaultSkipDirs = []string{"vendor", "node_modules"}
+
+// Options controls which folders are descended into while scanning, and how the results are ordered.
+type Options struct {
+	// Skip lists glob patterns matched against the name of the subfolders not to descend into
+	Skip []string
+	// ModifiedSince prunes the subfolders not modified since then (if zero, none are pruned).
+	// A folder is modified when entries are added or removed, e.g. when a repository is cloned in it.
+	ModifiedSince time.Time
+	// RepoType keeps the repositories found with (RepoTypeRemote) or without (RepoTypeLocal) a remote
+	// (if empty, all of them)
+	RepoType string
+	// OrderByActivity sorts the repositories found by the date of their HEAD commit, most recent first
+	OrderByActivity bool
+	// Metadata records the remote and the date of the last commit of the repositories found in the metadata file
+	Metadata bool
+	// Progress reports the number of repositories found while scanning (if nil, nothing is reported)
+	Progress *progress.Reporter
+}
+
+// prunes reports whether a subfolder must not be descended into.
+func (o Options) prunes(dir os.FileInfo) bool {
+	if isSkipped(dir.Name(), o.Skip) {
+		return true
+	}
+
+	return !o.ModifiedSince.IsZero() && dir.ModTime().Before(o.ModifiedSince)
+}
+
+// ScanGitFolders recursively looks for Git repositories in a folder and appends them to folders.
+// A folder is a repository if it contains a .git directory, or a .git file as found in
+// linked worktrees (git worktree) and submodules.
+// Folders that can't be read are logged and skipped.
+//
+// Parameters:
+//   - folders: The repositories found so far
+//   - folder: The folder to scan
+//   - opts: The options controlling which subfolders are descended into
+//
+// Returns:
+//   - []string: The repositories found so far, including those found in folder
+func ScanGitFolders(folders []string, folder string, opts Options) []string {
+	f, err := os.Open(folder)
+	if err != nil {
+		log.Printf("Failed to open folder %s: %v", folder, err)
+		return folders
+	}
+
+	files, err := f.Readdir(-1)
+	if closeErr := f.Close(); closeErr != nil {
+		log.Printf("Failed to close folder %s: %v", folder, closeErr)
+	}
+	if err != nil {
+		log.Printf("Failed to read folder %s: %v", folder, err)
+		return folders
+	}
+	opts.Progress.Update("Scanning... %d repositories found", len(folders))
+
+	for _, file := range files {
+		path := filepath.Join(folder, file.Name())
+
+		// A .git directory, or a .git file pointing at the real gitdir, marks a repository
+		if file.Name() == ".git" && (file.IsDir() || file.Mode().IsRegular()) {
+			folders = append(folders, folder)
+			continue
+		}
+
+		if !file.IsDir() || opts.prunes(file) {
+			continue
+		}
+
+		folders = ScanGitFolders(folders, path, opts)
+	}
+
+	return folders
+}
+
+// ScanFolder returns the Git repositories found in a folder and its subfolders,
+// sorted by path so the results don't depend on the order the filesystem lists folders in.
+//
+// Parameters:
+//   - folder: The folder to scan
+//   - opts: The options controlling which subfolders are descended into and the order of the results
+//
+// Returns:
+//   - []string: The paths of the repositories found
+func ScanFolder(folder string, opts Options) []string {
+	folders := ScanGitFolders(make([]string, 0), filepath.Clean(folder), opts)
+	opts.Progress.Done()
+	folders = FilterByType(folders, opts.RepoType)
+
+	// The sort by activity is stable, so repositories with the same date stay sorted by path
+	sort.Strings(folders)
+	if opts.OrderByActivity {
+		folders = SortByActivity(folders)
+	}
+
+	return folders
+}
+
+// isSkipped reports whether a directory must not be descended into.
+// Patterns follow filepath.Match, so a name without wildcards matches itself.
+func isSkipped(name string, skip []string) bool {
+	for _, pattern := range skip {
+		if matched, err := filepath.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
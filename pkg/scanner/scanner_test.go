@@ -0,0 +1,136 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// mkdirAll creates a directory and its parents for the test
+func mkdirAll(t *testing.T, path string) {
+	t.Helper()
+	if err := os.MkdirAll(path, 0755); err != nil {
+		t.Fatalf("Failed to create directory %s: %v", path, err)
+	}
+}
+
+// TestScanFolder tests the ScanFolder function
+func TestScanFolder(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// A regular repository, with a nested repository
+	mkdirAll(t, filepath.Join(tempDir, "repo", ".git"))
+	mkdirAll(t, filepath.Join(tempDir, "repo", "nested", ".git"))
+
+	// A linked worktree, where .git is a file pointing at the real gitdir
+	mkdirAll(t, filepath.Join(tempDir, "worktree"))
+	err := os.WriteFile(filepath.Join(tempDir, "worktree", ".git"), []byte("gitdir: ../repo/.git/worktrees/worktree"), 0666)
+	if err != nil {
+		t.Fatalf("Failed to write .git file: %v", err)
+	}
+
+	// Repositories created out of order
+	mkdirAll(t, filepath.Join(tempDir, "zeta", ".git"))
+	mkdirAll(t, filepath.Join(tempDir, "alpha", ".git"))
+
+	// Repositories in skipped directories are ignored
+	mkdirAll(t, filepath.Join(tempDir, "node_modules", "dep", ".git"))
+
+	// Plain folders aren't repositories
+	mkdirAll(t, filepath.Join(tempDir, "plain", "folder"))
+
+	// The repositories are sorted by path, whatever the order of the filesystem
+	result := ScanFolder(tempDir, Options{Skip: DefaultSkipDirs})
+
+	expected := []string{
+		filepath.Join(tempDir, "alpha"),
+		filepath.Join(tempDir, "repo"),
+		filepath.Join(tempDir, "repo", "nested"),
+		filepath.Join(tempDir, "worktree"),
+		filepath.Join(tempDir, "zeta"),
+	}
+	if len(result) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, result)
+			break
+		}
+	}
+}
+
+// TestScanFolderMissing tests that scanning a missing folder finds nothing
+func TestScanFolderMissing(t *testing.T) {
+	result := ScanFolder(filepath.Join(t.TempDir(), "missing"), Options{Skip: DefaultSkipDirs})
+	if len(result) != 0 {
+		t.Errorf("Expected no repositories, got %v", result)
+	}
+}
+
+// TestScanFolderSkipPatterns tests that skipped folders can be given as glob patterns
+func TestScanFolderSkipPatterns(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mkdirAll(t, filepath.Join(tempDir, "repo", ".git"))
+	mkdirAll(t, filepath.Join(tempDir, "pkg.egg-info", "dep", ".git"))
+	mkdirAll(t, filepath.Join(tempDir, "build-linux", "dep", ".git"))
+	mkdirAll(t, filepath.Join(tempDir, "vendor", "dep", ".git"))
+
+	result := ScanFolder(tempDir, Options{Skip: []string{"*.egg-info", "build-*", "vendor"}})
+
+	expected := []string{filepath.Join(tempDir, "repo")}
+	if len(result) != 1 || result[0] != expected[0] {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestScanFolderModifiedSince tests that folders not modified recently are pruned
+func TestScanFolderModifiedSince(t *testing.T) {
+	tempDir := t.TempDir()
+
+	mkdirAll(t, filepath.Join(tempDir, "active", "repo", ".git"))
+	mkdirAll(t, filepath.Join(tempDir, "stale", "repo", ".git"))
+
+	// Age the stale folder
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(filepath.Join(tempDir, "stale"), old, old); err != nil {
+		t.Fatalf("Failed to change modification time: %v", err)
+	}
+
+	result := ScanFolder(tempDir, Options{ModifiedSince: time.Now().Add(-24 * time.Hour)})
+	expected := []string{filepath.Join(tempDir, "active", "repo")}
+	if len(result) != 1 || result[0] != expected[0] {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// Without a threshold, nothing is pruned
+	if result := ScanFolder(tempDir, Options{}); len(result) != 2 {
+		t.Errorf("Expected 2 repositories, got %v", result)
+	}
+}
+
+// TestIsSkipped tests the isSkipped function
+func TestIsSkipped(t *testing.T) {
+	testCases := []struct {
+		name     string
+		skip     []string
+		expected bool
+	}{
+		{"vendor", []string{"vendor"}, true},
+		{"vendors", []string{"vendor"}, false},
+		{"pkg.egg-info", []string{"*.egg-info"}, true},
+		{"build-arm64", []string{"build-*"}, true},
+		{"build", []string{"build-*"}, false},
+		{"cache1", []string{"cache?"}, true},
+		{"src", []string{"[invalid"}, false},
+		{"vendor", nil, false},
+	}
+
+	for _, tc := range testCases {
+		if result := isSkipped(tc.name, tc.skip); result != tc.expected {
+			t.Errorf("isSkipped(%q, %v): expected %v, got %v", tc.name, tc.skip, tc.expected, result)
+		}
+	}
+}
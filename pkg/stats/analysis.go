@@ -0,0 +1,431 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/progress"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Options describes the repositories and commits Analyze should process.
+type Options struct {
+	// Filter decides which commits to count
+	Filter Filter
+	// Repositories lists the paths of the Git repositories to analyze
+	Repositories []string
+	// WorkingDaysOnly keeps quiet weekends from breaking streaks
+	WorkingDaysOnly bool
+	// RequireFull fails on shallow clones instead of reporting them in the result
+	RequireFull bool
+	// Tags counts the annotated tags created (by tagger) instead of the commits
+	Tags bool
+	// Reflog counts the commits created locally from the HEAD reflog instead of the history,
+	// see GetReflogFromRepo (experimental, local operations only)
+	Reflog bool
+	// CommitSizes computes the lines changed by each matched commit (slow, it diffs every commit)
+	CommitSizes bool
+	// Jobs is the number of repositories processed concurrently (if zero or less, the number of CPUs)
+	Jobs int
+	// RepoNames names each repository after its remote, see RepoName
+	RepoNames bool
+	// Head is the revision the history of each repository is walked from (if empty, HEAD)
+	Head string
+	// AllBranches walks the history of every branch, remote branch and tag instead of HEAD,
+	// see GetCommitsFromAllBranches
+	AllBranches bool
+	// EndAtHead ends the graph window on the author date of the most recent commit matching Filter in the
+	// histories walked from HEAD (or Head) instead of today, so the graph of an archived repository doesn't
+	// end with months without commits. It is ignored with AllBranches, Tags or Reflog.
+	EndAtHead bool
+	// Submodules also analyzes the checked-out submodules of the repositories, counting each commit once
+	Submodules bool
+	// Progress reports the number of repositories processed (if nil, nothing is reported)
+	Progress *progress.Reporter
+	// Retries is the number of times opening a repository is tried again on transient errors,
+	// e.g. of network filesystems, see IsTransient (if zero, it isn't)
+	Retries int
+	// Range is the window of the graph, the commits before it being out of range (if empty, DefaultGraphRange)
+	Range GraphRange
+	// BurstWindow counts the commits made within this window of another one in Summary.Bursts
+	// (if zero, they aren't), see CommitBursts
+	BurstWindow time.Duration
+}
+
+// DayCount holds the number of commits made on a single day.
+type DayCount struct {
+	// Date is the beginning of the day (UTC)
+	Date time.Time
+	// Count is the number of commits made on that day
+	Count int
+}
+
+// RepoResult holds the commits matched in a single repository.
+type RepoResult struct {
+	// Path is the path of the repository
+	Path string
+	// Name is the name of the repository after its remote (only set with Options.RepoNames)
+	Name string
+	// Total is the number of matched commits in the graph window
+	Total int
+	// FirstCommit is the author date of the oldest matched commit (zero if none)
+	FirstCommit time.Time
+	// LastCommit is the author date of the most recent matched commit (zero if none)
+	LastCommit time.Time
+	// Shallow reports a shallow clone, whose commits may be partial
+	Shallow bool
+	// Counts maps the number of days ago to the commit counts of the repository
+	Counts map[int]int
+}
+
+// Label returns the name of the repository if it has one, otherwise its path.
+func (r RepoResult) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Path
+}
+
+// Orders of the per-repository breakdown
+const (
+	// SortByCommits orders repositories by number of commits, most first
+	SortByCommits = "commits"
+	// SortByPath orders repositories alphabetically by path
+	SortByPath = "path"
+	// SortByRecent orders repositories by most recent commit, most recent first
+	SortByRecent = "recent"
+)
+
+// RepoOrders lists the supported orders of the per-repository breakdown.
+var RepoOrders = []string{SortByCommits, SortByPath, SortByRecent}
+
+// Result holds the outcome of an analysis, ready to be rendered by any output.
+type Result struct {
+	// Days lists the commit counts of every day in the graph window, oldest first
+	Days []DayCount
+	// Counts maps the number of days ago to commit counts, as used by the graph
+	Counts map[int]int
+	// Commits maps the number of days ago to the matched commits of that day
+	Commits map[int][]CommitInfo
+	// Summary holds the totals and streaks
+	Summary Summary
+	// Intervals holds the time between consecutive commits
+	Intervals Intervals
+	// FirstCommit is the author date of the oldest matched commit (zero if none)
+	FirstCommit time.Time
+	// LastCommit is the author date of the most recent matched commit (zero if none)
+	LastCommit time.Time
+	// Repositories holds the per-repository breakdown, in the order they were given
+	Repositories []RepoResult
+	// Until is the last day of the graph window: today, or the day of the most recent matched commit with Options.EndAtHead.
+	// The days ago of Counts and Commits are counted from it.
+	Until time.Time
+	// Range is the window of the graph, Options.Range or DefaultGraphRange
+	Range GraphRange
+}
+
+// repoOutcome holds the commits read from a single repository by a worker.
+type repoOutcome struct {
+	commits map[int][]CommitInfo
+	shallow bool
+	name    string
+	err     error
+}
+
+// processRepository reads the matched commits of a single repository, see Analyze.
+// The days ago are counted from the given day, the last one of the graph window.
+func processRepository(path string, opts Options, today time.Time) repoOutcome {
+	var commits map[int][]CommitInfo
+	var err error
+	if opts.Tags {
+		commits, err = getTagsFromRepo(opts.Filter, path, make(map[int][]CommitInfo), opts.Range)
+		if err != nil {
+			err = fmt.Errorf("error processing repository at %s: %w", path, err)
+		}
+	} else if opts.Reflog {
+		commits, err = getReflogFromRepo(opts.Filter, path, make(map[int][]CommitInfo), opts.Range)
+		if err != nil {
+			err = fmt.Errorf("error processing repository at %s: %w", path, err)
+		}
+	} else if opts.AllBranches {
+		commits, err = getCommitsFromAllBranches(opts.Filter, path, opts.Retries, make(map[int][]CommitInfo), today, opts.Range)
+		if err != nil {
+			err = fmt.Errorf("error processing repository at %s: %w", path, err)
+		}
+	} else {
+		commits, err = getCommitsFromRevision(opts.Filter, path, opts.Head, opts.Retries, make(map[int][]CommitInfo), today, opts.Range)
+		if err != nil {
+			err = fmt.Errorf("error processing repository at %s: %w", path, err)
+		}
+	}
+	if err != nil {
+		return repoOutcome{err: err}
+	}
+
+	// The history of a shallow clone stops at the grafted commits
+	shallow, err := IsShallow(path)
+	if err != nil {
+		return repoOutcome{err: err}
+	}
+	if shallow && opts.RequireFull {
+		return repoOutcome{err: fmt.Errorf("repository at %s is a shallow clone, fetch its full history with 'git fetch --unshallow'", path)}
+	}
+
+	if opts.CommitSizes {
+		if err := fillCommitSizes(path, commits); err != nil {
+			return repoOutcome{err: err}
+		}
+	}
+
+	outcome := repoOutcome{commits: commits, shallow: shallow}
+	if opts.RepoNames {
+		outcome.name = RepoName(path)
+	}
+
+	return outcome
+}
+
+// processRepositories reads the repositories with a bounded pool of workers.
+// Each worker fills the outcomes of the repositories it picks, so no map is shared.
+func processRepositories(opts Options, today time.Time) []repoOutcome {
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+	if jobs > len(opts.Repositories) {
+		jobs = len(opts.Repositories)
+	}
+
+	outcomes := make([]repoOutcome, len(opts.Repositories))
+	indexes := make(chan int)
+
+	var done atomic.Int64
+	total := len(opts.Repositories)
+	opts.Progress.Update("Analyzing repositories 0/%d", total)
+	defer opts.Progress.Done()
+
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				outcomes[index] = processRepository(opts.Repositories[index], opts, today)
+				opts.Progress.Update("Analyzing repositories %d/%d", done.Add(1), total)
+			}
+		}()
+	}
+
+	for index := range opts.Repositories {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	return outcomes
+}
+
+// Analyze processes the repositories and computes the contribution statistics
+// without printing anything, so it can be used as a library entry point.
+// Repositories are processed concurrently, and the failures of all of them are reported.
+//
+// Parameters:
+//   - opts: The repositories and commits to process
+//
+// Returns:
+//   - *Result: The computed statistics
+//   - error: An error if any repository couldn't be processed
+func Analyze(opts Options) (*Result, error) {
+	result := &Result{Commits: make(map[int][]CommitInfo)}
+
+	// The submodules are analyzed as repositories of their own, listed after the given ones
+	if opts.Submodules {
+		opts.Repositories = withSubmodules(opts.Repositories)
+	}
+
+	// The window ends today, or on the day of the most recent matched commit, found by a first walk
+	today := GetBeginningOfDay(time.Now())
+	if opts.EndAtHead && !opts.AllBranches && !opts.Tags && !opts.Reflog {
+		last, err := latestMatch(opts, today)
+		if err != nil {
+			return nil, err
+		}
+		if !last.IsZero() {
+			today = GetBeginningOfDay(last)
+		}
+	}
+	result.Until = today
+
+	// The window covers the last six months unless another one is chosen
+	opts.Range = opts.Range.orDefault()
+	result.Range = opts.Range
+
+	outcomes := processRepositories(opts, today)
+
+	// Report every failed repository, in the order they were given
+	var errs []error
+	for _, outcome := range outcomes {
+		if outcome.err != nil {
+			errs = append(errs, outcome.err)
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	// The same commit is only counted once when a submodule shares history with
+	// another analyzed repository (e.g. the same library checked out twice)
+	seen := make(map[string]bool)
+
+	// Merge the commits of each repository, in the order they were given
+	for i, path := range opts.Repositories {
+		commits := outcomes[i].commits
+
+		repo := RepoResult{Path: path, Name: outcomes[i].name, Shallow: outcomes[i].shallow, Counts: make(map[int]int)}
+		for day, dayCommits := range commits {
+			for _, c := range dayCommits {
+				if opts.Submodules && !opts.Tags {
+					if seen[c.Hash] {
+						continue
+					}
+					seen[c.Hash] = true
+				}
+				c.Repository = path
+				result.Commits[day] = append(result.Commits[day], c)
+
+				repo.Total++
+				repo.Counts[day]++
+				if repo.FirstCommit.IsZero() || c.When.Before(repo.FirstCommit) {
+					repo.FirstCommit = c.When
+				}
+				if c.When.After(repo.LastCommit) {
+					repo.LastCommit = c.When
+				}
+			}
+		}
+		result.Repositories = append(result.Repositories, repo)
+
+		if !repo.FirstCommit.IsZero() && (result.FirstCommit.IsZero() || repo.FirstCommit.Before(result.FirstCommit)) {
+			result.FirstCommit = repo.FirstCommit
+		}
+		if repo.LastCommit.After(result.LastCommit) {
+			result.LastCommit = repo.LastCommit
+		}
+	}
+
+	result.Counts = CountCommits(result.Commits, opts.Range)
+	result.Summary = summarize(result.Counts, opts.WorkingDaysOnly, today, opts.Range)
+	result.Intervals = CommitIntervals(result.Commits)
+	if opts.BurstWindow > 0 {
+		bursts := CommitBursts(result.Commits, opts.BurstWindow)
+		result.Summary.Bursts = &bursts
+	}
+
+	// List the days with their real dates, oldest first
+	for daysAgo := opts.Range.Days; daysAgo >= 0; daysAgo-- {
+		result.Days = append(result.Days, DayCount{Date: today.AddDate(0, 0, -daysAgo), Count: result.Counts[daysAgo]})
+	}
+
+	return result, nil
+}
+
+// latestMatch returns the author date of the most recent commit matching the filter in the histories
+// of the repositories (zero if none), ignoring the commits dated after today, see Options.EndAtHead.
+func latestMatch(opts Options, today time.Time) (time.Time, error) {
+	var latest time.Time
+	end := today.AddDate(0, 0, 1)
+	for _, path := range opts.Repositories {
+		err := withRetries(opts.Retries, func() error {
+			repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+			if err != nil {
+				return fmt.Errorf("failed to open repository at %s: %w", path, err)
+			}
+
+			start, err := ResolveStart(repo, opts.Head)
+			if err != nil {
+				return err
+			}
+			iterator, err := repo.Log(&git.LogOptions{From: start})
+			if err != nil {
+				return fmt.Errorf("failed to get commit log: %w", err)
+			}
+
+			// The same checks as getCommits, but the window, which isn't known yet
+			return iterator.ForEach(func(c *object.Commit) error {
+				if !c.Author.When.After(latest) || !c.Author.When.Before(end) {
+					return nil
+				}
+				if opts.Filter.RejectReason(c.Author.Email, c.Message) != "" {
+					return nil
+				}
+				if opts.Filter.ExcludeWeekends && isWeekend(c.Author.When) {
+					return nil
+				}
+				if opts.Filter.Subdir != "" {
+					touched, err := touchesSubdir(c, opts.Filter.Subdir)
+					if err != nil {
+						return fmt.Errorf("failed to compare the trees of commit %s: %w", c.Hash.String()[:7], err)
+					}
+					if !touched {
+						return nil
+					}
+				}
+				latest = c.Author.When
+				return nil
+			})
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error processing repository at %s: %w", path, err)
+		}
+	}
+	return latest, nil
+}
+
+// SortRepositories orders the per-repository breakdown in place.
+// Ties are broken by path so the order is deterministic.
+//
+// Parameters:
+//   - repos: The per-repository breakdown to sort
+//   - order: SortByCommits, SortByPath or SortByRecent
+func SortRepositories(repos []RepoResult, order string) {
+	sort.SliceStable(repos, func(i, j int) bool {
+		a, b := repos[i], repos[j]
+
+		switch order {
+		case SortByCommits:
+			if a.Total != b.Total {
+				return a.Total > b.Total
+			}
+		case SortByRecent:
+			if !a.LastCommit.Equal(b.LastCommit) {
+				return a.LastCommit.After(b.LastCommit)
+			}
+		}
+
+		return a.Path < b.Path
+	})
+}
+
+// PrintBreakdown prints the number of commits and the most recent commit date of each repository.
+// Repositories are shown by name if they have one, see Options.RepoNames, otherwise by path.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - repos: The per-repository breakdown to print
+func PrintBreakdown(w io.Writer, repos []RepoResult) {
+	fmt.Fprintf(w, "\n")
+	for _, repo := range repos {
+		lastCommit := "-"
+		if !repo.LastCommit.IsZero() {
+			lastCommit = repo.LastCommit.Format("2006-01-02")
+		}
+		fmt.Fprintf(w, "%6d  %s  %s\n", repo.Total, lastCommit, repo.Label())
+	}
+}
@@ -0,0 +1,273 @@
+package stats
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/testutil"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// testCommit describes a commit to create in a test repository
+type testCommit struct {
+	email   string
+	message string
+	when    time.Time
+}
+
+// createTestRepo creates a Git repository in a temporary directory with the given commits
+func createTestRepo(t *testing.T, commits ...testCommit) string {
+	t.Helper()
+
+	history := make([]testutil.Commit, len(commits))
+	for i, c := range commits {
+		history[i] = testutil.Commit{Email: c.email, Message: c.message, When: c.when}
+	}
+	return testutil.NewRepo(t, history...)
+}
+
+// TestAnalyzeConcurrent tests that the result doesn't depend on the number of jobs
+func TestAnalyzeConcurrent(t *testing.T) {
+	now := time.Now()
+
+	var repos []string
+	for i := 1; i <= 5; i++ {
+		var commits []testCommit
+		for j := 0; j < i; j++ {
+			commits = append(commits, testCommit{"alice@example.com", "Commit", now.AddDate(0, 0, -j)})
+		}
+		repos = append(repos, createTestRepo(t, commits...))
+	}
+
+	for _, jobs := range []int{1, 2, 0, 10} {
+		result, err := Analyze(Options{Repositories: repos, Jobs: jobs})
+		if err != nil {
+			t.Fatalf("Jobs %d: unexpected error: %v", jobs, err)
+		}
+		if result.Summary.Total != 15 || result.Counts[0] != 5 || result.Counts[4] != 1 {
+			t.Errorf("Jobs %d: unexpected counts %d / %d / %d", jobs, result.Summary.Total, result.Counts[0], result.Counts[4])
+		}
+
+		// The breakdown keeps the order of the repositories
+		for i, repo := range result.Repositories {
+			if repo.Path != repos[i] || repo.Total != i+1 {
+				t.Errorf("Jobs %d: expected %s with %d commits, got %+v", jobs, repos[i], i+1, repo)
+			}
+		}
+	}
+
+	// Every failed repository is reported
+	first, second := t.TempDir(), t.TempDir()
+	_, err := Analyze(Options{Repositories: append([]string{first}, append(repos, second)...), Jobs: 3})
+	if err == nil || !strings.Contains(err.Error(), first) || !strings.Contains(err.Error(), second) {
+		t.Errorf("Expected an error reporting both invalid repositories, got %v", err)
+	}
+}
+
+// TestAnalyzeShallow tests that shallow clones are reported, or rejected with RequireFull
+func TestAnalyzeShallow(t *testing.T) {
+	dir := createTestRepo(t, testCommit{"alice@example.com", "Grafted commit", time.Now()})
+
+	// Full clones aren't shallow
+	result, err := Analyze(Options{Repositories: []string{dir}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Repositories[0].Shallow {
+		t.Errorf("Expected a full clone, got a shallow one")
+	}
+
+	// Graft the commit, as git clone --depth 1 does
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+	if err := repo.Storer.SetShallow([]plumbing.Hash{head.Hash()}); err != nil {
+		t.Fatalf("Failed to set shallow commits: %v", err)
+	}
+
+	result, err = Analyze(Options{Repositories: []string{dir}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Repositories[0].Shallow || result.Summary.Total != 1 {
+		t.Errorf("Expected a shallow clone with 1 commit, got %+v", result.Repositories[0])
+	}
+
+	if _, err := Analyze(Options{Repositories: []string{dir}, RequireFull: true}); err == nil {
+		t.Errorf("Expected an error for a shallow clone, got nil")
+	}
+}
+
+// TestAnalyzeBursts tests that the bursts are only added to the summary when asked for
+func TestAnalyzeBursts(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t,
+		testCommit{"alice@example.com", "First commit", now.Add(-time.Hour)},
+		testCommit{"alice@example.com", "Fixup", now.Add(-time.Hour + time.Minute)},
+		testCommit{"alice@example.com", "Next commit", now},
+	)
+
+	result, err := Analyze(Options{Repositories: []string{dir}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Summary.Bursts != nil {
+		t.Errorf("Expected no bursts without a window, got %+v", result.Summary.Bursts)
+	}
+
+	result, err = Analyze(Options{Repositories: []string{dir}, BurstWindow: DefaultBurstWindow})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if bursts := result.Summary.Bursts; bursts == nil || bursts.Clustered != 2 || bursts.Total != 3 {
+		t.Errorf("Expected 2 of 3 commits in bursts, got %+v", bursts)
+	}
+}
+
+// TestAnalyzeEndAtHead tests that the window of an archived repository can end on the day of its last matched commit
+func TestAnalyzeEndAtHead(t *testing.T) {
+	last := time.Now().AddDate(-1, 0, 0)
+	archived := createTestRepo(t,
+		testCommit{"alice@example.com", "Ancient commit", last.AddDate(-1, 0, 0)},
+		testCommit{"alice@example.com", "First commit", last.AddDate(0, 0, -10)},
+		testCommit{"alice@example.com", "Last commit", last},
+	)
+
+	// Anchored on today, the repository has no commits in the window
+	result, err := Analyze(Options{Repositories: []string{archived}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Summary.Total != 0 || !result.Until.Equal(GetBeginningOfDay(time.Now())) {
+		t.Errorf("Expected no commits until today, got %d until %v", result.Summary.Total, result.Until)
+	}
+
+	result, err = Analyze(Options{Repositories: []string{archived}, EndAtHead: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Until.Equal(GetBeginningOfDay(last)) {
+		t.Errorf("Expected the window to end on %v, got %v", GetBeginningOfDay(last), result.Until)
+	}
+	if result.Summary.Total != 2 || result.Counts[0] != 1 || result.Counts[10] != 1 {
+		t.Errorf("Expected the last commit on the last day and the first 10 days before, got %v", result.Counts)
+	}
+	if day := result.Days[len(result.Days)-1]; !day.Date.Equal(GetBeginningOfDay(last)) || day.Count != 1 {
+		t.Errorf("Expected the last day to be the day of HEAD, got %+v", day)
+	}
+
+	// The window ends on the last commit of the filtered author, not on HEAD, whatever its committer date
+	shared := createTestRepo(t,
+		testCommit{"alice@example.com", "Alice's commit", last},
+		testCommit{"bob@example.com", "Bob's commit", last.AddDate(0, 1, 0)},
+	)
+	result, err = Analyze(Options{Repositories: []string{shared}, EndAtHead: true, Filter: Filter{Emails: []string{"alice@example.com"}}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !result.Until.Equal(GetBeginningOfDay(last)) || result.Counts[0] != 1 {
+		t.Errorf("Expected the window to end on Alice's commit on %v, got %v with %v", GetBeginningOfDay(last), result.Until, result.Counts)
+	}
+}
+
+// TestAnalyze tests the Analyze function
+func TestAnalyze(t *testing.T) {
+	now := time.Now()
+	first := createTestRepo(t,
+		testCommit{"alice@example.com", "Old commit", now.AddDate(-1, 0, 0)},
+		testCommit{"alice@example.com", "First commit", now.AddDate(0, 0, -3)},
+		testCommit{"bob@example.com", "Bob's commit", now.AddDate(0, 0, -2)},
+		testCommit{"alice@example.com", "Second commit", now},
+	)
+	second := createTestRepo(t,
+		testCommit{"alice@example.com", "Other repo", now.AddDate(0, 0, -1)},
+	)
+
+	result, err := Analyze(Options{
+		Filter:       Filter{Emails: []string{"alice@example.com"}},
+		Repositories: []string{first, second},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The old commit is out of the window and bob's commit is filtered out
+	if result.Summary.Total != 3 {
+		t.Errorf("Expected 3 commits, got %d", result.Summary.Total)
+	}
+	if len(result.Repositories) != 2 || result.Repositories[0].Total != 2 || result.Repositories[1].Total != 1 {
+		t.Errorf("Expected a breakdown of 2 and 1 commits, got %+v", result.Repositories)
+	}
+	// Each commit remembers its repository, e.g. to link it on its host
+	if commits := result.Commits[0]; len(commits) != 1 || commits[0].Repository != first {
+		t.Errorf("Expected today's commit to come from %s, got %+v", first, commits)
+	}
+	// Commit dates are stored with a precision of a second
+	if result.FirstCommit.Unix() != now.AddDate(0, 0, -3).Unix() || result.LastCommit.Unix() != now.Unix() {
+		t.Errorf("Unexpected first/last commit: %v / %v", result.FirstCommit, result.LastCommit)
+	}
+
+	// The days cover the whole window, oldest first, with real dates
+	if len(result.Days) != DaysInLastSixMonths+1 {
+		t.Fatalf("Expected %d days, got %d", DaysInLastSixMonths+1, len(result.Days))
+	}
+	last := result.Days[len(result.Days)-1]
+	if !last.Date.Equal(GetBeginningOfDay(now)) || last.Count != 1 {
+		t.Errorf("Expected today with 1 commit, got %+v", last)
+	}
+
+	// Invalid repositories are reported
+	_, err = Analyze(Options{Repositories: []string{t.TempDir()}})
+	if err == nil {
+		t.Errorf("Expected an error for an invalid repository, got nil")
+	}
+}
+
+// TestSortRepositories tests the SortRepositories function
+func TestSortRepositories(t *testing.T) {
+	now := time.Now()
+	repos := []RepoResult{
+		{Path: "/b", Total: 5, LastCommit: now.AddDate(0, 0, -10)},
+		{Path: "/c", Total: 1, LastCommit: now},
+		{Path: "/a", Total: 5, LastCommit: now.AddDate(0, 0, -1)},
+	}
+
+	testCases := []struct {
+		order    string
+		expected []string
+	}{
+		{SortByCommits, []string{"/a", "/b", "/c"}},
+		{SortByPath, []string{"/a", "/b", "/c"}},
+		{SortByRecent, []string{"/c", "/a", "/b"}},
+	}
+
+	for _, tc := range testCases {
+		SortRepositories(repos, tc.order)
+		for i, path := range tc.expected {
+			if repos[i].Path != path {
+				t.Errorf("Order %s: expected %v at %d, got %s", tc.order, path, i, repos[i].Path)
+			}
+		}
+	}
+
+	// Ties on commits are broken by path
+	repos = []RepoResult{{Path: "/z", Total: 2}, {Path: "/y", Total: 2}}
+	SortRepositories(repos, SortByCommits)
+	if repos[0].Path != "/y" {
+		t.Errorf("Expected ties to be sorted by path, got %s first", repos[0].Path)
+	}
+}
+
+// TestPrintBreakdown tests that PrintBreakdown doesn't panic
+func TestPrintBreakdown(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintBreakdown(io.Discard, []RepoResult{{Path: "/a", Total: 1, LastCommit: time.Now()}, {Path: "/b"}})
+}
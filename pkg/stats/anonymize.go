@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// AnonymizeEmail replaces an email with a stable pseudonym, so graphs can be shared
+// without revealing who they belong to. The same email always gets the same pseudonym.
+//
+// Parameters:
+//   - email: The email to anonymize
+//
+// Returns:
+//   - string: The pseudonym, "author-" followed by the beginning of the email hash
+func AnonymizeEmail(email string) string {
+	sum := sha256.Sum256([]byte(NormalizeEmail(email)))
+	return "author-" + hex.EncodeToString(sum[:])[:8]
+}
+
+// AnonymizeEmails replaces each email with its pseudonym, see AnonymizeEmail.
+//
+// Parameters:
+//   - emails: The emails to anonymize
+//
+// Returns:
+//   - []string: The pseudonyms, in the same order
+func AnonymizeEmails(emails []string) []string {
+	anonymized := make([]string, len(emails))
+	for i, email := range emails {
+		anonymized[i] = AnonymizeEmail(email)
+	}
+	return anonymized
+}
+
+// AnonymizeRepositories replaces the path (and name) of each repository with "repo 1", "repo 2", ...
+// in the order they are given, keeping their commits.
+//
+// Parameters:
+//   - repos: The per-repository breakdown to anonymize in place
+func AnonymizeRepositories(repos []RepoResult) {
+	for i := range repos {
+		repos[i].Path = fmt.Sprintf("repo %d", i+1)
+		if repos[i].Name != "" {
+			repos[i].Name = repos[i].Path
+		}
+	}
+}
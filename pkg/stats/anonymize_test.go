@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestAnonymizeEmail tests the AnonymizeEmail function
+func TestAnonymizeEmail(t *testing.T) {
+	pseudonym := AnonymizeEmail("me@example.com")
+	if !strings.HasPrefix(pseudonym, "author-") || len(pseudonym) != len("author-")+8 || strings.Contains(pseudonym, "example") {
+		t.Errorf("Unexpected pseudonym %q", pseudonym)
+	}
+
+	// The pseudonym is stable, and emails are compared normalized
+	if AnonymizeEmail(" Me@Example.com ") != pseudonym {
+		t.Errorf("Expected the same pseudonym for the same email")
+	}
+	if AnonymizeEmail("you@example.com") == pseudonym {
+		t.Errorf("Expected different pseudonyms for different emails")
+	}
+
+	anonymized := AnonymizeEmails([]string{"me@example.com", "you@example.com"})
+	if len(anonymized) != 2 || anonymized[0] != pseudonym {
+		t.Errorf("Unexpected pseudonyms %v", anonymized)
+	}
+}
+
+// TestAnonymizeRepositories tests the AnonymizeRepositories function
+func TestAnonymizeRepositories(t *testing.T) {
+	repos := []RepoResult{{Path: "/home/me/secret", Total: 3}, {Path: "/home/me/other", Total: 1}}
+	AnonymizeRepositories(repos)
+
+	if repos[0].Path != "repo 1" || repos[1].Path != "repo 2" || repos[0].Total != 3 {
+		t.Errorf("Unexpected repositories %+v", repos)
+	}
+}
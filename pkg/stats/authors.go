@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// AuthorCount holds the number of commits of an author.
+type AuthorCount struct {
+	// Email is the normalized email of the author
+	Email string
+	// Count is the number of commits of the author
+	Count int
+}
+
+// CountByAuthor counts the commits of each author email, normalized so the case doesn't split an author.
+//
+// Parameters:
+//   - commits: A map of days to matched commits
+//
+// Returns:
+//   - []AuthorCount: The commits of each author, most commits first (by email on ties)
+func CountByAuthor(commits map[int][]CommitInfo) []AuthorCount {
+	counts := make(map[string]int)
+	for _, dayCommits := range commits {
+		for _, c := range dayCommits {
+			counts[NormalizeEmail(c.Email)]++
+		}
+	}
+
+	authors := make([]AuthorCount, 0, len(counts))
+	for email, count := range counts {
+		authors = append(authors, AuthorCount{Email: email, Count: count})
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Count != authors[j].Count {
+			return authors[i].Count > authors[j].Count
+		}
+		return authors[i].Email < authors[j].Email
+	})
+
+	return authors
+}
+
+// AuthorColors holds the colors of the 256-color palette assigned to authors, distinct
+// enough to tell them apart and readable on dark and light backgrounds.
+var AuthorColors = []int{33, 39, 41, 70, 99, 129, 136, 166, 169, 172, 202, 208}
+
+// authorBasicColors holds the closest basic color of each of AuthorColors, see Colors16.
+var authorBasicColors = []int{4, 12, 2, 10, 5, 13, 3, 1, 13, 3, 9, 11}
+
+// authorColorIndex returns the position of the color of an author in AuthorColors, hashed from
+// the normalized email.
+func authorColorIndex(email string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(NormalizeEmail(email)))
+	return int(h.Sum32() % uint32(len(AuthorColors)))
+}
+
+// AuthorColor returns the color of an author, hashed from the normalized email so the same
+// person keeps the same color across runs, whoever else is listed. The labels of the --per-author
+// graphs are the only author rows printed: there is no leaderboard, and --diff compares periods.
+//
+// Parameters:
+//   - email: The email of the author
+//
+// Returns:
+//   - int: The color of the author in the 256-color palette, one of AuthorColors
+func AuthorColor(email string) int {
+	return AuthorColors[authorColorIndex(email)]
+}
+
+// ColorAuthor returns the text in bold and the color of the author, see AuthorColor,
+// adapted to the color capability of the options.
+//
+// Parameters:
+//   - text: The text to color, e.g. a heading naming the author
+//   - email: The email of the author
+//
+// Returns:
+//   - string: The text wrapped in the ANSI escape sequences of the author's color, or as is without colors
+func (o RenderOptions) ColorAuthor(text string, email string) string {
+	if o.NoColor {
+		return text
+	}
+	// The text is bold already, like the bright colors of Colors8
+	i := authorColorIndex(email)
+	color := strings.TrimPrefix(o.colorSGR(AuthorColors[i], authorBasicColors[i], false), "1;")
+	return fmt.Sprintf("\033[1;%sm%s\033[0m", color, text)
+}
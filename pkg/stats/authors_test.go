@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// TestCountByAuthor tests the CountByAuthor function
+func TestCountByAuthor(t *testing.T) {
+	commits := map[int][]CommitInfo{
+		0: {{Email: "Me@Example.com"}, {Email: "bob@example.com"}},
+		3: {{Email: "me@example.com"}, {Email: "alice@example.com"}, {Email: "bob@example.com"}},
+		9: {{Email: "me@example.com "}},
+	}
+
+	result := CountByAuthor(commits)
+	expected := []AuthorCount{
+		{Email: "me@example.com", Count: 3},
+		{Email: "bob@example.com", Count: 2},
+		{Email: "alice@example.com", Count: 1},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if result := CountByAuthor(nil); len(result) != 0 {
+		t.Errorf("Expected no authors, got %v", result)
+	}
+}
+
+// TestAuthorColor tests that the color of an author is stable
+func TestAuthorColor(t *testing.T) {
+	color := AuthorColor("me@example.com")
+
+	// The case and surrounding spaces don't change the author
+	if other := AuthorColor(" Me@Example.com"); other != color {
+		t.Errorf("Expected the same color for the same author, got %d and %d", color, other)
+	}
+
+	// Every author gets one of the palette colors
+	for _, email := range []string{"alice@example.com", "bob@example.com", ""} {
+		found := false
+		for _, c := range AuthorColors {
+			found = found || c == AuthorColor(email)
+		}
+		if !found {
+			t.Errorf("Expected a color of the palette for %q, got %d", email, AuthorColor(email))
+		}
+	}
+}
+
+// TestColorAuthor tests the ColorAuthor function
+func TestColorAuthor(t *testing.T) {
+	if text := (RenderOptions{NoColor: true}).ColorAuthor("me", "me@example.com"); text != "me" {
+		t.Errorf("Expected the text as is without colors, got %q", text)
+	}
+
+	expected := fmt.Sprintf("\033[1;38;5;%dmme\033[0m", AuthorColor("me@example.com"))
+	if text := (RenderOptions{}).ColorAuthor("me", "me@example.com"); text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	// The 8 basic colors stay within the codes 30-37, the bright red of the author falling back to bold red
+	expected = "\033[1;31mme\033[0m"
+	if text := (RenderOptions{Colors: Colors8}).ColorAuthor("me", "me@example.com"); text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+
+	// The bright variants are used with 16 colors
+	expected = "\033[1;91mme\033[0m"
+	if text := (RenderOptions{Colors: Colors16}).ColorAuthor("me", "me@example.com"); text != expected {
+		t.Errorf("Expected %q, got %q", expected, text)
+	}
+}
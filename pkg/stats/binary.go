@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+)
+
+// BinaryCounts reduces the commit counts to whether each day is active: one for a day
+// with commits, whatever their number, and nothing for the other days.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//
+// Returns:
+//   - map[int]int: A map of the active days to 1
+func BinaryCounts(commits map[int]int) map[int]int {
+	active := make(map[int]int, len(commits))
+	for day, count := range commits {
+		if count > 0 {
+			active[day] = 1
+		}
+	}
+	return active
+}
+
+// printBinaryLegend prints a legend mapping the two cells of the binary graph to inactive and active days.
+func printBinaryLegend(w io.Writer, opts RenderOptions) {
+	fmt.Fprintf(w, "\n     ")
+	for _, level := range []int{LevelNone, LevelHigh} {
+		label := "inactive"
+		if level == LevelHigh {
+			label = "active"
+		}
+
+		if opts.blocks() {
+			fmt.Fprintf(w, "%s███%s %s  ", opts.levelForeground(level), opts.reset(), label)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s %s %s  ", opts.levelEscape(level), opts.glyph(level), opts.reset(), label)
+	}
+	fmt.Fprintf(w, "days\n")
+}
+
+// PrintBinarySummary prints the summary below the binary graph, counting days rather than commits.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - summary: The summary to print
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+func PrintBinarySummary(w io.Writer, summary Summary, window GraphRange) {
+	days := window.orDefault().Days + 1
+
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "Active days:    %d of %d (%d%%)\n", summary.ActiveDays, days, summary.ActiveDays*100/days)
+	fmt.Fprintf(w, "Current streak: %d days\n", summary.CurrentStreak)
+	fmt.Fprintf(w, "Longest streak: %d days\n", summary.LongestStreak)
+	if summary.LongestGap > 0 {
+		fmt.Fprintf(w, "Longest gap:    %d days (%s to %s)\n", summary.LongestGap,
+			summary.GapStart.Format("2006-01-02"), summary.GapEnd.Format("2006-01-02"))
+	}
+	printBursts(w, summary)
+}
@@ -0,0 +1,38 @@
+package stats
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+// TestBinaryCounts tests the BinaryCounts function
+func TestBinaryCounts(t *testing.T) {
+	commits := map[int]int{0: 12, 3: 1, 5: 0, 9: 4}
+
+	expected := map[int]int{0: 1, 3: 1, 9: 1}
+	if result := BinaryCounts(commits); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestBinaryLevel tests that any number of commits gets the same color
+func TestBinaryLevel(t *testing.T) {
+	opts := RenderOptions{Binary: true}
+	for _, count := range []int{1, 4, 25} {
+		if level := opts.level(count); level != LevelHigh {
+			t.Errorf("Expected %d commits to be LevelHigh, got %d", count, level)
+		}
+	}
+	if level := opts.level(0); level != LevelNone {
+		t.Errorf("Expected no commits to be LevelNone, got %d", level)
+	}
+}
+
+// TestPrintBinarySummary tests that PrintBinarySummary and the legend don't panic
+func TestPrintBinarySummary(t *testing.T) {
+	// This test just ensures the functions don't panic
+	PrintBinarySummary(io.Discard, Summary{ActiveDays: 40, CurrentStreak: 2, LongestStreak: 9}, DefaultGraphRange)
+	PrintLegend(io.Discard, RenderOptions{Binary: true})
+	PrintLegend(io.Discard, RenderOptions{Binary: true, Style: StyleBlocks})
+}
@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Color capabilities of the terminals the graph is printed on
+const (
+	// Colors8 uses the 8 basic ANSI colors only (SGR codes 30-37 and 40-47), e.g. on the Linux console,
+	// the bright variants falling back to bold in the foreground and to the basic color in the background
+	Colors8 = "8"
+	// Colors16 uses the 8 basic ANSI colors and their bright variants (SGR codes 90-97 and 100-107)
+	Colors16 = "16"
+	// Colors256 uses the 256-color palette of xterm
+	Colors256 = "256"
+	// ColorsTrueColor uses 24-bit RGB colors
+	ColorsTrueColor = "truecolor"
+)
+
+// ColorDepths lists the supported color capabilities, for validation and completion.
+var ColorDepths = []string{Colors8, Colors16, Colors256, ColorsTrueColor}
+
+// basicTerms lists the terminal types whose terminfo entries only describe 8 colors. Plain xterm
+// and screen are left out: their terminfo entries describe 8 colors too, but the terminals behind
+// them (and most emulators setting them, e.g. in containers and over SSH) support the 256-color palette.
+var basicTerms = map[string]bool{
+	"ansi": true, "cons25": true, "eterm": true, "linux": true, "putty": true, "rxvt": true,
+}
+
+// DetectColors guesses the color capability of the terminal from its environment, the way
+// terminfo names describe it: COLORTERM=truecolor (or 24bit) announces 24-bit colors, TERM=*-256color
+// the 256-color palette, TERM=*-16color the bright colors, and the plain names of old terminals
+// (e.g. linux, vt100) 8 colors. Unknown or missing terminal types, and plain xterm and screen,
+// keep the 256-color palette, which modern terminals all support.
+//
+// Parameters:
+//   - term: The value of the TERM environment variable
+//   - colorTerm: The value of the COLORTERM environment variable
+//
+// Returns:
+//   - string: Colors8, Colors16, Colors256 or ColorsTrueColor
+func DetectColors(term string, colorTerm string) string {
+	colorTerm = strings.ToLower(strings.TrimSpace(colorTerm))
+	if colorTerm == "truecolor" || colorTerm == "24bit" {
+		return ColorsTrueColor
+	}
+
+	term = strings.ToLower(strings.TrimSpace(term))
+	switch {
+	case strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor") || strings.Contains(term, "24bit"):
+		return ColorsTrueColor
+	case strings.Contains(term, "256color"):
+		return Colors256
+	case strings.HasSuffix(term, "-16color"):
+		return Colors16
+	case basicTerms[term] || strings.HasPrefix(term, "vt") ||
+		strings.HasSuffix(term, "-color") || strings.HasSuffix(term, "-8color"):
+		return Colors8
+	}
+	return Colors256
+}
+
+// colorSGR returns the parameters of the SGR escape sequence selecting a color of the 256-color palette
+// in the foreground or the background, e.g. "48;5;34", adapted to the color capability of the options:
+// the given basic color (0 to 15, 8 to 15 being the bright variants) with Colors8 and Colors16,
+// or the RGB value of the palette color with ColorsTrueColor.
+func (o RenderOptions) colorSGR(index int, basic int, background bool) string {
+	switch o.Colors {
+	case Colors8:
+		if background {
+			return fmt.Sprintf("%d", 40+basic%8)
+		}
+		if basic >= 8 {
+			return fmt.Sprintf("1;%d", 30+basic%8)
+		}
+		return fmt.Sprintf("%d", 30+basic)
+	case Colors16:
+		code := 30 + basic
+		if basic >= 8 {
+			code = 90 + basic - 8
+		}
+		if background {
+			code += 10
+		}
+		return fmt.Sprintf("%d", code)
+	case ColorsTrueColor:
+		rgb := paletteColor(index)
+		if background {
+			return fmt.Sprintf("48;2;%d;%d;%d", rgb.R, rgb.G, rgb.B)
+		}
+		return fmt.Sprintf("38;2;%d;%d;%d", rgb.R, rgb.G, rgb.B)
+	}
+
+	if background {
+		return fmt.Sprintf("48;5;%d", index)
+	}
+	return fmt.Sprintf("38;5;%d", index)
+}
+
+// basicColorsRepeat reports whether two intensity levels of the color scheme get the same basic color
+// with the color capability of the options, e.g. the two darkest greens of github, as there are only
+// two greens (and a single one in the background of Colors8). The glyphs then tell the levels apart.
+func (o RenderOptions) basicColorsRepeat() bool {
+	if o.Colors != Colors8 && o.Colors != Colors16 {
+		return false
+	}
+
+	basic := o.colorScheme().Basic
+	for level := LevelNone + 1; level < len(basic); level++ {
+		previous, current := basic[level-1], basic[level]
+		if o.Colors == Colors8 {
+			previous, current = previous%8, current%8
+		}
+		if previous == current {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package stats
+
+import "testing"
+
+// TestDetectColors tests that the color capability is guessed from TERM and COLORTERM
+func TestDetectColors(t *testing.T) {
+	tests := []struct {
+		term      string
+		colorTerm string
+		expected  string
+	}{
+		{"xterm-256color", "truecolor", ColorsTrueColor},
+		{"xterm-256color", "24bit", ColorsTrueColor},
+		{"xterm-direct", "", ColorsTrueColor},
+		{"xterm-256color", "", Colors256},
+		{"screen-256color", "", Colors256},
+		{"linux", "", Colors8},
+		{"xterm", "", Colors256},
+		{"screen", "", Colors256},
+		{"vt100", "", Colors8},
+		{"xterm-color", "", Colors8},
+		{"rxvt-16color", "", Colors16},
+		{"rxvt", "", Colors8},
+		{"", "", Colors256},
+		{"alacritty", "", Colors256},
+	}
+
+	for _, test := range tests {
+		if colors := DetectColors(test.term, test.colorTerm); colors != test.expected {
+			t.Errorf("DetectColors(%q, %q): expected %q, got %q", test.term, test.colorTerm, test.expected, colors)
+		}
+	}
+}
+
+// TestColorSGR tests the color parameters at each color capability
+func TestColorSGR(t *testing.T) {
+	tests := []struct {
+		colors     string
+		index      int
+		basic      int
+		background bool
+		expected   string
+	}{
+		{"", 34, 2, true, "48;5;34"},
+		{Colors256, 34, 2, false, "38;5;34"},
+		{Colors8, 34, 2, true, "42"},
+		{Colors8, 34, 2, false, "32"},
+		{Colors8, 248, 7, true, "47"},
+		{Colors8, 33, 4, false, "34"},
+		{Colors8, 120, 10, true, "42"},
+		{Colors8, 120, 10, false, "1;32"},
+		{Colors16, 120, 10, true, "102"},
+		{Colors16, 120, 10, false, "92"},
+		{Colors16, 34, 2, true, "42"},
+		{ColorsTrueColor, 34, 2, true, "48;2;0;175;0"},
+		{ColorsTrueColor, 248, 7, false, "38;2;168;168;168"},
+	}
+
+	for _, test := range tests {
+		o := RenderOptions{Colors: test.colors}
+		if sgr := o.colorSGR(test.index, test.basic, test.background); sgr != test.expected {
+			t.Errorf("colorSGR(%d, %d, %v) with %q: expected %q, got %q", test.index, test.basic, test.background, test.colors, test.expected, sgr)
+		}
+	}
+}
+
+// TestBasicColorsRepeat tests that the glyphs tell apart the levels sharing a basic color
+func TestBasicColorsRepeat(t *testing.T) {
+	tests := []struct {
+		colors   string
+		scheme   string
+		expected bool
+	}{
+		{Colors256, "github", false},
+		{ColorsTrueColor, "github", false},
+		{Colors16, "github", true},
+		{Colors16, "halloween", false},
+		{Colors8, "halloween", true},
+		{Colors16, "dracula", false},
+	}
+
+	for _, test := range tests {
+		o := RenderOptions{Colors: test.colors, ColorScheme: test.scheme}
+		if repeat := o.basicColorsRepeat(); repeat != test.expected {
+			t.Errorf("basicColorsRepeat() of %s with %q: expected %v, got %v", test.scheme, test.colors, test.expected, repeat)
+		}
+	}
+
+	// The levels of the green ramp are told apart by their glyphs with 16 colors only
+	if glyph := (RenderOptions{Colors: Colors16}).glyph(LevelHigh); glyph != DefaultGlyphs[LevelHigh] {
+		t.Errorf("Expected the glyph %q with 16 colors, got %q", DefaultGlyphs[LevelHigh], glyph)
+	}
+	if glyph := (RenderOptions{}).glyph(LevelHigh); glyph != " " {
+		t.Errorf("Expected no glyph with 256 colors, got %q", glyph)
+	}
+}
@@ -0,0 +1,205 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// Periods the current one can be compared to the previous one of, see ParseComparison
+const (
+	CompareWeek    = "week"
+	CompareMonth   = "month"
+	CompareQuarter = "quarter"
+)
+
+// CompareModes lists the periods accepted by ParseComparison besides explicit ranges, for validation and completion.
+var CompareModes = []string{CompareWeek, CompareMonth, CompareQuarter}
+
+// DateRange is a range of days, both ends included.
+type DateRange struct {
+	// From is the beginning of the first day of the range
+	From time.Time
+	// To is the beginning of the last day of the range
+	To time.Time
+}
+
+// String formats the range as FROM..TO, the syntax ParseComparison accepts.
+func (r DateRange) String() string {
+	return r.From.Format("2006-01-02") + ".." + r.To.Format("2006-01-02")
+}
+
+// Days returns the number of days of the range.
+func (r DateRange) Days() int {
+	return int(r.To.Sub(r.From).Hours()/HoursInDay) + 1
+}
+
+// PeriodStats holds the activity of one of the compared periods.
+type PeriodStats struct {
+	// Range is the days of the period
+	Range DateRange
+	// Commits is the number of commits made during the period
+	Commits int
+	// ActiveDays is the number of days of the period with at least one commit
+	ActiveDays int
+}
+
+// PerDay returns the average number of commits per day of the period, so periods of
+// different lengths (e.g. the month so far and the whole last month) can be compared.
+func (p PeriodStats) PerDay() float64 {
+	return float64(p.Commits) / float64(p.Range.Days())
+}
+
+// Comparison holds the activity of two periods, see ComparePeriods.
+type Comparison struct {
+	// Previous is the period compared to
+	Previous PeriodStats
+	// Current is the period compared
+	Current PeriodStats
+}
+
+// ParseComparison parses the periods to compare: "week", "month" or "quarter" compare the current one
+// so far to the whole previous one, and "FROM..TO,FROM..TO" (YYYY-MM-DD) compares the second range to the first.
+// Both ranges must fall within the graph window.
+//
+// Parameters:
+//   - spec: The periods to compare
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+//
+// Returns:
+//   - DateRange: The previous period
+//   - DateRange: The current period
+//   - error: An error if the periods are malformed or outside of the graph window
+func ParseComparison(spec string, window GraphRange) (DateRange, DateRange, error) {
+	return parseComparison(spec, GetBeginningOfDay(time.Now()), window.orDefault())
+}
+
+// parseComparison parses the periods to compare relative to the given day, see ParseComparison.
+func parseComparison(spec string, today time.Time, window GraphRange) (DateRange, DateRange, error) {
+	var previous, current DateRange
+	switch spec {
+	case CompareWeek:
+		start := today.AddDate(0, 0, -int(today.Weekday()))
+		current = DateRange{From: start, To: today}
+		previous = DateRange{From: start.AddDate(0, 0, -DaysInWeek), To: start.AddDate(0, 0, -1)}
+	case CompareMonth:
+		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		current = DateRange{From: start, To: today}
+		previous = DateRange{From: start.AddDate(0, -1, 0), To: start.AddDate(0, 0, -1)}
+	case CompareQuarter:
+		firstMonth := time.Month((int(today.Month())-1)/3*3 + 1)
+		start := time.Date(today.Year(), firstMonth, 1, 0, 0, 0, 0, today.Location())
+		current = DateRange{From: start, To: today}
+		previous = DateRange{From: start.AddDate(0, -3, 0), To: start.AddDate(0, 0, -1)}
+	default:
+		first, second, ok := strings.Cut(spec, ",")
+		if !ok {
+			return DateRange{}, DateRange{}, fmt.Errorf("invalid periods %q, expected %s or FROM..TO,FROM..TO", spec, strings.Join(CompareModes, ", "))
+		}
+
+		var err error
+		if previous, err = parseDateRange(first); err != nil {
+			return DateRange{}, DateRange{}, err
+		}
+		if current, err = parseDateRange(second); err != nil {
+			return DateRange{}, DateRange{}, err
+		}
+	}
+
+	// The counts only cover the graph window
+	windowStart := today.AddDate(0, 0, -window.Days)
+	for _, r := range []DateRange{previous, current} {
+		if r.From.Before(windowStart) || r.To.After(today) {
+			return DateRange{}, DateRange{}, fmt.Errorf("the period %s is outside of the graph window (%s..%s)",
+				r, windowStart.Format("2006-01-02"), today.Format("2006-01-02"))
+		}
+	}
+
+	return previous, current, nil
+}
+
+// parseDateRange parses a range of days written FROM..TO (YYYY-MM-DD).
+func parseDateRange(spec string) (DateRange, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(spec), "..")
+	if !ok {
+		return DateRange{}, fmt.Errorf("invalid period %q, expected FROM..TO (YYYY-MM-DD)", spec)
+	}
+
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid period %q, expected FROM..TO (YYYY-MM-DD)", spec)
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return DateRange{}, fmt.Errorf("invalid period %q, expected FROM..TO (YYYY-MM-DD)", spec)
+	}
+	if toDate.Before(fromDate) {
+		return DateRange{}, fmt.Errorf("invalid period %q, it ends before it starts", spec)
+	}
+
+	return DateRange{From: fromDate, To: toDate}, nil
+}
+
+// ComparePeriods sums the commits and active days of two periods of the graph window.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//   - previous: The period compared to
+//   - current: The period compared
+//
+// Returns:
+//   - Comparison: The activity of both periods
+func ComparePeriods(commits map[int]int, previous DateRange, current DateRange) Comparison {
+	return comparePeriods(commits, previous, current, GetBeginningOfDay(time.Now()))
+}
+
+// comparePeriods sums the activity of the periods relative to the given day, see ComparePeriods.
+func comparePeriods(commits map[int]int, previous DateRange, current DateRange, today time.Time) Comparison {
+	period := func(r DateRange) PeriodStats {
+		p := PeriodStats{Range: r}
+		for day := r.From; !day.After(r.To); day = day.AddDate(0, 0, 1) {
+			count := commits[daysBetween(GetBeginningOfDay(day), today)]
+			p.Commits += count
+			if count > 0 {
+				p.ActiveDays++
+			}
+		}
+		return p
+	}
+
+	return Comparison{Previous: period(previous), Current: period(current)}
+}
+
+// formatChange formats the change from one value to another in percent, e.g. "+25%",
+// or "n/a" when the first value is zero.
+func formatChange(from float64, to float64) string {
+	if from == 0 {
+		return "n/a"
+	}
+
+	change := math.Round((to - from) / from * 100)
+	if change > 0 {
+		return fmt.Sprintf("+%.0f%%", change)
+	}
+	return fmt.Sprintf("%.0f%%", change)
+}
+
+// PrintComparison prints the activity of two periods side by side, with the change in percent.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - comparison: The periods to print
+func PrintComparison(w io.Writer, comparison Comparison) {
+	previous, current := comparison.Previous, comparison.Current
+
+	fmt.Fprintf(w, "%-12s %-24s %-24s %s\n", "", "Previous", "Current", "Change")
+	fmt.Fprintf(w, "%-12s %-24s %s\n", "Period", previous.Range, current.Range)
+	fmt.Fprintf(w, "%-12s %-24d %-24d %s\n", "Commits", previous.Commits, current.Commits,
+		formatChange(float64(previous.Commits), float64(current.Commits)))
+	fmt.Fprintf(w, "%-12s %-24d %-24d %s\n", "Active days", previous.ActiveDays, current.ActiveDays,
+		formatChange(float64(previous.ActiveDays), float64(current.ActiveDays)))
+	fmt.Fprintf(w, "%-12s %-24.2f %-24.2f %s\n", "Per day", previous.PerDay(), current.PerDay(),
+		formatChange(previous.PerDay(), current.PerDay()))
+}
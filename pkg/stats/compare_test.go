@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// TestParseComparison tests the periods compared by ParseComparison
+func TestParseComparison(t *testing.T) {
+	// A Wednesday
+	today := time.Date(2023, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		spec     string
+		previous string
+		current  string
+	}{
+		{CompareWeek, "2023-05-07..2023-05-13", "2023-05-14..2023-05-17"},
+		{CompareMonth, "2023-04-01..2023-04-30", "2023-05-01..2023-05-17"},
+		{CompareQuarter, "2023-01-01..2023-03-31", "2023-04-01..2023-05-17"},
+		{"2023-03-01..2023-03-31, 2023-04-01..2023-04-15", "2023-03-01..2023-03-31", "2023-04-01..2023-04-15"},
+	}
+
+	for _, tc := range testCases {
+		previous, current, err := parseComparison(tc.spec, today, DefaultGraphRange)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tc.spec, err)
+			continue
+		}
+		if previous.String() != tc.previous || current.String() != tc.current {
+			t.Errorf("%q: expected %s and %s, got %s and %s", tc.spec, tc.previous, tc.current, previous, current)
+		}
+	}
+
+	// Malformed periods and periods outside of the window are rejected
+	for _, spec := range []string{"year", "2023-04-01..2023-04-30", "2023-04-30..2023-04-01,2023-05-01..2023-05-02", "2022-01-01..2022-01-31,2023-05-01..2023-05-02", "2023-05-01..2023-05-20,2023-05-01..2023-05-02"} {
+		if _, _, err := parseComparison(spec, today, DefaultGraphRange); err == nil {
+			t.Errorf("%q: expected an error, got nil", spec)
+		}
+	}
+}
+
+// TestComparePeriods tests the ComparePeriods function
+func TestComparePeriods(t *testing.T) {
+	today := time.Date(2023, 5, 17, 0, 0, 0, 0, time.UTC)
+	previous, current, err := parseComparison(CompareWeek, today, DefaultGraphRange)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 2 commits on Wednesday and Friday of last week, 3 on Monday and today
+	commits := map[int]int{10: 1, 8: 1, 2: 1, 0: 2, 30: 5}
+	comparison := comparePeriods(commits, previous, current, today)
+
+	if comparison.Previous.Commits != 2 || comparison.Previous.ActiveDays != 2 {
+		t.Errorf("Expected 2 commits on 2 days last week, got %+v", comparison.Previous)
+	}
+	if comparison.Current.Commits != 3 || comparison.Current.ActiveDays != 2 {
+		t.Errorf("Expected 3 commits on 2 days this week, got %+v", comparison.Current)
+	}
+	if perDay := comparison.Current.PerDay(); perDay != 0.75 {
+		t.Errorf("Expected 0.75 commits per day over the 4 days of this week, got %v", perDay)
+	}
+
+	// This test just ensures the function doesn't panic
+	PrintComparison(io.Discard, comparison)
+}
+
+// TestFormatChange tests the formatChange function
+func TestFormatChange(t *testing.T) {
+	testCases := []struct {
+		from     float64
+		to       float64
+		expected string
+	}{
+		{4, 5, "+25%"},
+		{4, 3, "-25%"},
+		{4, 4, "0%"},
+		{0, 3, "n/a"},
+	}
+
+	for _, tc := range testCases {
+		if result := formatChange(tc.from, tc.to); result != tc.expected {
+			t.Errorf("formatChange(%v, %v) = %q, expected %q", tc.from, tc.to, result, tc.expected)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+)
+
+// Deltas computes the day-over-day change of the commit counts: each day gets its count
+// minus the count of the day before. The first day of the window has no day before
+// in the graph, so it is reported as unchanged (zero) rather than as a ramp from nothing.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+//
+// Returns:
+//   - map[int]int: A map of days to the change from the day before, negative for drops
+func Deltas(commits map[int]int, window GraphRange) map[int]int {
+	window = window.orDefault()
+	deltas := make(map[int]int, window.Days+1)
+	deltas[window.Days] = 0
+	for daysAgo := window.Days - 1; daysAgo >= 0; daysAgo-- {
+		deltas[daysAgo] = commits[daysAgo] - commits[daysAgo+1]
+	}
+	return deltas
+}
+
+// deltaDown and deltaUp hold the palette indexes of the drops (reds) and ramps (greens),
+// by the intensity level of the magnitude of the change. LevelNone is an unchanged day.
+var (
+	deltaDown = [4]int{248, 217, 203, 160}
+	deltaUp   = [4]int{248, 120, 34, 22}
+)
+
+// deltaDownBasic and deltaUpBasic hold the closest basic colors of deltaDown and deltaUp, see Colors16.
+var (
+	deltaDownBasic = [4]int{7, 9, 1, 1}
+	deltaUpBasic   = [4]int{7, 10, 2, 2}
+)
+
+// deltaLevel returns the intensity level of the magnitude of a change, see Level.
+func deltaLevel(delta int) int {
+	if delta < 0 {
+		return Level(-delta)
+	}
+	return Level(delta)
+}
+
+// deltaColor returns the palette index of a cell showing a change on the diverging scale.
+func deltaColor(delta int) int {
+	if delta < 0 {
+		return deltaDown[deltaLevel(delta)]
+	}
+	return deltaUp[deltaLevel(delta)]
+}
+
+// deltaBasicColor returns the closest basic color of deltaColor, see Colors16.
+func deltaBasicColor(delta int) int {
+	if delta < 0 {
+		return deltaDownBasic[deltaLevel(delta)]
+	}
+	return deltaUpBasic[deltaLevel(delta)]
+}
+
+// deltaEscape returns the ANSI escape sequence coloring the background of a cell showing a change.
+func (o RenderOptions) deltaEscape(delta int) string {
+	if o.NoColor {
+		return ""
+	}
+
+	background := o.colorSGR(deltaColor(delta), deltaBasicColor(delta), true)
+	if delta == 0 {
+		return "\033[0;37;" + background + "m"
+	}
+	return "\033[1;30;" + background + "m"
+}
+
+// deltaForeground returns the ANSI escape sequence coloring the blocks of a cell showing a change.
+func (o RenderOptions) deltaForeground(delta int) string {
+	if o.NoColor {
+		return ""
+	}
+
+	return "\033[" + o.colorSGR(deltaColor(delta), deltaBasicColor(delta), false) + "m"
+}
+
+// deltaGlyph returns the character drawn in a cell showing a change: the direction of the change
+// with glyphs or without colors, as the colors alone tell it otherwise.
+func (o RenderOptions) deltaGlyph(delta int) string {
+	switch {
+	case delta == 0 && o.EmptyCell != "":
+		return o.EmptyCell
+	case delta == 0, !o.Glyphs && !o.NoColor:
+		return " "
+	case delta < 0:
+		return "-"
+	default:
+		return "+"
+	}
+}
+
+// printDeltaLegend prints a legend mapping the cells of the diverging scale to the change of commits,
+// from the largest drops to the largest ramps.
+func printDeltaLegend(w io.Writer, opts RenderOptions) {
+	labels := []string{"0", "1-4", "5-9", "10+"}
+	deltas := []int{-10, -5, -1, 0, 1, 5, 10}
+
+	fmt.Fprintf(w, "\n     ")
+	for _, delta := range deltas {
+		label := labels[deltaLevel(delta)]
+		switch {
+		case delta < 0:
+			label = "-" + label
+		case delta > 0:
+			label = "+" + label
+		}
+
+		if opts.blocks() {
+			fmt.Fprintf(w, "%s███%s %s  ", opts.deltaForeground(delta), opts.reset(), label)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s %s %s  ", opts.deltaEscape(delta), opts.deltaGlyph(delta), opts.reset(), label)
+	}
+	fmt.Fprintf(w, "commits vs the day before\n")
+}
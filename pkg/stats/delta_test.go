@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// TestDeltas tests the Deltas function
+func TestDeltas(t *testing.T) {
+	commits := map[int]int{DaysInLastSixMonths: 4, 3: 2, 2: 5, 0: 1}
+	deltas := Deltas(commits, DefaultGraphRange)
+
+	if len(deltas) != DaysInLastSixMonths+1 {
+		t.Fatalf("Expected a change for every day of the window, got %d", len(deltas))
+	}
+
+	testCases := []struct {
+		daysAgo  int
+		expected int
+	}{
+		{DaysInLastSixMonths, 0}, // No day before, unchanged
+		{DaysInLastSixMonths - 1, -4},
+		{3, 2},
+		{2, 3},
+		{1, -5},
+		{0, 1},
+	}
+	for _, tc := range testCases {
+		if deltas[tc.daysAgo] != tc.expected {
+			t.Errorf("Expected a change of %d %d days ago, got %d", tc.expected, tc.daysAgo, deltas[tc.daysAgo])
+		}
+	}
+}
+
+// TestDeltaColor tests that drops and ramps are on both sides of the diverging scale
+func TestDeltaColor(t *testing.T) {
+	if deltaColor(0) != deltaUp[LevelNone] || deltaColor(0) != deltaDown[LevelNone] {
+		t.Errorf("Expected unchanged days to be neutral, got %d", deltaColor(0))
+	}
+	if deltaColor(-12) != deltaDown[LevelHigh] {
+		t.Errorf("Expected a large drop to be %d, got %d", deltaDown[LevelHigh], deltaColor(-12))
+	}
+	if deltaColor(3) != deltaUp[LevelLow] {
+		t.Errorf("Expected a small ramp to be %d, got %d", deltaUp[LevelLow], deltaColor(3))
+	}
+}
+
+// TestDeltaGlyph tests the direction shown without colors
+func TestDeltaGlyph(t *testing.T) {
+	opts := RenderOptions{NoColor: true}
+	if opts.deltaGlyph(-2) != "-" || opts.deltaGlyph(2) != "+" || opts.deltaGlyph(0) != " " {
+		t.Errorf("Expected -, + and a space, got %q, %q and %q", opts.deltaGlyph(-2), opts.deltaGlyph(2), opts.deltaGlyph(0))
+	}
+
+	// The colors tell the direction
+	if glyph := (RenderOptions{}).deltaGlyph(2); glyph != " " {
+		t.Errorf("Expected a space with colors, got %q", glyph)
+	}
+}
+
+// TestPrintDelta tests that the delta cells and legend don't panic
+func TestPrintDelta(t *testing.T) {
+	// This test just ensures the functions don't panic
+	for _, opts := range []RenderOptions{{Delta: true}, {Delta: true, NoColor: true}, {Delta: true, Style: StyleBlocks}, {Delta: true, Compact: true}} {
+		PrintCell(io.Discard, -3, false, time.Now(), opts)
+		PrintCell(io.Discard, 7, true, time.Now(), opts)
+		PrintLegend(io.Discard, opts)
+	}
+}
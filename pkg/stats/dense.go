@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// sparkBars holds the bars of a sparkline, from the lowest to the highest non-zero value.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline draws one bar per value, scaled to the given maximum. Zero values are blank,
+// so weeks without commits stand out.
+//
+// Parameters:
+//   - values: The values to draw
+//   - maxValue: The value drawn as the highest bar (if zero, the highest of values)
+//
+// Returns:
+//   - string: The sparkline, one character per value
+func Sparkline(values []int, maxValue int) string {
+	if maxValue <= 0 {
+		for _, value := range values {
+			maxValue = max(maxValue, value)
+		}
+	}
+
+	var sb strings.Builder
+	for _, value := range values {
+		if value <= 0 || maxValue <= 0 {
+			sb.WriteRune(' ')
+			continue
+		}
+		// The highest value gets the highest bar, any other non-zero value at least the lowest
+		bar := (min(value, maxValue)*len(sparkBars)+maxValue-1)/maxValue - 1
+		sb.WriteRune(sparkBars[bar])
+	}
+	return sb.String()
+}
+
+// TopRepositories returns the n repositories with the most commits, most first.
+// Ties are broken by path so the selection is deterministic.
+//
+// Parameters:
+//   - repos: The per-repository breakdown, left untouched
+//   - n: The number of repositories to keep (if zero or less, all of them)
+//
+// Returns:
+//   - []RepoResult: The most active repositories
+func TopRepositories(repos []RepoResult, n int) []RepoResult {
+	top := append([]RepoResult(nil), repos...)
+	SortRepositories(top, SortByCommits)
+	if n > 0 && n < len(top) {
+		top = top[:n]
+	}
+	return top
+}
+
+// weeklySeries returns the weekly totals of a repository, oldest week first.
+func weeklySeries(counts map[int]int, opts RenderOptions) []int {
+	totals := WeeklyCounts(counts, opts)
+
+	series := make([]int, 0, opts.window().Weeks+1)
+	for week := opts.window().Weeks; week >= 0; week-- {
+		series = append(series, totals[week])
+	}
+	return series
+}
+
+// PrintSmallMultiples prints one compact strip per repository, stacked: its name,
+// a sparkline of its weekly commits and its total. The strips share the same scale,
+// so the repositories can be compared at a glance.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - repos: The repositories to print, in order
+//   - opts: The options controlling how the graph is rendered
+func PrintSmallMultiples(w io.Writer, repos []RepoResult, opts RenderOptions) {
+	series := make([][]int, len(repos))
+	highest, width := 0, 0
+	for i, repo := range repos {
+		series[i] = weeklySeries(repo.Counts, opts)
+		for _, value := range series[i] {
+			highest = max(highest, value)
+		}
+		width = max(width, utf8.RuneCountInString(repo.Label()))
+	}
+
+	for i, repo := range repos {
+		label := repo.Label()
+		padding := strings.Repeat(" ", width-utf8.RuneCountInString(label))
+		fmt.Fprintf(w, "%s%s  %s  %d\n", label, padding, Sparkline(series[i], highest), repo.Total)
+	}
+}
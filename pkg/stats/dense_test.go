@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"io"
+	"reflect"
+	"testing"
+)
+
+// TestSparkline tests the Sparkline function
+func TestSparkline(t *testing.T) {
+	testCases := []struct {
+		values   []int
+		maxValue int
+		expected string
+	}{
+		{[]int{0, 1, 2, 4, 8}, 0, " ▁▂▄█"},
+		{[]int{1, 1}, 0, "██"},
+		{[]int{1, 16}, 8, "▁█"},
+		{[]int{0, 0}, 0, "  "},
+	}
+
+	for _, tc := range testCases {
+		if result := Sparkline(tc.values, tc.maxValue); result != tc.expected {
+			t.Errorf("Sparkline(%v, %d): expected %q, got %q", tc.values, tc.maxValue, tc.expected, result)
+		}
+	}
+}
+
+// TestTopRepositories tests the TopRepositories function
+func TestTopRepositories(t *testing.T) {
+	repos := []RepoResult{{Path: "/a", Total: 1}, {Path: "/b", Total: 5}, {Path: "/c", Total: 3}}
+
+	top := TopRepositories(repos, 2)
+	expected := []RepoResult{{Path: "/b", Total: 5}, {Path: "/c", Total: 3}}
+	if !reflect.DeepEqual(top, expected) {
+		t.Errorf("Expected %v, got %v", expected, top)
+	}
+	if repos[0].Path != "/a" {
+		t.Errorf("Expected the given repositories to be left untouched, got %v", repos)
+	}
+
+	if top := TopRepositories(repos, 0); len(top) != 3 {
+		t.Errorf("Expected all repositories without a limit, got %v", top)
+	}
+}
+
+// TestPrintSmallMultiples tests that PrintSmallMultiples doesn't panic
+func TestPrintSmallMultiples(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintSmallMultiples(io.Discard, []RepoResult{
+		{Path: "/code/foo", Total: 3, Counts: map[int]int{0: 1, 10: 2}},
+		{Path: "/code/bar", Name: "github.com/me/bar"},
+	}, RenderOptions{})
+}
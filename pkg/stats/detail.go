@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/scanner"
+)
+
+// SortCommitsByTime returns a copy of the commits ordered from oldest to newest.
+//
+// Parameters:
+//   - commits: The commits to sort
+//
+// Returns:
+//   - []CommitInfo: The sorted commits
+func SortCommitsByTime(commits []CommitInfo) []CommitInfo {
+	sorted := make([]CommitInfo, len(commits))
+	copy(sorted, commits)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].When.Before(sorted[j].When)
+	})
+
+	return sorted
+}
+
+// PrintCommitDetails lists the commits of a single day (short hash, time and subject)
+// in chronological order. Commits of repositories hosted on GitHub or GitLab are followed by
+// the URL of their page, see CommitURL, which terminals make clickable.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - date: The day the commits were made on
+//   - commits: The commits made on that day
+//   - hashLength: The number of characters of the short hashes (if zero, DefaultShortHashLength)
+func PrintCommitDetails(w io.Writer, date time.Time, commits []CommitInfo, hashLength int) {
+	if len(commits) == 0 {
+		fmt.Fprintf(w, "No commits on %s\n", date.Format("2006-01-02"))
+		return
+	}
+
+	// Read the remote of each repository once
+	remotes := make(map[string]string)
+
+	fmt.Fprintf(w, "Commits on %s:\n", date.Format("2006-01-02"))
+	for _, c := range SortCommitsByTime(commits) {
+		line := fmt.Sprintf("  %s %s %s", c.AbbreviatedHash(hashLength), c.When.Format("15:04"), c.Subject)
+
+		if c.Repository != "" {
+			remote, ok := remotes[c.Repository]
+			if !ok {
+				remote = scanner.RemoteURL(c.Repository)
+				remotes[c.Repository] = remote
+			}
+			if url := CommitURL(remote, c.Hash); url != "" {
+				line += "  " + url
+			}
+		}
+		fmt.Fprintf(w, "%s\n", line)
+	}
+}
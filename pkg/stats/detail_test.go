@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// TestSortCommitsByTime tests the SortCommitsByTime function
+func TestSortCommitsByTime(t *testing.T) {
+	morning := time.Date(2023, 5, 15, 9, 0, 0, 0, time.UTC)
+	evening := time.Date(2023, 5, 15, 18, 0, 0, 0, time.UTC)
+
+	commits := []CommitInfo{
+		{Hash: "b", When: evening},
+		{Hash: "a", When: morning},
+	}
+
+	result := SortCommitsByTime(commits)
+	if result[0].Hash != "a" || result[1].Hash != "b" {
+		t.Errorf("Expected commits sorted by time, got %v", result)
+	}
+
+	// The input must not be modified
+	if commits[0].Hash != "b" {
+		t.Errorf("Expected input to be left untouched, got %v", commits)
+	}
+}
+
+// TestShortHash tests the CommitInfo.ShortHash method
+func TestShortHash(t *testing.T) {
+	c := CommitInfo{Hash: "0123456789abcdef"}
+	if c.ShortHash() != "0123456" {
+		t.Errorf("Expected 0123456, got %s", c.ShortHash())
+	}
+
+	c = CommitInfo{Hash: "0123"}
+	if c.ShortHash() != "0123" {
+		t.Errorf("Expected 0123, got %s", c.ShortHash())
+	}
+
+	// The length is configurable
+	c = CommitInfo{Hash: "0123456789abcdef"}
+	if hash := c.AbbreviatedHash(12); hash != "0123456789ab" {
+		t.Errorf("Expected 0123456789ab, got %s", hash)
+	}
+	if hash := c.AbbreviatedHash(0); hash != "0123456" {
+		t.Errorf("Expected the default length, got %s", hash)
+	}
+}
+
+// TestPrintCommitDetails tests that PrintCommitDetails doesn't panic
+func TestPrintCommitDetails(t *testing.T) {
+	date := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	// This test just ensures the function doesn't panic
+	PrintCommitDetails(io.Discard, date, nil, 0)
+	PrintCommitDetails(io.Discard, date, []CommitInfo{{Hash: "0123456789", Subject: "Fix bug", When: date}}, 10)
+}
@@ -0,0 +1,233 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Output formats of the stats command
+const (
+	FormatTerminal = "terminal"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatJSONL    = "jsonl"
+	FormatSVG      = "svg"
+	FormatPNG      = "png"
+	FormatHTML     = "html"
+)
+
+// Formats lists the supported output formats, for validation and completion.
+var Formats = []string{FormatTerminal, FormatJSON, FormatCSV, FormatJSONL, FormatSVG, FormatPNG, FormatHTML}
+
+// jsonDay is the JSON representation of a DayCount, with the intensity level of its cell
+// (LevelNone to LevelHigh) so front-ends can color it like the terminal graph.
+type jsonDay struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+	Level int    `json:"level"`
+}
+
+// jsonYear is the JSON representation of the commits of a calendar year.
+type jsonYear struct {
+	Year  string `json:"year"`
+	Count int    `json:"count"`
+}
+
+// jsonBursts is the JSON representation of Bursts, with the window in seconds.
+type jsonBursts struct {
+	Clustered int     `json:"clustered"`
+	Total     int     `json:"total"`
+	Percent   int     `json:"percent"`
+	Window    float64 `json:"window_seconds"`
+}
+
+// jsonRepository is the JSON representation of a RepoResult.
+type jsonRepository struct {
+	Path        string `json:"path"`
+	Name        string `json:"name,omitempty"`
+	Total       int    `json:"total"`
+	FirstCommit string `json:"first_commit,omitempty"`
+	LastCommit  string `json:"last_commit,omitempty"`
+	Shallow     bool   `json:"shallow,omitempty"`
+}
+
+// jsonResult is the JSON representation of a Result.
+type jsonResult struct {
+	Total         int              `json:"total"`
+	ActiveDays    int              `json:"active_days"`
+	PerActiveDay  float64          `json:"commits_per_active_day"`
+	CurrentStreak int              `json:"current_streak"`
+	LongestStreak int              `json:"longest_streak"`
+	Years         []jsonYear       `json:"years"`
+	Bursts        *jsonBursts      `json:"bursts,omitempty"`
+	Days          []jsonDay        `json:"days"`
+	Repositories  []jsonRepository `json:"repositories"`
+}
+
+// formatDate formats a date as YYYY-MM-DD, or an empty string if it is zero.
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// dayLevels returns the options computing the intensity level of each day in the outputs,
+// the same as the cells of the daily graph rendered with opts.
+func dayLevels(result *Result, opts RenderOptions) RenderOptions {
+	opts.Weekly = false
+	if opts.Relative && opts.MaxCount == 0 {
+		opts.MaxCount = MaxCount(result.Counts)
+	}
+	return opts
+}
+
+// WriteJSON writes the summary, the daily counts and the per-repository breakdown as indented JSON.
+// Each day has the intensity level of its cell in the graph rendered with opts.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//   - opts: The options the levels of the days are computed with (only the levels settings are used)
+//
+// Returns:
+//   - error: An error if the result couldn't be written
+func WriteJSON(w io.Writer, result *Result, opts RenderOptions) error {
+	levels := dayLevels(result, opts)
+	out := jsonResult{
+		Total:         result.Summary.Total,
+		ActiveDays:    result.Summary.ActiveDays,
+		PerActiveDay:  math.Round(result.Summary.PerActiveDay()*100) / 100,
+		CurrentStreak: result.Summary.CurrentStreak,
+		LongestStreak: result.Summary.LongestStreak,
+		Years:         make([]jsonYear, 0, len(result.Summary.Years)),
+		Days:          make([]jsonDay, 0, len(result.Days)),
+		Repositories:  make([]jsonRepository, 0, len(result.Repositories)),
+	}
+
+	for _, year := range result.Summary.Years {
+		out.Years = append(out.Years, jsonYear{Year: year.Label, Count: year.Count})
+	}
+
+	if bursts := result.Summary.Bursts; bursts != nil {
+		out.Bursts = &jsonBursts{Clustered: bursts.Clustered, Total: bursts.Total, Percent: bursts.Percent(), Window: bursts.Window.Seconds()}
+	}
+
+	for _, day := range result.Days {
+		out.Days = append(out.Days, jsonDay{Date: day.Date.Format("2006-01-02"), Count: day.Count, Level: levels.level(day.Count)})
+	}
+
+	for _, repo := range result.Repositories {
+		out.Repositories = append(out.Repositories, jsonRepository{
+			Path:        repo.Path,
+			Name:        repo.Name,
+			Total:       repo.Total,
+			FirstCommit: formatDate(repo.FirstCommit),
+			LastCommit:  formatDate(repo.LastCommit),
+			Shallow:     repo.Shallow,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(out); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	return nil
+}
+
+// WriteJSONL writes one JSON object per line: one per day, or one per repository in breakdown mode.
+// Each record is written as soon as it is encoded, so consumers like jq can process them incrementally.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//   - perRepository: Whether to write the per-repository breakdown instead of the daily counts
+//   - opts: The options the levels of the days are computed with, see WriteJSON
+//
+// Returns:
+//   - error: An error if a record couldn't be written
+func WriteJSONL(w io.Writer, result *Result, perRepository bool, opts RenderOptions) error {
+	encoder := json.NewEncoder(w)
+
+	if perRepository {
+		for _, repo := range result.Repositories {
+			record := jsonRepository{
+				Path:        repo.Path,
+				Name:        repo.Name,
+				Total:       repo.Total,
+				FirstCommit: formatDate(repo.FirstCommit),
+				LastCommit:  formatDate(repo.LastCommit),
+				Shallow:     repo.Shallow,
+			}
+			if err := encoder.Encode(record); err != nil {
+				return fmt.Errorf("failed to write JSON line: %w", err)
+			}
+		}
+		return nil
+	}
+
+	levels := dayLevels(result, opts)
+	for _, day := range result.Days {
+		if err := encoder.Encode(jsonDay{Date: day.Date.Format("2006-01-02"), Count: day.Count, Level: levels.level(day.Count)}); err != nil {
+			return fmt.Errorf("failed to write JSON line: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// WriteCSV writes the daily counts as CSV, with a date,count header.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//
+// Returns:
+//   - error: An error if the result couldn't be written
+func WriteCSV(w io.Writer, result *Result) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"date", "count"}); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, day := range result.Days {
+		if err := writer.Write([]string{day.Date.Format("2006-01-02"), strconv.Itoa(day.Count)}); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
+
+// WritePorcelain writes the summary as a single line of key=value pairs, for scripts:
+// total=123 streak=5 longest=14 active_days=60. The keys and their order are stable,
+// and the line doesn't change when the human-readable summary does.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//
+// Returns:
+//   - error: An error if the line couldn't be written
+func WritePorcelain(w io.Writer, result *Result) error {
+	summary := result.Summary
+	_, err := fmt.Fprintf(w, "total=%d streak=%d longest=%d active_days=%d\n",
+		summary.Total, summary.CurrentStreak, summary.LongestStreak, summary.ActiveDays)
+	if err != nil {
+		return fmt.Errorf("failed to write the summary: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package stats
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testResult returns a small result to export
+func testResult() *Result {
+	first := time.Date(2023, 5, 14, 10, 0, 0, 0, time.UTC)
+	return &Result{
+		Days: []DayCount{
+			{Date: time.Date(2023, 5, 14, 0, 0, 0, 0, time.UTC), Count: 2},
+			{Date: time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC), Count: 0},
+		},
+		Summary:      Summary{Total: 2, ActiveDays: 1, CurrentStreak: 1, LongestStreak: 1, Years: []PeriodCount{{Label: "2023", Count: 2}}},
+		Repositories: []RepoResult{{Path: "/repo", Total: 2, FirstCommit: first, LastCommit: first}, {Path: "/empty"}},
+	}
+}
+
+// TestWriteJSON tests the WriteJSON function
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, testResult(), RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var out jsonResult
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if out.Total != 2 || out.PerActiveDay != 2 || len(out.Days) != 2 || out.Days[0].Date != "2023-05-14" || out.Days[0].Count != 2 {
+		t.Errorf("Unexpected JSON: %s", buf.String())
+	}
+	if out.Days[0].Level != LevelLow || out.Days[1].Level != LevelNone {
+		t.Errorf("Expected the levels of the graph, got %+v", out.Days)
+	}
+	if len(out.Years) != 1 || out.Years[0].Year != "2023" || out.Years[0].Count != 2 {
+		t.Errorf("Unexpected years: %+v", out.Years)
+	}
+	if out.Repositories[0].LastCommit != "2023-05-14" || out.Repositories[1].LastCommit != "" {
+		t.Errorf("Unexpected repositories: %+v", out.Repositories)
+	}
+	if out.Bursts != nil {
+		t.Errorf("Expected no bursts unless computed, got %+v", out.Bursts)
+	}
+
+	// The bursts are written with the summary when they were computed
+	withBursts := testResult()
+	withBursts.Summary.Bursts = &Bursts{Clustered: 1, Total: 4, Window: DefaultBurstWindow}
+	buf.Reset()
+	if err := WriteJSON(&buf, withBursts, RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out = jsonResult{}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if out.Bursts == nil || *out.Bursts != (jsonBursts{Clustered: 1, Total: 4, Percent: 25, Window: 300}) {
+		t.Errorf("Unexpected bursts: %+v", out.Bursts)
+	}
+
+	// The relative levels are computed from the highest daily count, like in the graph
+	result := testResult()
+	result.Counts = map[int]int{1: 2}
+	buf.Reset()
+	if err := WriteJSON(&buf, result, RenderOptions{Relative: true, Weekly: true}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatalf("Invalid JSON: %v", err)
+	}
+	if out.Days[0].Level != LevelHigh {
+		t.Errorf("Expected the busiest day at LevelHigh, got %d", out.Days[0].Level)
+	}
+}
+
+// TestWriteCSV tests the WriteCSV function
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testResult()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{"date,count", "2023-05-14,2", "2023-05-15,0", ""}, "\n")
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+// TestWriteJSONL tests the WriteJSONL function
+func TestWriteJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, testResult(), false, RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := strings.Join([]string{`{"date":"2023-05-14","count":2,"level":1}`, `{"date":"2023-05-15","count":0,"level":0}`, ""}, "\n")
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+
+	// One line per repository in breakdown mode
+	buf.Reset()
+	if err := WriteJSONL(&buf, testResult(), true, RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	var repo jsonRepository
+	if len(lines) != 2 || json.Unmarshal([]byte(lines[1]), &repo) != nil || repo.Path != "/empty" {
+		t.Errorf("Unexpected JSON lines: %q", buf.String())
+	}
+}
+
+// TestWritePorcelain tests the WritePorcelain function
+func TestWritePorcelain(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePorcelain(&buf, testResult()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "total=2 streak=1 longest=1 active_days=1\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
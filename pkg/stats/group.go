@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Periods (or hosts) the commits can be grouped by
+const (
+	GroupByQuarter = "quarter"
+	// GroupByHost sums the commits of the repositories hosted on the same server, see RepoHost
+	GroupByHost = "host"
+)
+
+// GroupByModes lists the supported groupings, for validation and completion.
+var GroupByModes = []string{GroupByQuarter, GroupByHost}
+
+// PeriodCount holds the number of commits made during a period, such as a quarter.
+type PeriodCount struct {
+	// Label names the period, e.g. "2024 Q1"
+	Label string
+	// Count is the number of commits made during the period
+	Count int
+}
+
+// CountByQuarter buckets the daily counts of the graph window into calendar quarters.
+// Every quarter the window overlaps is listed, even without commits.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+//
+// Returns:
+//   - []PeriodCount: The commits of each quarter, from oldest to newest
+func CountByQuarter(commits map[int]int, window GraphRange) []PeriodCount {
+	return countByQuarter(commits, GetBeginningOfDay(time.Now()), window.orDefault())
+}
+
+// countByQuarter buckets the daily counts relative to the given day, see CountByQuarter.
+func countByQuarter(commits map[int]int, today time.Time, window GraphRange) []PeriodCount {
+	return countByPeriod(commits, today, window, func(date time.Time) string {
+		return fmt.Sprintf("%d Q%d", date.Year(), (int(date.Month())-1)/3+1)
+	})
+}
+
+// countByYear buckets the daily counts of the graph window into calendar years.
+func countByYear(commits map[int]int, today time.Time, window GraphRange) []PeriodCount {
+	return countByPeriod(commits, today, window, func(date time.Time) string {
+		return strconv.Itoa(date.Year())
+	})
+}
+
+// countByPeriod buckets the daily counts into the consecutive periods named by label,
+// reconstructing the date of each day relative to the given day.
+func countByPeriod(commits map[int]int, today time.Time, window GraphRange, label func(time.Time) string) []PeriodCount {
+	var periods []PeriodCount
+
+	// Walk from the oldest day to today so the periods come out in order
+	for daysAgo := window.Days; daysAgo >= 0; daysAgo-- {
+		label := label(today.AddDate(0, 0, -daysAgo))
+
+		if len(periods) == 0 || periods[len(periods)-1].Label != label {
+			periods = append(periods, PeriodCount{Label: label})
+		}
+		periods[len(periods)-1].Count += commits[daysAgo]
+	}
+
+	return periods
+}
+
+// PrintPeriods prints the number of commits of each period, one per line.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - periods: The periods to print
+func PrintPeriods(w io.Writer, periods []PeriodCount) {
+	for _, period := range periods {
+		fmt.Fprintf(w, "%-8s %6d\n", period.Label, period.Count)
+	}
+}
+
+// HostCount holds the commits made in the repositories of a single host, such as github.com.
+type HostCount struct {
+	// Host is the host of the repositories, or LocalHost
+	Host string
+	// Repositories is the number of repositories on the host
+	Repositories int
+	// Count is the number of commits made in the repositories of the host
+	Count int
+}
+
+// CountByHost sums the commits of the per-repository breakdown by the host of their origin remote.
+//
+// Parameters:
+//   - repos: The per-repository breakdown, with the paths of the repositories
+//
+// Returns:
+//   - []HostCount: The commits of each host, most commits first
+func CountByHost(repos []RepoResult) []HostCount {
+	return countByHost(repos, RepoHost)
+}
+
+// countByHost sums the commits of the repositories by the host returned by hostOf, see CountByHost.
+func countByHost(repos []RepoResult, hostOf func(path string) string) []HostCount {
+	var hosts []HostCount
+	index := make(map[string]int)
+
+	for _, repo := range repos {
+		host := hostOf(repo.Path)
+		i, ok := index[host]
+		if !ok {
+			i = len(hosts)
+			index[host] = i
+			hosts = append(hosts, HostCount{Host: host})
+		}
+		hosts[i].Repositories++
+		hosts[i].Count += repo.Total
+	}
+
+	// Most commits first, by host name on ties so the order is stable
+	sort.Slice(hosts, func(i, j int) bool {
+		if hosts[i].Count != hosts[j].Count {
+			return hosts[i].Count > hosts[j].Count
+		}
+		return hosts[i].Host < hosts[j].Host
+	})
+
+	return hosts
+}
+
+// PrintHosts prints the number of commits and repositories of each host, one per line.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - hosts: The hosts to print
+func PrintHosts(w io.Writer, hosts []HostCount) {
+	width := 0
+	for _, host := range hosts {
+		width = max(width, len(host.Host))
+	}
+
+	for _, host := range hosts {
+		noun := "repositories"
+		if host.Repositories == 1 {
+			noun = "repository"
+		}
+		fmt.Fprintf(w, "%-*s %6d  (%d %s)\n", width, host.Host, host.Count, host.Repositories, noun)
+	}
+}
@@ -0,0 +1,74 @@
+package stats
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestCountByQuarter tests the countByQuarter function
+func TestCountByQuarter(t *testing.T) {
+	// Use a fixed date for testing, the window starts on 2022-11-13
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	// Today, the last day of Q1 (2023-03-31) and the first day of the window
+	commits := map[int]int{0: 1, 45: 2, DaysInLastSixMonths: 3}
+
+	result := countByQuarter(commits, today, DefaultGraphRange)
+	expected := []PeriodCount{
+		{Label: "2022 Q4", Count: 3},
+		{Label: "2023 Q1", Count: 2},
+		{Label: "2023 Q2", Count: 1},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestCountByYear tests the countByYear function
+func TestCountByYear(t *testing.T) {
+	today := time.Date(2023, 1, 10, 0, 0, 0, 0, time.UTC)
+	commits := map[int]int{0: 1, 9: 2, 10: 4, DaysInLastSixMonths: 8}
+
+	result := countByYear(commits, today, DefaultGraphRange)
+	expected := []PeriodCount{{Label: "2022", Count: 12}, {Label: "2023", Count: 3}}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestPrintPeriods tests that PrintPeriods doesn't panic
+func TestPrintPeriods(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintPeriods(io.Discard, []PeriodCount{{Label: "2023 Q1", Count: 2}})
+	PrintPeriods(io.Discard, nil)
+}
+
+// TestCountByHost tests the countByHost function
+func TestCountByHost(t *testing.T) {
+	hosts := map[string]string{"/code/app": "github.com", "/code/lib": "gitlab.com", "/code/tools": "github.com", "/code/notes": LocalHost}
+	repos := []RepoResult{
+		{Path: "/code/app", Total: 3},
+		{Path: "/code/lib", Total: 5},
+		{Path: "/code/tools", Total: 4},
+		{Path: "/code/notes", Total: 0},
+	}
+
+	result := countByHost(repos, func(path string) string { return hosts[path] })
+	expected := []HostCount{
+		{Host: "github.com", Repositories: 2, Count: 7},
+		{Host: "gitlab.com", Repositories: 1, Count: 5},
+		{Host: LocalHost, Repositories: 1, Count: 0},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+}
+
+// TestPrintHosts tests that PrintHosts doesn't panic
+func TestPrintHosts(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintHosts(io.Discard, []HostCount{{Host: "github.com", Repositories: 2, Count: 7}, {Host: LocalHost, Repositories: 1}})
+	PrintHosts(io.Discard, nil)
+}
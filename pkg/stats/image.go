@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+)
+
+// Dimensions of the cells of the SVG and PNG graphs, in pixels
+const (
+	imageCellSize = 10
+	imageCellGap  = 2
+	imagePitch    = imageCellSize + imageCellGap
+)
+
+// imageCell is a cell of the SVG and PNG graphs, positioned in the grid of weeks and weekdays.
+type imageCell struct {
+	day DayCount
+	x   int
+	y   int
+}
+
+// imageLayout positions the days of the result in a grid of one column per week, Sunday on top,
+// like the terminal graph.
+//
+// Returns:
+//   - []imageCell: The cells, oldest first
+//   - int: The width of the image
+//   - int: The height of the image
+func imageLayout(result *Result) ([]imageCell, int, int) {
+	if len(result.Days) == 0 {
+		return nil, imageCellGap, imageCellGap
+	}
+
+	// The first column starts on the Sunday before the first day of the window
+	offset := int(result.Days[0].Date.Weekday())
+	cells := make([]imageCell, 0, len(result.Days))
+	for i, day := range result.Days {
+		slot := offset + i
+		cells = append(cells, imageCell{
+			day: day,
+			x:   imageCellGap + slot/DaysInWeek*imagePitch,
+			y:   imageCellGap + slot%DaysInWeek*imagePitch,
+		})
+	}
+
+	weeks := (offset + len(result.Days) + DaysInWeek - 1) / DaysInWeek
+	return cells, imageCellGap + weeks*imagePitch, imageCellGap + DaysInWeek*imagePitch
+}
+
+// paletteColor converts a color of the 256-color palette to RGB, using the xterm values.
+func paletteColor(index int) color.RGBA {
+	// The 16 system colors
+	system := [16][3]uint8{
+		{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0}, {0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+		{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0}, {0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+
+	switch {
+	case index < 16:
+		rgb := system[max(index, 0)]
+		return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 255}
+	case index < 232:
+		// The 6x6x6 color cube
+		steps := [6]uint8{0, 95, 135, 175, 215, 255}
+		index -= 16
+		return color.RGBA{R: steps[index/36], G: steps[index/6%6], B: steps[index%6], A: 255}
+	}
+
+	// The grayscale ramp
+	gray := uint8(8 + 10*(min(index, 255)-232))
+	return color.RGBA{R: gray, G: gray, B: gray, A: 255}
+}
+
+// imageColor returns the color of a cell, from the color scheme of the options.
+func (o RenderOptions) imageColor(count int) color.RGBA {
+	return paletteColor(o.colorScheme().Colors[o.level(count)])
+}
+
+// WriteSVG writes the contribution graph as an SVG image, one square per day,
+// with a tooltip giving its date and number of commits.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//   - opts: The options controlling how the graph is rendered (only the color scheme and levels are used)
+//
+// Returns:
+//   - error: An error if the image couldn't be written
+func WriteSVG(w io.Writer, result *Result, opts RenderOptions) error {
+	cells, width, height := imageLayout(result)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", width, height, width, height)
+	for _, cell := range cells {
+		c := opts.imageColor(cell.day.Count)
+		fmt.Fprintf(&b, "  <rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" rx=\"2\" fill=\"#%02x%02x%02x\"><title>%d commits on %s</title></rect>\n",
+			cell.x, cell.y, imageCellSize, imageCellSize, c.R, c.G, c.B, cell.day.Count, cell.day.Date.Format("2006-01-02"))
+	}
+	b.WriteString("</svg>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write SVG: %w", err)
+	}
+
+	return nil
+}
+
+// WritePNG writes the contribution graph as a PNG image, with the same layout as WriteSVG
+// on a transparent background.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//   - opts: The options controlling how the graph is rendered (only the color scheme and levels are used)
+//
+// Returns:
+//   - error: An error if the image couldn't be written
+func WritePNG(w io.Writer, result *Result, opts RenderOptions) error {
+	cells, width, height := imageLayout(result)
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for _, cell := range cells {
+		rect := image.Rect(cell.x, cell.y, cell.x+imageCellSize, cell.y+imageCellSize)
+		draw.Draw(img, rect, image.NewUniform(opts.imageColor(cell.day.Count)), image.Point{}, draw.Src)
+	}
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to write PNG: %w", err)
+	}
+
+	return nil
+}
+
+// WriteHTML writes a standalone HTML page showing the contribution graph as an inline SVG image,
+// see WriteSVG, followed by a line of the summary.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - result: The result of the analysis
+//   - opts: The options controlling how the graph is rendered (only the color scheme and levels are used)
+//
+// Returns:
+//   - error: An error if the page couldn't be written
+func WriteHTML(w io.Writer, result *Result, opts RenderOptions) error {
+	var svg strings.Builder
+	if err := WriteSVG(&svg, result, opts); err != nil {
+		return err
+	}
+
+	summary := result.Summary
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n<title>Contributions</title>\n</head>\n<body>\n")
+	b.WriteString(svg.String())
+	fmt.Fprintf(&b, "<p>%d commits on %d active days in %s, current streak %d days, longest streak %d days</p>\n",
+		summary.Total, summary.ActiveDays, result.Range, summary.CurrentStreak, summary.LongestStreak)
+	b.WriteString("</body>\n</html>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write HTML: %w", err)
+	}
+
+	return nil
+}
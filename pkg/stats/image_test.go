@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// TestPaletteColor tests the conversion of the 256-color palette to RGB
+func TestPaletteColor(t *testing.T) {
+	tests := []struct {
+		index    int
+		expected color.RGBA
+	}{
+		{9, color.RGBA{R: 255, A: 255}},
+		{22, color.RGBA{G: 95, A: 255}},
+		{120, color.RGBA{R: 135, G: 255, B: 135, A: 255}},
+		{248, color.RGBA{R: 168, G: 168, B: 168, A: 255}},
+	}
+
+	for _, test := range tests {
+		if got := paletteColor(test.index); got != test.expected {
+			t.Errorf("paletteColor(%d) = %v, expected %v", test.index, got, test.expected)
+		}
+	}
+}
+
+// TestWriteSVG tests the WriteSVG function
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, testResult(), RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	svg := buf.String()
+	if !strings.HasPrefix(svg, "<svg ") || strings.Count(svg, "<rect ") != 2 {
+		t.Fatalf("Expected an SVG with 2 cells, got %q", svg)
+	}
+
+	// 2023-05-14 is a Sunday, so the days are stacked in the first column
+	if !strings.Contains(svg, `<rect x="2" y="2" width="10" height="10" rx="2" fill="#87ff87"><title>2 commits on 2023-05-14</title>`) {
+		t.Errorf("Missing the cell of 2023-05-14: %q", svg)
+	}
+	if !strings.Contains(svg, `<rect x="2" y="14" width="10" height="10" rx="2" fill="#a8a8a8"><title>0 commits on 2023-05-15</title>`) {
+		t.Errorf("Missing the cell of 2023-05-15: %q", svg)
+	}
+}
+
+// TestWritePNG tests the WritePNG function
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, testResult(), RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Invalid PNG: %v", err)
+	}
+
+	// One week column and a row per weekday
+	if bounds := img.Bounds(); bounds.Dx() != 14 || bounds.Dy() != 86 {
+		t.Errorf("Expected a 14x86 image, got %dx%d", bounds.Dx(), bounds.Dy())
+	}
+	if r, g, b, _ := img.At(5, 5).RGBA(); r>>8 != 135 || g>>8 != 255 || b>>8 != 135 {
+		t.Errorf("Expected the first cell in green, got %d,%d,%d", r>>8, g>>8, b>>8)
+	}
+	if _, _, _, a := img.At(0, 0).RGBA(); a != 0 {
+		t.Errorf("Expected a transparent background, got alpha %d", a)
+	}
+}
+
+// TestWriteHTML tests the WriteHTML function
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, testResult(), RenderOptions{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	page := buf.String()
+	if !strings.HasPrefix(page, "<!DOCTYPE html>\n") || strings.Count(page, "<rect ") != 2 || !strings.HasSuffix(page, "</html>\n") {
+		t.Fatalf("Expected a page with the SVG graph, got %q", page)
+	}
+	if !strings.Contains(page, "<p>2 commits on 1 active days in the last 6 months") {
+		t.Errorf("Expected the summary, got %q", page)
+	}
+}
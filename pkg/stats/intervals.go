@@ -0,0 +1,172 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Intervals holds the time elapsed between consecutive commits, a measure of the flow of work.
+type Intervals struct {
+	// Count is the number of intervals, one less than the number of commits (zero with a single commit)
+	Count int
+	// Average is the mean time between consecutive commits (zero without intervals)
+	Average time.Duration
+	// Median is the median time between consecutive commits (zero without intervals)
+	Median time.Duration
+}
+
+// CommitIntervals computes the time between consecutive commits from their author dates.
+// A commit found in several repositories (e.g. the same project cloned twice) is only counted once,
+// while distinct commits with identical dates count as an interval of zero.
+//
+// Parameters:
+//   - commits: A map of days to the commits of that day
+//
+// Returns:
+//   - Intervals: The intervals between the commits, without any if there are less than two commits
+func CommitIntervals(commits map[int][]CommitInfo) Intervals {
+	dates := commitDates(commits)
+
+	// A single commit has no interval
+	if len(dates) < 2 {
+		return Intervals{}
+	}
+
+	gaps := make([]time.Duration, len(dates)-1)
+	for i := 1; i < len(dates); i++ {
+		gaps[i-1] = dates[i].Sub(dates[i-1])
+	}
+
+	// The average is the time from the first to the last commit, spread over the intervals
+	intervals := Intervals{Count: len(gaps), Average: dates[len(dates)-1].Sub(dates[0]) / time.Duration(len(gaps))}
+
+	sort.Slice(gaps, func(i, j int) bool {
+		return gaps[i] < gaps[j]
+	})
+	middle := len(gaps) / 2
+	if len(gaps)%2 == 1 {
+		intervals.Median = gaps[middle]
+	} else {
+		intervals.Median = (gaps[middle-1] + gaps[middle]) / 2
+	}
+
+	return intervals
+}
+
+// commitDates returns the author dates of the commits, oldest first.
+// A commit found in several repositories is only returned once.
+func commitDates(commits map[int][]CommitInfo) []time.Time {
+	var dates []time.Time
+	seen := make(map[string]bool)
+	for _, dayCommits := range commits {
+		for _, c := range dayCommits {
+			if c.Hash != "" {
+				if seen[c.Hash] {
+					continue
+				}
+				seen[c.Hash] = true
+			}
+			dates = append(dates, c.When)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+	return dates
+}
+
+// DefaultBurstWindow is the time within which consecutive commits are counted as a burst,
+// suggesting amend or fixup churn.
+const DefaultBurstWindow = 5 * time.Minute
+
+// Bursts holds the commits made within a short window of another one.
+type Bursts struct {
+	// Clustered is the number of commits made within Window of the commit before or after them
+	Clustered int
+	// Total is the number of commits
+	Total int
+	// Window is the time within which consecutive commits are clustered
+	Window time.Duration
+}
+
+// Percent returns the share of clustered commits, rounded down (zero without commits).
+func (b Bursts) Percent() int {
+	if b.Total == 0 {
+		return 0
+	}
+	return b.Clustered * 100 / b.Total
+}
+
+// CommitBursts counts the commits made within the window of the commit before or after them,
+// from their author dates. A commit found in several repositories is only counted once.
+//
+// Parameters:
+//   - commits: A map of days to the commits of that day
+//   - window: The time within which consecutive commits are clustered (e.g. DefaultBurstWindow)
+//
+// Returns:
+//   - Bursts: The clustered commits among all the commits
+func CommitBursts(commits map[int][]CommitInfo, window time.Duration) Bursts {
+	dates := commitDates(commits)
+	bursts := Bursts{Total: len(dates), Window: window}
+
+	for i := range dates {
+		closeToPrevious := i > 0 && dates[i].Sub(dates[i-1]) < window
+		closeToNext := i < len(dates)-1 && dates[i+1].Sub(dates[i]) < window
+		if closeToPrevious || closeToNext {
+			bursts.Clustered++
+		}
+	}
+
+	return bursts
+}
+
+// formatInterval formats a duration with its two most significant units, e.g. "2d 4h" or "35m".
+func formatInterval(d time.Duration) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
+}
+
+// PrintIntervals prints the average and median time between consecutive commits.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - intervals: The intervals to print
+func PrintIntervals(w io.Writer, intervals Intervals) {
+	fmt.Fprintf(w, "\n")
+	if intervals.Count == 0 {
+		fmt.Fprintf(w, "Time between commits: n/a (needs at least 2 commits)\n")
+		return
+	}
+	fmt.Fprintf(w, "Time between commits: %s on average, %s median\n", formatInterval(intervals.Average), formatInterval(intervals.Median))
+}
+
+// printBursts prints the share of commits made within a short window of another one in the summary,
+// if the bursts were computed.
+func printBursts(w io.Writer, summary Summary) {
+	bursts := summary.Bursts
+	switch {
+	case bursts == nil:
+		// Not asked for
+	case bursts.Total == 0:
+		fmt.Fprintf(w, "Commits in bursts: n/a (no commits)\n")
+	default:
+		fmt.Fprintf(w, "Commits in bursts: %d of %d (%d%%) within %s of another commit\n",
+			bursts.Clustered, bursts.Total, bursts.Percent(), formatInterval(bursts.Window))
+	}
+}
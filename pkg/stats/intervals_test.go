@@ -0,0 +1,97 @@
+package stats
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// TestCommitIntervals tests the CommitIntervals function
+func TestCommitIntervals(t *testing.T) {
+	start := time.Date(2023, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1: No interval with a single commit
+	commits := map[int][]CommitInfo{0: {{Hash: "a", When: start}}}
+	if result := CommitIntervals(commits); result != (Intervals{}) {
+		t.Errorf("Expected no intervals, got %+v", result)
+	}
+
+	// Test case 2: Intervals of 1h, 3h and 8h, whatever the order of the commits
+	commits = map[int][]CommitInfo{
+		0: {{Hash: "d", When: start.Add(12 * time.Hour)}, {Hash: "b", When: start.Add(time.Hour)}},
+		1: {{Hash: "a", When: start}, {Hash: "c", When: start.Add(4 * time.Hour)}},
+	}
+	expected := Intervals{Count: 3, Average: 4 * time.Hour, Median: 3 * time.Hour}
+	if result := CommitIntervals(commits); result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	// Test case 3: A commit with the same date adds an interval of zero, the median is between the middle ones
+	commits[1] = append(commits[1], CommitInfo{Hash: "e", When: start})
+	expected = Intervals{Count: 4, Average: 3 * time.Hour, Median: 2 * time.Hour}
+	if result := CommitIntervals(commits); result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	// Test case 4: The same commit found twice is counted once
+	commits[0] = append(commits[0], CommitInfo{Hash: "e", When: start})
+	if result := CommitIntervals(commits); result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+}
+
+// TestCommitBursts tests the CommitBursts function
+func TestCommitBursts(t *testing.T) {
+	start := time.Date(2023, 5, 15, 9, 0, 0, 0, time.UTC)
+
+	// Test case 1: No commits
+	if result := CommitBursts(nil, DefaultBurstWindow); result.Total != 0 || result.Percent() != 0 {
+		t.Errorf("Expected no commits, got %+v", result)
+	}
+
+	// Test case 2: A burst of 3 commits (amended within minutes), and 2 isolated commits
+	commits := map[int][]CommitInfo{
+		0: {{Hash: "e", When: start.Add(5 * time.Hour)}, {Hash: "b", When: start.Add(2 * time.Minute)}},
+		1: {{Hash: "a", When: start}, {Hash: "c", When: start.Add(6 * time.Minute)}, {Hash: "d", When: start.Add(time.Hour)}},
+	}
+	expected := Bursts{Clustered: 3, Total: 5, Window: DefaultBurstWindow}
+	result := CommitBursts(commits, DefaultBurstWindow)
+	if result != expected {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+	if result.Percent() != 60 {
+		t.Errorf("Expected 60%%, got %d%%", result.Percent())
+	}
+
+	// Test case 3: A shorter window splits the burst
+	if result := CommitBursts(commits, time.Minute); result.Clustered != 0 {
+		t.Errorf("Expected no commits in bursts within a minute, got %d", result.Clustered)
+	}
+}
+
+// TestFormatInterval tests the formatInterval function
+func TestFormatInterval(t *testing.T) {
+	testCases := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{0, "0s"},
+		{42 * time.Second, "42s"},
+		{35*time.Minute + 10*time.Second, "35m"},
+		{3*time.Hour + 5*time.Minute, "3h 5m"},
+		{52*time.Hour + 30*time.Minute, "2d 4h"},
+	}
+
+	for _, tc := range testCases {
+		if result := formatInterval(tc.duration); result != tc.expected {
+			t.Errorf("formatInterval(%v): expected %q, got %q", tc.duration, tc.expected, result)
+		}
+	}
+}
+
+// TestPrintIntervals tests that PrintIntervals doesn't panic
+func TestPrintIntervals(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintIntervals(io.Discard, Intervals{Count: 3, Average: 4 * time.Hour, Median: 3 * time.Hour})
+	PrintIntervals(io.Discard, Intervals{})
+}
@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// MonoRamp holds the characters of the monochrome graph, from no commits to the densest cells,
+// so the intensity shows without any color (e.g. on e-ink or monochrome terminals).
+var MonoRamp = []string{" ", ".", ":", "-", "=", "+", "*", "#", "%", "@"}
+
+// monoStep returns the index in MonoRamp of a cell: its number of commits, capped at the densest
+// character. The weekly totals are averaged per day (rounded up) so they don't all end up
+// at the densest character.
+func (o RenderOptions) monoStep(val int) int {
+	if val <= 0 {
+		return 0
+	}
+
+	if o.Weekly {
+		val = (val + DaysInWeek - 1) / DaysInWeek
+	}
+	return min(val, len(MonoRamp)-1)
+}
+
+// monoGlyph returns the character drawn in a cell of the monochrome graph, see MonoRamp.
+func (o RenderOptions) monoGlyph(val int) string {
+	if val <= 0 && o.EmptyCell != "" {
+		return o.EmptyCell
+	}
+	return MonoRamp[o.monoStep(val)]
+}
+
+// printMonoLegend prints a legend mapping the characters of the monochrome graph to their number of commits.
+func printMonoLegend(w io.Writer, opts RenderOptions) {
+	var entries []string
+	for step := 1; step < len(MonoRamp); step++ {
+		label := fmt.Sprintf("%d", step)
+		if step == len(MonoRamp)-1 {
+			label += "+"
+		}
+		entries = append(entries, MonoRamp[step]+" "+label)
+	}
+
+	unit := "commits"
+	if opts.Weekly {
+		unit = "commits per day on average"
+	}
+	fmt.Fprintf(w, "\n     %s  %s\n", strings.Join(entries, "  "), unit)
+}
@@ -0,0 +1,40 @@
+package stats
+
+import (
+	"io"
+	"testing"
+)
+
+// TestMonoGlyph tests the characters of the monochrome graph
+func TestMonoGlyph(t *testing.T) {
+	testCases := []struct {
+		opts     RenderOptions
+		val      int
+		expected string
+	}{
+		{RenderOptions{}, 0, " "},
+		{RenderOptions{}, 1, "."},
+		{RenderOptions{}, 4, "="},
+		{RenderOptions{}, 9, "@"},
+		{RenderOptions{}, 42, "@"},
+		{RenderOptions{EmptyCell: "·"}, 0, "·"},
+		{RenderOptions{Weekly: true}, 1, "."},
+		{RenderOptions{Weekly: true}, 14, ":"},
+		{RenderOptions{Weekly: true}, 15, "-"},
+	}
+
+	for _, tc := range testCases {
+		if result := tc.opts.monoGlyph(tc.val); result != tc.expected {
+			t.Errorf("monoGlyph(%d) with %+v = %q, expected %q", tc.val, tc.opts, result, tc.expected)
+		}
+	}
+}
+
+// TestPrintCellsMono tests that the monochrome graph doesn't panic
+func TestPrintCellsMono(t *testing.T) {
+	// This test just ensures the functions don't panic
+	cols := map[int]Column{0: {0, 1, 5, 12, 0, 0, 0}}
+	PrintCells(io.Discard, cols, RenderOptions{Mono: true, NoColor: true})
+	PrintCells(io.Discard, cols, RenderOptions{Mono: true, NoColor: true, Compact: true})
+	PrintLegend(io.Discard, RenderOptions{Mono: true, NoColor: true})
+}
@@ -0,0 +1,165 @@
+package stats
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// reflogCommitActions lists the reflog actions recording a commit being created: new commits,
+// amends, merges, cherry-picks, reverts and the commits rewritten by a rebase.
+// Checkouts, resets, pulls and the start and end of rebases only move HEAD.
+var reflogCommitActions = map[string]bool{
+	"commit": true, "commit (initial)": true, "commit (amend)": true, "commit (merge)": true, "cherry-pick": true, "revert": true,
+	"rebase (pick)": true, "rebase (reword)": true, "rebase (edit)": true, "rebase (squash)": true, "rebase (fixup)": true, "rebase (continue)": true,
+	"rebase -i (pick)": true, "rebase -i (reword)": true, "rebase -i (edit)": true, "rebase -i (squash)": true, "rebase -i (fixup)": true, "rebase -i (continue)": true,
+}
+
+// GetReflogFromRepo retrieves the commits created locally in a Git repository from its HEAD reflog,
+// including the amended and rebased commits that no longer appear in the history.
+//
+// This is experimental and reflects local operations only: the reflog is never pushed nor cloned,
+// it is pruned by git gc (after 90 days by default), and the same work done on another machine
+// or in a fresh clone isn't in it. Events are filtered by the identity that performed them
+// (the committer), dated when they happened, and each created commit is counted once.
+// A repository without reflog has no events.
+//
+// Parameters:
+//   - filter: The filter deciding which events to count
+//   - path: The path to the Git repository
+//   - commits: A map of days to matched commits to update
+//
+// Returns:
+//   - map[int][]CommitInfo: The updated commits map, the reflog message as subject
+//   - error: An error if the repository or its reflog couldn't be read
+func GetReflogFromRepo(filter Filter, path string, commits map[int][]CommitInfo) (map[int][]CommitInfo, error) {
+	return getReflogFromRepo(filter, path, commits, DefaultGraphRange)
+}
+
+// getReflogFromRepo retrieves the commits created locally like GetReflogFromRepo, within the given window.
+func getReflogFromRepo(filter Filter, path string, commits map[int][]CommitInfo, window GraphRange) (map[int][]CommitInfo, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository at %s: %w", path, err)
+	}
+
+	storage, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil, fmt.Errorf("failed to read the reflog of %s: not stored on disk", path)
+	}
+
+	// The HEAD reflog of a linked worktree is kept in its own gitdir, which is the one opened
+	file, err := storage.Filesystem().Open("logs/HEAD")
+	if errors.Is(err, os.ErrNotExist) {
+		return commits, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the reflog of %s: %w", path, err)
+	}
+	defer file.Close()
+
+	today := GetBeginningOfDay(time.Now())
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry, ok := parseReflogEntry(scanner.Text())
+		if !ok || !reflogCommitActions[entry.action] || seen[entry.hash.String()] {
+			continue
+		}
+		seen[entry.hash.String()] = true
+
+		if reason := filter.RejectReason(entry.signature.Email, ""); reason != "" {
+			filter.trace(path, entry.hash, entry.signature, reason)
+			continue
+		}
+
+		daysAgo := countDaysSince(entry.signature.When, today, window)
+		if daysAgo == OutOfRange {
+			filter.trace(path, entry.hash, entry.signature, SkipOutOfRange)
+			continue
+		}
+		if filter.ExcludeWeekends && isWeekend(entry.signature.When) {
+			filter.trace(path, entry.hash, entry.signature, SkipWeekend)
+			continue
+		}
+
+		commits[daysAgo] = append(commits[daysAgo], CommitInfo{
+			Hash:    entry.hash.String(),
+			Subject: entry.message,
+			Email:   filter.Identity(entry.signature.Email),
+			When:    entry.signature.When,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the reflog of %s: %w", path, err)
+	}
+
+	return commits, nil
+}
+
+// reflogEntry is a line of a reflog: "<old> <new> <name> <<email>> <timestamp> <zone>\t<action>: <message>".
+type reflogEntry struct {
+	hash      plumbing.Hash
+	signature object.Signature
+	action    string
+	message   string
+}
+
+// parseReflogEntry parses a line of a reflog, reporting false for malformed lines.
+func parseReflogEntry(line string) (reflogEntry, bool) {
+	header, message, found := strings.Cut(line, "\t")
+	if !found {
+		return reflogEntry{}, false
+	}
+
+	// The old and new hashes, then the identity
+	fields := strings.SplitN(header, " ", 3)
+	if len(fields) != 3 || !plumbing.IsHash(fields[1]) {
+		return reflogEntry{}, false
+	}
+	start, end := strings.Index(fields[2], "<"), strings.LastIndex(fields[2], ">")
+	if start < 0 || end < start {
+		return reflogEntry{}, false
+	}
+
+	// The date is a Unix timestamp and the offset of the zone it was recorded in, e.g. +0200
+	date := strings.Fields(fields[2][end+1:])
+	if len(date) != 2 || len(date[1]) != 5 {
+		return reflogEntry{}, false
+	}
+	timestamp, err := strconv.ParseInt(date[0], 10, 64)
+	if err != nil {
+		return reflogEntry{}, false
+	}
+	hours, hoursErr := strconv.Atoi(date[1][1:3])
+	minutes, minutesErr := strconv.Atoi(date[1][3:])
+	if hoursErr != nil || minutesErr != nil {
+		return reflogEntry{}, false
+	}
+	offset := hours*3600 + minutes*60
+	if date[1][0] == '-' {
+		offset = -offset
+	}
+
+	action, subject, _ := strings.Cut(message, ": ")
+	return reflogEntry{
+		hash: plumbing.NewHash(fields[1]),
+		signature: object.Signature{
+			Name:  strings.TrimSpace(fields[2][:start]),
+			Email: fields[2][start+1 : end],
+			When:  time.Unix(timestamp, 0).In(time.FixedZone("", offset)),
+		},
+		action:  action,
+		message: subject,
+	}, true
+}
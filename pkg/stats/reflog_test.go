@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGetReflogFromRepo tests counting the commits created locally from the reflog
+func TestGetReflogFromRepo(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t, testCommit{"dev@example.com", "Initial commit", now})
+
+	// Without reflog, there are no events
+	result, err := GetReflogFromRepo(Filter{}, dir, make(map[int][]CommitInfo))
+	if err != nil || len(result) != 0 {
+		t.Fatalf("Expected no events without reflog, got %v (%v)", result, err)
+	}
+
+	entry := func(old, new int, email string, when time.Time, message string) string {
+		return fmt.Sprintf("%040d %040d Dev <%s> %d +0200\t%s", old, new, email, when.Unix(), message)
+	}
+	lines := []string{
+		entry(0, 1, "dev@example.com", now, "commit (initial): Initial commit"),
+		entry(1, 2, "dev@example.com", now, "commit: Add feature"),
+		// The amended commit is gone from the history, but still counts
+		entry(2, 3, "dev@example.com", now, "commit (amend): Add feature"),
+		entry(3, 1, "dev@example.com", now, "checkout: moving from main to old"),
+		entry(1, 4, "dev@example.com", now.AddDate(0, 0, -2), "rebase (pick): Fix bug"),
+		entry(4, 4, "dev@example.com", now.AddDate(0, 0, -2), "rebase (finish): returning to refs/heads/main"),
+		entry(4, 5, "other@example.com", now, "commit: Not mine"),
+		entry(5, 6, "dev@example.com", now.AddDate(-1, 0, 0), "commit: Too old"),
+		"malformed line",
+	}
+	logs := filepath.Join(dir, ".git", "logs")
+	if err := os.MkdirAll(logs, 0755); err != nil {
+		t.Fatalf("Failed to create logs: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(logs, "HEAD"), []byte(strings.Join(lines, "\n")+"\n"), 0666); err != nil {
+		t.Fatalf("Failed to write reflog: %v", err)
+	}
+
+	result, err = GetReflogFromRepo(Filter{Emails: []string{"dev@example.com"}}, dir, make(map[int][]CommitInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result[0]) != 3 || len(result[2]) != 1 || len(result) != 2 {
+		t.Fatalf("Expected 3 events today and 1 two days ago, got %v", result)
+	}
+	if result[0][2].Subject != "Add feature" || result[2][0].Subject != "Fix bug" {
+		t.Errorf("Unexpected subjects: %v", result)
+	}
+	if _, offset := result[0][0].When.Zone(); offset != 2*3600 {
+		t.Errorf("Expected the zone of the reflog, got an offset of %d", offset)
+	}
+
+	// The reflog is analyzed like commits
+	analysis, err := Analyze(Options{Repositories: []string{dir}, Reflog: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if analysis.Summary.Total != 5 {
+		t.Errorf("Expected 5 events, got %d", analysis.Summary.Total)
+	}
+}
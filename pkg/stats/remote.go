@@ -0,0 +1,132 @@
+package stats
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/acheddir/git-contrib/pkg/scanner"
+)
+
+// RepoName returns a name meaningful to others for a repository: the host and path of its
+// origin remote (e.g. github.com/me/foo), or of its first remote without origin.
+// It falls back to the directory name when the repository has no remote.
+//
+// Parameters:
+//   - path: The path of the repository
+//
+// Returns:
+//   - string: The name of the repository
+func RepoName(path string) string {
+	fallback := filepath.Base(filepath.Clean(path))
+
+	url := scanner.RemoteURL(path)
+	if url == "" {
+		return fallback
+	}
+
+	if name := RemoteName(url); name != "" {
+		return name
+	}
+	return fallback
+}
+
+// RepoHost returns the host of the origin remote of a repository (or of its first remote
+// without origin), e.g. github.com. Repositories without a remote, or with a local one, are on LocalHost.
+//
+// Parameters:
+//   - path: The path of the repository
+//
+// Returns:
+//   - string: The host of the repository
+func RepoHost(path string) string {
+	if host := RemoteHost(scanner.RemoteURL(path)); host != "" {
+		return host
+	}
+	return LocalHost
+}
+
+// LocalHost is the host of the repositories without a remote, or with a remote on the same machine.
+const LocalHost = "local"
+
+// RemoteHost returns the host of a remote URL, without credentials or port,
+// e.g. github.com for both https://github.com/me/foo.git and git@github.com:me/foo.git.
+//
+// Parameters:
+//   - url: The URL of the remote
+//
+// Returns:
+//   - string: The host of the remote, empty for local remotes (file:// URLs or paths)
+func RemoteHost(url string) string {
+	url = strings.TrimSpace(url)
+	if url == "" || strings.HasPrefix(url, "file://") {
+		return ""
+	}
+
+	// Local paths have no scheme and no scp-like host
+	i := strings.Index(url, ":")
+	if !strings.Contains(url, "://") && (i < 0 || strings.Contains(url[:i], "/")) {
+		return ""
+	}
+
+	host, _, _ := strings.Cut(RemoteName(url), "/")
+
+	// Strip the port, e.g. ssh://git@host:2222/me/foo
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	return strings.ToLower(host)
+}
+
+// CommitURL returns the web page of a commit on the host of a remote, for the hosts whose URLs are known:
+// GitHub (github.com) and GitLab (gitlab.com, or a self-hosted gitlab.* host).
+//
+// Parameters:
+//   - url: The URL of the remote
+//   - hash: The full hash of the commit
+//
+// Returns:
+//   - string: The URL of the commit page, empty if the host isn't known
+func CommitURL(url string, hash string) string {
+	host := RemoteHost(url)
+	_, path, ok := strings.Cut(RemoteName(url), "/")
+	if !ok || path == "" {
+		return ""
+	}
+
+	switch {
+	case host == "github.com":
+		return "https://" + host + "/" + path + "/commit/" + hash
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return "https://" + host + "/" + path + "/-/commit/" + hash
+	}
+	return ""
+}
+
+// RemoteName turns a remote URL into a host/path name, e.g. both
+// https://user@github.com/me/foo.git and git@github.com:me/foo.git become github.com/me/foo.
+// Local remotes (file:// URLs or paths) keep their path.
+//
+// Parameters:
+//   - url: The URL of the remote
+//
+// Returns:
+//   - string: The name of the remote
+func RemoteName(url string) string {
+	name := strings.TrimSpace(url)
+
+	// Strip the scheme, e.g. https://, ssh:// or file://
+	if i := strings.Index(name, "://"); i >= 0 {
+		name = name[i+3:]
+	} else if i := strings.Index(name, ":"); i >= 0 && !strings.Contains(name[:i], "/") {
+		// scp-like syntax, user@host:path
+		name = name[:i] + "/" + name[i+1:]
+	}
+
+	// Strip the credentials, e.g. git@ or user:token@
+	if i := strings.Index(name, "@"); i >= 0 && !strings.Contains(name[:i], "/") {
+		name = name[i+1:]
+	}
+
+	name = strings.TrimSuffix(strings.TrimRight(name, "/"), ".git")
+	return strings.TrimRight(name, "/")
+}
@@ -0,0 +1,452 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Intensity levels of the cells in the contribution graph
+const (
+	LevelNone = iota
+	LevelLow
+	LevelMedium
+	LevelHigh
+)
+
+// Styles of the contribution graph
+const (
+	// StyleCells draws cells with colored backgrounds
+	StyleCells = "cells"
+	// StyleBlocks draws cells with solid blocks colored in the foreground
+	StyleBlocks = "blocks"
+)
+
+// Styles lists the supported styles of the contribution graph.
+var Styles = []string{StyleCells, StyleBlocks}
+
+// DefaultGlyphs are the characters drawn in the cells of each intensity level
+// when glyphs are enabled, so the graph doesn't rely on color alone.
+var DefaultGlyphs = []string{" ", ".", "+", "#"}
+
+// RenderOptions controls how the contribution graph is rendered.
+type RenderOptions struct {
+	// ShowCommitCount displays the number of commits on each cell
+	ShowCommitCount bool
+	// ShowDaysOfMonth displays the days of the month on the graph calendar
+	ShowDaysOfMonth bool
+	// Glyphs draws a distinct character per intensity level in each cell
+	Glyphs bool
+	// EmptyCell is the character drawn in cells without commits (if empty, a space)
+	EmptyCell string
+	// Style is the style of the graph, StyleCells or StyleBlocks (if empty, StyleCells)
+	Style string
+	// ColorScheme is the name of the color scheme (if empty, DefaultColorScheme)
+	ColorScheme string
+	// Relative computes the intensity levels from the highest daily count instead of fixed thresholds
+	Relative bool
+	// MaxCount is the highest daily count the relative levels are computed from
+	MaxCount int
+	// MaxCellValue caps the counts shown with ShowCommitCount, higher counts show as "99+"
+	// (if zero, DefaultMaxCellValue). It must fit the cell, so it can't exceed DefaultMaxCellValue.
+	MaxCellValue int
+	// AnnotateAbove only shows the counts higher than this threshold with ShowCommitCount,
+	// the quieter cells keep their color only (if zero, every cell with commits shows its count)
+	AnnotateAbove int
+	// Weekly renders one cell per week with the week's total instead of one per day
+	Weekly bool
+	// GraphOnly prints the cell rows only, without the month header and day labels
+	GraphOnly bool
+	// AlignToToday makes the current week the last column, with the days after today left out
+	AlignToToday bool
+	// Compact draws each cell with a single character, halving the width of the graph.
+	// It can't show commit counts, days of the month or week numbers, see CanCompact.
+	Compact bool
+	// WeekNumbers prints the ISO week number of each column below the month labels
+	WeekNumbers bool
+	// NoColor prints the graph without ANSI escape codes, telling the levels apart with glyphs
+	NoColor bool
+	// CollapseEmptyRows leaves out the days of the week without commits in the whole window,
+	// labelling the remaining rows with two letters so they can't be mistaken for one another
+	CollapseEmptyRows bool
+	// Delta colors each cell by the change from the day before on a diverging scale (green for
+	// ramps, red for drops) instead of its number of commits, whose values must come from Deltas
+	Delta bool
+	// Stripe draws a heavier separator before the first column of each month, so the eye can follow
+	// the columns of a wide graph. The width of the graph is unchanged.
+	Stripe bool
+	// Weeks is the exact number of week columns of the graph, whatever the data, padding the
+	// oldest columns with empty weeks or leaving them out (if zero, the weeks of Range)
+	Weeks int
+	// Range is the window of the graph, see Options.Range (if empty, DefaultGraphRange)
+	Range GraphRange
+	// Binary draws every day with commits in the same color, whatever their number,
+	// telling active days from inactive ones only (habit tracking)
+	Binary bool
+	// Mono draws each cell with a character of MonoRamp by its number of commits, without any color,
+	// so the intensity shows on e-ink and monochrome terminals. NoColor must be set too.
+	Mono bool
+	// Until is the last day of the graph, see Result.Until (if zero, today)
+	Until time.Time
+	// Colors is the color capability of the terminal, Colors8, Colors16, Colors256 or ColorsTrueColor
+	// (if empty, Colors256), see DetectColors. With Colors8 and Colors16, each color scheme falls back
+	// to its ramp of basic colors.
+	Colors string
+}
+
+// UseColor decides whether the graph is printed with colors. From the highest precedence:
+// forceColor always colors, noColor never does, a non-empty NO_COLOR environment variable
+// (see https://no-color.org) disables colors, and otherwise colors are used on terminals only.
+// The --force-color flag wins over NO_COLOR as flags override the environment everywhere else.
+//
+// Parameters:
+//   - forceColor: Whether colors are forced, e.g. when piping into less -R
+//   - noColor: Whether colors are disabled
+//   - noColorEnv: The value of the NO_COLOR environment variable
+//   - terminal: Whether the output is a terminal
+//
+// Returns:
+//   - bool: true if the graph must be colored
+func UseColor(forceColor bool, noColor bool, noColorEnv string, terminal bool) bool {
+	switch {
+	case forceColor:
+		return true
+	case noColor, noColorEnv != "":
+		return false
+	default:
+		return terminal
+	}
+}
+
+// colorReset is the ANSI escape sequence resetting the colors after a cell.
+const colorReset = "\033[0m"
+
+// reset returns the escape sequence ending a colored cell, or nothing without colors.
+func (o RenderOptions) reset() string {
+	if o.NoColor {
+		return ""
+	}
+	return colorReset
+}
+
+// blocks reports whether the cells are drawn as colored blocks.
+// Blocks can't be told apart without colors, so the cells style is used instead.
+func (o RenderOptions) blocks() bool {
+	return o.Style == StyleBlocks && !o.NoColor
+}
+
+// dayColWidth is the width of the day labels on the left side of the graph.
+const dayColWidth = 5
+
+// cellWidth returns the width of a cell of the graph, including its separator.
+func (o RenderOptions) cellWidth() int {
+	if o.Compact {
+		return 2
+	}
+	return 4
+}
+
+// CanCompact reports whether the graph can be drawn with Compact cells,
+// which have no room for commit counts, days of the month or week numbers.
+func (o RenderOptions) CanCompact() bool {
+	return !o.ShowCommitCount && !o.ShowDaysOfMonth && !o.WeekNumbers
+}
+
+// GridWidth returns the number of terminal columns the graph needs, month labels included.
+//
+// Returns:
+//   - int: The width of the widest line of the graph
+func (o RenderOptions) GridWidth() int {
+	// The day labels, one cell per week, and room for a month label on the last week
+	return dayColWidth + o.cellWidth()*o.weekColumns() + 3
+}
+
+// today returns the beginning of the last day of the graph, see Until.
+func (o RenderOptions) today() time.Time {
+	if o.Until.IsZero() {
+		return GetBeginningOfDay(time.Now())
+	}
+	return GetBeginningOfDay(o.Until)
+}
+
+// window returns the window of the graph, see Range.
+func (o RenderOptions) window() GraphRange {
+	return o.Range.orDefault()
+}
+
+// weekColumns returns the number of week columns of the graph, see Weeks.
+func (o RenderOptions) weekColumns() int {
+	if o.Weeks > 0 {
+		return o.Weeks
+	}
+	return o.window().Weeks + 1
+}
+
+// annotated reports whether a cell with the given count shows it, see AnnotateAbove.
+func (o RenderOptions) annotated(val int) bool {
+	return o.ShowCommitCount && val > 0 && val > o.AnnotateAbove
+}
+
+// DefaultMaxCellValue is the highest count that fits in a cell, higher counts show as "99+".
+const DefaultMaxCellValue = 99
+
+// countLabel returns the 3-character content of a cell showing a commit count.
+// Counts above the maximum show as the maximum followed by '+', so the grid stays aligned.
+func (o RenderOptions) countLabel(val int) string {
+	maxValue := o.MaxCellValue
+	if maxValue <= 0 || maxValue > DefaultMaxCellValue {
+		maxValue = DefaultMaxCellValue
+	}
+
+	switch {
+	case val > maxValue:
+		return fmt.Sprintf("%3s", fmt.Sprintf("%d+", maxValue))
+	case val < 10:
+		return fmt.Sprintf(" %d ", val) // Single digit with padding
+	default:
+		return fmt.Sprintf("%d ", val) // Double-digit with padding
+	}
+}
+
+// stripeSeparator returns the separator drawn before the first column of a month with Stripe.
+func (o RenderOptions) stripeSeparator() string {
+	if o.blocks() {
+		return "│"
+	}
+	return "┃"
+}
+
+// glyph returns the character to draw in a cell of the given intensity level.
+func (o RenderOptions) glyph(level int) string {
+	if level == LevelNone && o.EmptyCell != "" {
+		return o.EmptyCell
+	}
+
+	// Without colors, or with too few of them, the glyphs are the only way to tell the levels apart
+	if !o.Glyphs && !o.NoColor && !o.basicColorsRepeat() {
+		return " "
+	}
+
+	return DefaultGlyphs[level]
+}
+
+// Level returns the intensity level of a cell from its number of commits.
+//
+// Parameters:
+//   - val: The number of commits for the cell
+//
+// Returns:
+//   - int: The intensity level, from LevelNone to LevelHigh
+func Level(val int) int {
+	switch {
+	case val >= 10:
+		return LevelHigh
+	case val >= 5:
+		return LevelMedium
+	case val > 0:
+		return LevelLow
+	}
+
+	return LevelNone
+}
+
+// WeeklyLevel returns the intensity level of a weekly cell from its total number of commits.
+// The levels are those of the daily average (rounded up), so a week at LevelHigh
+// averages as many commits per day as a day at LevelHigh.
+//
+// Parameters:
+//   - total: The number of commits of the week
+//
+// Returns:
+//   - int: The intensity level, from LevelNone to LevelHigh
+func WeeklyLevel(total int) int {
+	return Level((total + DaysInWeek - 1) / DaysInWeek)
+}
+
+// ColorScheme maps the intensity levels of the cells to colors of the 256-color palette.
+type ColorScheme struct {
+	// Name is the name used to select the scheme
+	Name string
+	// Colors holds the palette index of each intensity level, from LevelNone to LevelHigh
+	Colors [4]int
+	// Basic holds one of the 16 basic colors (0 to 7, and 8 to 15 for their bright variants) for each
+	// intensity level, ramping like Colors, for the terminals without the 256-color palette, see Colors16.
+	// The levels sharing a basic color are told apart by the glyphs of the cells.
+	Basic [4]int
+}
+
+// DefaultColorScheme is the name of the color scheme used when none is selected.
+const DefaultColorScheme = "github"
+
+// ColorSchemes lists the built-in color schemes.
+var ColorSchemes = []ColorScheme{
+	{Name: "github", Colors: [4]int{248, 120, 34, 22}, Basic: [4]int{7, 10, 2, 2}},
+	{Name: "halloween", Colors: [4]int{248, 228, 214, 202}, Basic: [4]int{7, 11, 3, 1}},
+	{Name: "dracula", Colors: [4]int{248, 183, 141, 98}, Basic: [4]int{7, 13, 5, 4}},
+}
+
+// ColorSchemeNames returns the names of the built-in color schemes.
+func ColorSchemeNames() []string {
+	names := make([]string, len(ColorSchemes))
+	for i, scheme := range ColorSchemes {
+		names[i] = scheme.Name
+	}
+	return names
+}
+
+// colorScheme returns the selected color scheme, or the default one if none is selected.
+func (o RenderOptions) colorScheme() ColorScheme {
+	for _, scheme := range ColorSchemes {
+		if scheme.Name == o.ColorScheme {
+			return scheme
+		}
+	}
+
+	return ColorSchemes[0]
+}
+
+// level returns the intensity level of a cell, fixed or relative depending on the options.
+func (o RenderOptions) level(val int) int {
+	if o.Binary {
+		if val > 0 {
+			return LevelHigh
+		}
+		return LevelNone
+	}
+
+	if o.Relative {
+		return RelativeLevel(val, o.MaxCount)
+	}
+
+	if o.Weekly {
+		return WeeklyLevel(val)
+	}
+
+	return Level(val)
+}
+
+// RelativeLevel returns the intensity level of a cell relative to the highest daily count:
+// up to a third of it is LevelLow, up to two thirds LevelMedium, and above LevelHigh.
+// This gives low-activity repositories more contrast, but makes levels meaningless
+// to compare across repositories.
+//
+// Parameters:
+//   - val: The number of commits for the cell
+//   - maxCount: The highest daily count in the graph window
+//
+// Returns:
+//   - int: The intensity level, from LevelNone to LevelHigh
+func RelativeLevel(val int, maxCount int) int {
+	if val <= 0 || maxCount <= 0 {
+		return LevelNone
+	}
+
+	switch {
+	case val*3 <= maxCount:
+		return LevelLow
+	case val*3 <= maxCount*2:
+		return LevelMedium
+	}
+
+	return LevelHigh
+}
+
+// MaxCount returns the highest daily count of the commits' map.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//
+// Returns:
+//   - int: The highest daily count, 0 if there are no commits
+func MaxCount(commits map[int]int) int {
+	maxCount := 0
+	for _, count := range commits {
+		if count > maxCount {
+			maxCount = count
+		}
+	}
+	return maxCount
+}
+
+// levelLabels returns the range of commits covered by each intensity level.
+func (o RenderOptions) levelLabels() []string {
+	if !o.Relative && o.Weekly {
+		return []string{"0", "1-28", "29-63", "64+"}
+	}
+	if !o.Relative {
+		return []string{"0", "1-4", "5-9", "10+"}
+	}
+
+	labels := []string{"0", "-", "-", "-"}
+	first := make(map[int]int)
+	for val := 1; val <= o.MaxCount; val++ {
+		level := RelativeLevel(val, o.MaxCount)
+		if _, ok := first[level]; !ok {
+			first[level] = val
+		}
+		if first[level] == val {
+			labels[level] = fmt.Sprintf("%d", val)
+		} else {
+			labels[level] = fmt.Sprintf("%d-%d", first[level], val)
+		}
+	}
+	return labels
+}
+
+// levelEscape returns the ANSI escape sequence coloring the background of a cell
+// of the given intensity level.
+func (o RenderOptions) levelEscape(level int) string {
+	if o.NoColor {
+		return ""
+	}
+
+	scheme := o.colorScheme()
+	background := o.colorSGR(scheme.Colors[level], scheme.Basic[level], true)
+	if level == LevelNone {
+		return "\033[0;37;" + background + "m"
+	}
+
+	return "\033[1;30;" + background + "m"
+}
+
+// levelForeground returns the ANSI escape sequence coloring the blocks of a cell
+// of the given intensity level, using the same palette as levelEscape.
+func (o RenderOptions) levelForeground(level int) string {
+	if o.NoColor {
+		return ""
+	}
+
+	scheme := o.colorScheme()
+	return "\033[" + o.colorSGR(scheme.Colors[level], scheme.Basic[level], false) + "m"
+}
+
+// PrintLegend prints a legend mapping the cells of each intensity level
+// to their number of commits.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - opts: The options controlling how the graph is rendered
+func PrintLegend(w io.Writer, opts RenderOptions) {
+	if opts.Delta {
+		printDeltaLegend(w, opts)
+		return
+	}
+	if opts.Binary {
+		printBinaryLegend(w, opts)
+		return
+	}
+	if opts.Mono {
+		printMonoLegend(w, opts)
+		return
+	}
+
+	fmt.Fprintf(w, "\n     ")
+	for level, label := range opts.levelLabels() {
+		if opts.blocks() {
+			fmt.Fprintf(w, "%s███%s %s  ", opts.levelForeground(level), opts.reset(), label)
+			continue
+		}
+		fmt.Fprintf(w, "%s %s %s %s  ", opts.levelEscape(level), opts.glyph(level), opts.reset(), label)
+	}
+	fmt.Fprintf(w, "commits\n")
+}
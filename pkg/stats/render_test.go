@@ -0,0 +1,279 @@
+package stats
+
+import (
+	"io"
+	"testing"
+)
+
+// TestLevel tests the Level function
+func TestLevel(t *testing.T) {
+	testCases := []struct {
+		val      int
+		expected int
+	}{
+		{0, LevelNone},
+		{1, LevelLow},
+		{4, LevelLow},
+		{5, LevelMedium},
+		{9, LevelMedium},
+		{10, LevelHigh},
+		{100, LevelHigh},
+	}
+
+	for _, tc := range testCases {
+		result := Level(tc.val)
+		if result != tc.expected {
+			t.Errorf("Level(%d): expected %d, got %d", tc.val, tc.expected, result)
+		}
+	}
+}
+
+// TestRelativeLevel tests the RelativeLevel function
+func TestRelativeLevel(t *testing.T) {
+	testCases := []struct {
+		val      int
+		maxCount int
+		expected int
+	}{
+		{0, 6, LevelNone},
+		{1, 6, LevelLow},
+		{2, 6, LevelLow},
+		{3, 6, LevelMedium},
+		{4, 6, LevelMedium},
+		{5, 6, LevelHigh},
+		{6, 6, LevelHigh},
+		{2, 2, LevelHigh},
+		{1, 2, LevelMedium},
+		{1, 0, LevelNone},
+	}
+
+	for _, tc := range testCases {
+		result := RelativeLevel(tc.val, tc.maxCount)
+		if result != tc.expected {
+			t.Errorf("RelativeLevel(%d, %d): expected %d, got %d", tc.val, tc.maxCount, tc.expected, result)
+		}
+	}
+}
+
+// TestMaxCount tests the MaxCount function
+func TestMaxCount(t *testing.T) {
+	if MaxCount(map[int]int{}) != 0 {
+		t.Errorf("Expected 0 for empty map")
+	}
+	if MaxCount(map[int]int{0: 2, 1: 7, 2: 3}) != 7 {
+		t.Errorf("Expected 7")
+	}
+}
+
+// TestLevelLabels tests the RenderOptions.levelLabels method
+func TestLevelLabels(t *testing.T) {
+	opts := RenderOptions{Relative: true, MaxCount: 6}
+	expected := []string{"0", "1-2", "3-4", "5-6"}
+	result := opts.levelLabels()
+	for i := range expected {
+		if result[i] != expected[i] {
+			t.Errorf("Expected %v, got %v", expected, result)
+			break
+		}
+	}
+}
+
+// TestGlyph tests the RenderOptions.glyph method
+func TestGlyph(t *testing.T) {
+	// Test case 1: Spaces by default
+	opts := RenderOptions{}
+	for level := LevelNone; level <= LevelHigh; level++ {
+		if opts.glyph(level) != " " {
+			t.Errorf("Expected a space for level %d, got %q", level, opts.glyph(level))
+		}
+	}
+
+	// Test case 2: Distinct glyphs per level
+	opts = RenderOptions{Glyphs: true}
+	seen := make(map[string]bool)
+	for level := LevelNone; level <= LevelHigh; level++ {
+		seen[opts.glyph(level)] = true
+	}
+	if len(seen) != 4 {
+		t.Errorf("Expected 4 distinct glyphs, got %v", seen)
+	}
+
+	// Test case 3: Custom empty cell character
+	opts = RenderOptions{EmptyCell: "-"}
+	if opts.glyph(LevelNone) != "-" {
+		t.Errorf("Expected custom empty cell, got %q", opts.glyph(LevelNone))
+	}
+	if opts.glyph(LevelLow) != " " {
+		t.Errorf("Expected a space for non-empty cells, got %q", opts.glyph(LevelLow))
+	}
+}
+
+// TestCountLabel tests that counts always fit the 3-character cell
+func TestCountLabel(t *testing.T) {
+	testCases := []struct {
+		maxValue int
+		val      int
+		expected string
+	}{
+		{0, 7, " 7 "},
+		{0, 42, "42 "},
+		{0, 99, "99 "},
+		{0, 100, "99+"},
+		{0, 1234, "99+"},
+		{9, 9, " 9 "},
+		{9, 10, " 9+"},
+		{50, 51, "50+"},
+		{500, 100, "99+"},
+	}
+
+	for _, tc := range testCases {
+		opts := RenderOptions{MaxCellValue: tc.maxValue}
+		if result := opts.countLabel(tc.val); result != tc.expected {
+			t.Errorf("countLabel(%d) with max %d: expected %q, got %q", tc.val, tc.maxValue, tc.expected, result)
+		}
+	}
+}
+
+// TestAnnotated tests which cells show their count with a threshold
+func TestAnnotated(t *testing.T) {
+	testCases := []struct {
+		opts     RenderOptions
+		val      int
+		expected bool
+	}{
+		{RenderOptions{}, 5, false},
+		{RenderOptions{ShowCommitCount: true}, 0, false},
+		{RenderOptions{ShowCommitCount: true}, 1, true},
+		{RenderOptions{ShowCommitCount: true, AnnotateAbove: 5}, 5, false},
+		{RenderOptions{ShowCommitCount: true, AnnotateAbove: 5}, 6, true},
+	}
+
+	for _, tc := range testCases {
+		if result := tc.opts.annotated(tc.val); result != tc.expected {
+			t.Errorf("annotated(%d) above %d: expected %v, got %v", tc.val, tc.opts.AnnotateAbove, tc.expected, result)
+		}
+	}
+}
+
+// TestWeeklyLevel tests the WeeklyLevel function
+func TestWeeklyLevel(t *testing.T) {
+	testCases := []struct {
+		total    int
+		expected int
+	}{
+		{0, LevelNone},
+		{1, LevelLow},
+		{28, LevelLow},
+		{29, LevelMedium},
+		{63, LevelMedium},
+		{64, LevelHigh},
+	}
+
+	for _, tc := range testCases {
+		if result := WeeklyLevel(tc.total); result != tc.expected {
+			t.Errorf("WeeklyLevel(%d): expected %d, got %d", tc.total, tc.expected, result)
+		}
+	}
+
+	// Weekly cells use the weekly levels, unless relative
+	opts := RenderOptions{Weekly: true}
+	if opts.level(28) != LevelLow || (RenderOptions{Weekly: true, Relative: true, MaxCount: 28}).level(28) != LevelHigh {
+		t.Errorf("Unexpected weekly levels")
+	}
+}
+
+// TestColorScheme tests the selection of color schemes
+func TestColorScheme(t *testing.T) {
+	// Test case 1: The default scheme keeps the original greens
+	opts := RenderOptions{}
+	expected := []string{
+		"\033[0;37;48;5;248m",
+		"\033[1;30;48;5;120m",
+		"\033[1;30;48;5;34m",
+		"\033[1;30;48;5;22m",
+	}
+	for level, escape := range expected {
+		if opts.levelEscape(level) != escape {
+			t.Errorf("Level %d: expected %q, got %q", level, escape, opts.levelEscape(level))
+		}
+	}
+
+	// Test case 2: Every built-in scheme can be selected
+	for _, name := range ColorSchemeNames() {
+		opts = RenderOptions{ColorScheme: name}
+		if opts.colorScheme().Name != name {
+			t.Errorf("Expected scheme %s, got %s", name, opts.colorScheme().Name)
+		}
+	}
+
+	// Test case 3: Blocks use the scheme as foreground color
+	opts = RenderOptions{ColorScheme: "dracula"}
+	if opts.levelForeground(LevelHigh) != "\033[38;5;98m" {
+		t.Errorf("Unexpected foreground %q", opts.levelForeground(LevelHigh))
+	}
+}
+
+// TestPrintLegend tests that PrintLegend doesn't panic
+func TestPrintLegend(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintLegend(io.Discard, RenderOptions{})
+	PrintLegend(io.Discard, RenderOptions{Glyphs: true, EmptyCell: "_"})
+	PrintLegend(io.Discard, RenderOptions{Style: StyleBlocks})
+}
+
+// TestUseColor tests the precedence of the color settings
+func TestUseColor(t *testing.T) {
+	testCases := []struct {
+		name       string
+		forceColor bool
+		noColor    bool
+		noColorEnv string
+		terminal   bool
+		expected   bool
+	}{
+		{"terminal", false, false, "", true, true},
+		{"pipe", false, false, "", false, false},
+		{"no-color flag", false, true, "", true, false},
+		{"NO_COLOR", false, false, "1", true, false},
+		{"force-color in a pipe", true, false, "", false, true},
+		{"force-color wins over NO_COLOR", true, false, "1", false, true},
+	}
+
+	for _, tc := range testCases {
+		if result := UseColor(tc.forceColor, tc.noColor, tc.noColorEnv, tc.terminal); result != tc.expected {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.expected, result)
+		}
+	}
+}
+
+// TestNoColor tests that no escape codes are printed without colors
+func TestNoColor(t *testing.T) {
+	opts := RenderOptions{NoColor: true, Style: StyleBlocks}
+	if opts.levelEscape(LevelHigh) != "" || opts.levelForeground(LevelHigh) != "" || opts.reset() != "" {
+		t.Errorf("Expected no escape codes without colors")
+	}
+	if opts.blocks() {
+		t.Errorf("Expected the cells style without colors")
+	}
+	if opts.glyph(LevelHigh) != DefaultGlyphs[LevelHigh] {
+		t.Errorf("Expected glyphs without colors, got %q", opts.glyph(LevelHigh))
+	}
+}
+
+// TestGridWidth tests that compact cells halve the width of the graph
+func TestGridWidth(t *testing.T) {
+	if width := (RenderOptions{}).GridWidth(); width != 116 {
+		t.Errorf("Expected a graph 116 columns wide, got %d", width)
+	}
+	if width := (RenderOptions{Compact: true}).GridWidth(); width != 62 {
+		t.Errorf("Expected a compact graph 62 columns wide, got %d", width)
+	}
+
+	if !(RenderOptions{Glyphs: true}).CanCompact() || (RenderOptions{ShowCommitCount: true}).CanCompact() {
+		t.Errorf("Expected commit counts only to prevent compact cells")
+	}
+
+	// This just ensures the compact graph doesn't panic
+	PrintCommitsStats(io.Discard, map[int]int{0: 1, 10: 5}, RenderOptions{Compact: true, Style: StyleBlocks})
+	PrintMonths(io.Discard, RenderOptions{Compact: true})
+}
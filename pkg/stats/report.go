@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// ReportFormats lists the supported output formats of the report, for validation and completion.
+var ReportFormats = []string{FormatTerminal, FormatCSV}
+
+// ReportRow holds the activity of a single repository in the report.
+type ReportRow struct {
+	// Repository is the name of the repository, or its path, see RepoResult.Label
+	Repository string
+	// Total is the number of matched commits in the graph window
+	Total int
+	// ActiveDays is the number of days with at least one commit
+	ActiveDays int
+	// LastCommit is the author date of the most recent matched commit in the graph window
+	// (zero if none, even if the repository has older commits)
+	LastCommit time.Time
+	// CurrentStreak is the number of consecutive days with commits ending today (or yesterday)
+	CurrentStreak int
+}
+
+// Report holds the activity of every repository, and of all of them together.
+type Report struct {
+	// Rows lists the repositories, in the order of the breakdown
+	Rows []ReportRow
+	// Overall holds the activity of all the repositories together
+	Overall ReportRow
+}
+
+// BuildReport computes the activity of each repository of the breakdown, and of all of them together.
+//
+// Parameters:
+//   - result: The result of the analysis
+//   - workingDaysOnly: Whether quiet weekends should be ignored by the streaks
+//
+// Returns:
+//   - Report: The activity of the repositories
+func BuildReport(result *Result, workingDaysOnly bool) Report {
+	report := Report{Overall: ReportRow{
+		Repository:    "Total",
+		Total:         result.Summary.Total,
+		ActiveDays:    result.Summary.ActiveDays,
+		LastCommit:    result.LastCommit,
+		CurrentStreak: result.Summary.CurrentStreak,
+	}}
+
+	for _, repo := range result.Repositories {
+		summary := Summarize(repo.Counts, workingDaysOnly, result.Range)
+		report.Rows = append(report.Rows, ReportRow{
+			Repository:    repo.Label(),
+			Total:         repo.Total,
+			ActiveDays:    summary.ActiveDays,
+			LastCommit:    repo.LastCommit,
+			CurrentStreak: summary.CurrentStreak,
+		})
+	}
+
+	return report
+}
+
+// reportHeader lists the columns of the report.
+var reportHeader = []string{"repository", "commits", "active_days", "last_commit", "current_streak"}
+
+// fields returns the values of the columns of a row, see reportHeader.
+func (r ReportRow) fields() []string {
+	return []string{r.Repository, strconv.Itoa(r.Total), strconv.Itoa(r.ActiveDays), formatDate(r.LastCommit), strconv.Itoa(r.CurrentStreak)}
+}
+
+// WriteReport writes the report as an aligned table, with a line for all the repositories together.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - report: The report to write
+//
+// Returns:
+//   - error: An error if the table couldn't be written
+func WriteReport(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	row := func(r ReportRow) {
+		lastCommit := "-"
+		if !r.LastCommit.IsZero() {
+			lastCommit = formatDate(r.LastCommit)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%d\n", r.Repository, r.Total, r.ActiveDays, lastCommit, r.CurrentStreak)
+	}
+
+	_, _ = fmt.Fprintf(tw, "Repository\tCommits\tActive days\tLast commit\tStreak\n")
+	for _, r := range report.Rows {
+		row(r)
+	}
+	row(report.Overall)
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// WriteReportCSV writes the rows of the report as CSV with a header, without the line for all the repositories.
+// The last commit is formatted as YYYY-MM-DD, empty for repositories without commits.
+//
+// Parameters:
+//   - w: The writer to write to
+//   - report: The report to write
+//
+// Returns:
+//   - error: An error if the report couldn't be written
+func WriteReportCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write(reportHeader); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	for _, r := range report.Rows {
+		if err := writer.Write(r.fields()); err != nil {
+			return fmt.Errorf("failed to write CSV: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// testReport returns a report of two repositories, one of them without commits
+func testReport() Report {
+	last := time.Date(2023, 5, 14, 10, 0, 0, 0, time.UTC)
+	return Report{
+		Rows: []ReportRow{
+			{Repository: "github.com/me/api", Total: 12, ActiveDays: 5, LastCommit: last, CurrentStreak: 2},
+			{Repository: "/code/empty"},
+		},
+		Overall: ReportRow{Repository: "Total", Total: 12, ActiveDays: 5, LastCommit: last, CurrentStreak: 2},
+	}
+}
+
+// TestBuildReport tests that each repository gets its own active days and streak
+func TestBuildReport(t *testing.T) {
+	last := time.Now()
+	result := &Result{
+		Summary:    Summary{Total: 4, ActiveDays: 3, CurrentStreak: 2},
+		LastCommit: last,
+		Repositories: []RepoResult{
+			{Path: "/code/api", Name: "github.com/me/api", Total: 3, LastCommit: last, Counts: map[int]int{0: 1, 1: 2}},
+			{Path: "/code/old", Total: 1, Counts: map[int]int{10: 1}},
+		},
+	}
+
+	report := BuildReport(result, false)
+	expected := []ReportRow{
+		{Repository: "github.com/me/api", Total: 3, ActiveDays: 2, LastCommit: last, CurrentStreak: 2},
+		{Repository: "/code/old", Total: 1, ActiveDays: 1},
+	}
+	if len(report.Rows) != len(expected) {
+		t.Fatalf("Expected %d rows, got %+v", len(expected), report.Rows)
+	}
+	for i, row := range expected {
+		if report.Rows[i] != row {
+			t.Errorf("Row %d: expected %+v, got %+v", i, row, report.Rows[i])
+		}
+	}
+	if report.Overall.Total != 4 || report.Overall.ActiveDays != 3 || report.Overall.CurrentStreak != 2 {
+		t.Errorf("Unexpected overall row %+v", report.Overall)
+	}
+}
+
+// TestWriteReport tests that the table is aligned and ends with the overall row
+func TestWriteReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReport(&buf, testReport()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "Repository         Commits  Active days  Last commit  Streak\n" +
+		"github.com/me/api  12       5            2023-05-14   2\n" +
+		"/code/empty        0        0            -            0\n" +
+		"Total              12       5            2023-05-14   2\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
+
+// TestWriteReportCSV tests that the CSV has a header and no overall row
+func TestWriteReportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteReportCSV(&buf, testReport()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := "repository,commits,active_days,last_commit,current_streak\n" +
+		"github.com/me/api,12,5,2023-05-14,2\n" +
+		"/code/empty,0,0,,0\n"
+	if buf.String() != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// retryDelay is the wait before the first retry, doubled after each attempt.
+var retryDelay = 100 * time.Millisecond
+
+// transientErrnos lists the system errors network filesystems (e.g. NFS, SMB) return
+// on a blip, which may succeed when tried again.
+var transientErrnos = []syscall.Errno{syscall.EIO, syscall.ESTALE, syscall.EAGAIN, syscall.EINTR, syscall.EBUSY, syscall.ETIMEDOUT}
+
+// IsTransient reports whether an error may go away when the operation is tried again,
+// like an I/O error or a stale file handle of a network filesystem. Errors telling that
+// there is no repository or no such file are permanent.
+//
+// Parameters:
+//   - err: The error to check
+//
+// Returns:
+//   - bool: true if the operation is worth retrying
+func IsTransient(err error) bool {
+	if err == nil || errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+		return false
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		for _, transient := range transientErrnos {
+			if errno == transient {
+				return true
+			}
+		}
+	}
+
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// withRetries runs op, and runs it again up to retries times while it fails with a transient
+// error (see IsTransient), waiting retryDelay before the first retry and twice as long after each.
+//
+// Parameters:
+//   - retries: The number of times op is tried again (if zero, it is run once)
+//   - op: The operation to run
+//
+// Returns:
+//   - error: The error of the last attempt, nil if one succeeded
+func withRetries(retries int, op func() error) error {
+	delay := retryDelay
+	err := op()
+	for attempt := 0; attempt < retries && IsTransient(err); attempt++ {
+		time.Sleep(delay)
+		delay *= 2
+		err = op()
+	}
+	return err
+}
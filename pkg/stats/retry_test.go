@@ -0,0 +1,75 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// TestIsTransient tests the classification of errors worth retrying
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"stale handle", &fs.PathError{Op: "open", Path: "/nfs/repo/.git/HEAD", Err: syscall.ESTALE}, true},
+		{"wrapped I/O error", fmt.Errorf("failed to open repository: %w", &fs.PathError{Op: "read", Err: syscall.EIO}), true},
+		{"deadline", os.ErrDeadlineExceeded, true},
+		{"not a repository", fmt.Errorf("failed to open repository: %w", git.ErrRepositoryNotExists), false},
+		{"missing file", &fs.PathError{Op: "open", Err: syscall.ENOENT}, false},
+		{"other", errors.New("object not found"), false},
+	}
+
+	for _, test := range tests {
+		if result := IsTransient(test.err); result != test.expected {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, result)
+		}
+	}
+}
+
+// TestWithRetries tests that only transient errors are retried, at most the given number of times
+func TestWithRetries(t *testing.T) {
+	previous := retryDelay
+	retryDelay = 0
+	t.Cleanup(func() { retryDelay = previous })
+
+	// Test case 1: A blip is retried until the operation succeeds
+	attempts := 0
+	err := withRetries(3, func() error {
+		attempts++
+		if attempts < 3 {
+			return syscall.EIO
+		}
+		return nil
+	})
+	if err != nil || attempts != 3 {
+		t.Errorf("Expected success after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	// Test case 2: The retries run out
+	attempts = 0
+	err = withRetries(2, func() error {
+		attempts++
+		return syscall.ESTALE
+	})
+	if !errors.Is(err, syscall.ESTALE) || attempts != 3 {
+		t.Errorf("Expected the error after 3 attempts, got %v after %d", err, attempts)
+	}
+
+	// Test case 3: Permanent errors aren't retried
+	attempts = 0
+	err = withRetries(5, func() error {
+		attempts++
+		return git.ErrRepositoryNotExists
+	})
+	if !errors.Is(err, git.ErrRepositoryNotExists) || attempts != 1 {
+		t.Errorf("Expected a single attempt, got %d", attempts)
+	}
+}
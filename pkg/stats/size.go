@@ -0,0 +1,117 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// SizeBand is a range of commit sizes, in lines added and deleted.
+type SizeBand struct {
+	// Label names the band
+	Label string
+	// MaxLines is the largest size in the band (if negative, the band has no upper bound)
+	MaxLines int
+}
+
+// SizeBands lists the bands commits are bucketed into, from smallest to largest.
+var SizeBands = []SizeBand{
+	{Label: "small (0-10 lines)", MaxLines: 10},
+	{Label: "medium (11-100 lines)", MaxLines: 100},
+	{Label: "large (101+ lines)", MaxLines: -1},
+}
+
+// SizeBandCount holds the number of commits whose size falls in a band.
+type SizeBandCount struct {
+	// Band is the size band
+	Band SizeBand
+	// Count is the number of commits in the band
+	Count int
+}
+
+// fillCommitSizes computes the lines changed by each matched commit of a repository.
+// It diffs every commit against its first parent, so it is much slower than reading the log.
+func fillCommitSizes(path string, commits map[int][]CommitInfo) error {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return fmt.Errorf("failed to open repository at %s: %w", path, err)
+	}
+
+	for _, dayCommits := range commits {
+		for i := range dayCommits {
+			commit, err := repo.CommitObject(plumbing.NewHash(dayCommits[i].Hash))
+			if err != nil {
+				return fmt.Errorf("failed to get commit %s: %w", dayCommits[i].ShortHash(), err)
+			}
+
+			fileStats, err := commit.Stats()
+			if err != nil {
+				return fmt.Errorf("failed to compute the changes of commit %s: %w", dayCommits[i].ShortHash(), err)
+			}
+
+			dayCommits[i].LinesChanged = 0
+			for _, file := range fileStats {
+				dayCommits[i].LinesChanged += file.Addition + file.Deletion
+			}
+		}
+	}
+
+	return nil
+}
+
+// SizeHistogram buckets the commits by the number of lines they changed.
+// The sizes must have been computed with Options.CommitSizes.
+//
+// Parameters:
+//   - commits: A map of days to matched commits
+//
+// Returns:
+//   - []SizeBandCount: The number of commits of each band, from smallest to largest
+func SizeHistogram(commits map[int][]CommitInfo) []SizeBandCount {
+	histogram := make([]SizeBandCount, len(SizeBands))
+	for i, band := range SizeBands {
+		histogram[i].Band = band
+	}
+
+	for _, dayCommits := range commits {
+		for _, c := range dayCommits {
+			for i, band := range SizeBands {
+				if band.MaxLines < 0 || c.LinesChanged <= band.MaxLines {
+					histogram[i].Count++
+					break
+				}
+			}
+		}
+	}
+
+	return histogram
+}
+
+// PrintSizeHistogram prints the number of commits of each size band, with a bar
+// scaled to the largest band.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - histogram: The number of commits of each band
+func PrintSizeHistogram(w io.Writer, histogram []SizeBandCount) {
+	maxCount := 0
+	for _, band := range histogram {
+		if band.Count > maxCount {
+			maxCount = band.Count
+		}
+	}
+
+	const barWidth = 40
+
+	fmt.Fprintf(w, "\n")
+	for _, band := range histogram {
+		bar := 0
+		if maxCount > 0 {
+			bar = band.Count * barWidth / maxCount
+		}
+		fmt.Fprintf(w, "%-22s %6d  %s\n", band.Band.Label, band.Count, strings.Repeat("#", bar))
+	}
+}
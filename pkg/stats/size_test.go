@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TestCommitSizes tests that commit sizes are computed and bucketed
+func TestCommitSizes(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	// Commits adding 5, then 50, then 200 lines to a file
+	lines := 0
+	for _, added := range []int{5, 50, 200} {
+		lines += added
+		content := strings.Repeat("line\n", lines)
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(content), 0666); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		if _, err := worktree.Add("file.txt"); err != nil {
+			t.Fatalf("Failed to add file: %v", err)
+		}
+		signature := &object.Signature{Name: "Me", Email: "me@example.com", When: time.Now()}
+		if _, err := worktree.Commit("Add lines", &git.CommitOptions{Author: signature, Committer: signature}); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	// Sizes are only computed on demand
+	result, err := Analyze(Options{Repositories: []string{dir}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, c := range result.Commits[0] {
+		if c.LinesChanged != 0 {
+			t.Errorf("Expected no sizes without CommitSizes, got %d", c.LinesChanged)
+		}
+	}
+
+	result, err = Analyze(Options{Repositories: []string{dir}, CommitSizes: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	histogram := SizeHistogram(result.Commits)
+	for i, expected := range []int{1, 1, 1} {
+		if histogram[i].Count != expected {
+			t.Errorf("Expected %d commits in %s, got %d", expected, histogram[i].Band.Label, histogram[i].Count)
+		}
+	}
+}
+
+// TestSizeHistogram tests the band boundaries of SizeHistogram
+func TestSizeHistogram(t *testing.T) {
+	commits := map[int][]CommitInfo{
+		0: {{LinesChanged: 0}, {LinesChanged: 10}, {LinesChanged: 11}},
+		1: {{LinesChanged: 100}, {LinesChanged: 101}, {LinesChanged: 5000}},
+	}
+
+	histogram := SizeHistogram(commits)
+	for i, expected := range []int{2, 2, 2} {
+		if histogram[i].Count != expected {
+			t.Errorf("Expected %d commits in %s, got %d", expected, histogram[i].Band.Label, histogram[i].Count)
+		}
+	}
+
+	// This just ensures the function doesn't panic, even without commits
+	PrintSizeHistogram(io.Discard, histogram)
+	PrintSizeHistogram(io.Discard, SizeHistogram(nil))
+}
@@ -1,12 +1,20 @@
 package stats
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"sort"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/text/unicode/norm"
 )
 
 // Constants for time calculations and display
@@ -20,6 +28,266 @@ const (
 
 type Column []int
 
+// ErrEmptyHead is returned when HEAD points to a branch without commits (e.g. an orphan branch)
+// while other branches of the repository have some.
+var ErrEmptyHead = errors.New("no commits on the branch of HEAD")
+
+// CommitInfo holds the metadata of a commit matched by the filter.
+type CommitInfo struct {
+	// Hash is the full hash of the commit
+	Hash string
+	// Subject is the first line of the commit message
+	Subject string
+	// Email is the author email of the commit
+	Email string
+	// When is the author date of the commit
+	When time.Time
+	// LinesChanged is the number of lines added and deleted (only computed with Options.CommitSizes)
+	LinesChanged int
+	// Repository is the path of the repository the commit was read from (only set by Analyze)
+	Repository string
+}
+
+// DefaultShortHashLength is the number of characters of the abbreviated hashes, like git's default.
+const DefaultShortHashLength = 7
+
+// ShortHash returns the abbreviated hash of the commit, see DefaultShortHashLength.
+func (c CommitInfo) ShortHash() string {
+	return c.AbbreviatedHash(DefaultShortHashLength)
+}
+
+// AbbreviatedHash returns the first characters of the hash of the commit.
+//
+// Parameters:
+//   - length: The number of characters to keep (if zero or less, DefaultShortHashLength)
+//
+// Returns:
+//   - string: The abbreviated hash, the whole hash if it is shorter
+func (c CommitInfo) AbbreviatedHash(length int) string {
+	if length <= 0 {
+		length = DefaultShortHashLength
+	}
+	if len(c.Hash) > length {
+		return c.Hash[:length]
+	}
+	return c.Hash
+}
+
+// DefaultBotPatterns lists the author email patterns of common CI bots
+// (dependabot, renovate, github-actions, ...).
+var DefaultBotPatterns = []string{
+	"*[bot]*",
+	"action@github.com",
+}
+
+// Filter describes which commits should be counted in the contribution graph.
+type Filter struct {
+	// Emails is the list of author email addresses to include (if empty, includes all commits)
+	Emails []string
+	// ExcludeEmails is the list of author email addresses to skip, even without Emails
+	ExcludeEmails []string
+	// ExcludeBots skips commits whose author email matches one of the bot patterns
+	ExcludeBots bool
+	// BotPatterns extends DefaultBotPatterns, '*' matches any sequence of characters
+	BotPatterns []string
+	// CoAuthors also counts commits whose Co-authored-by trailers match the filter
+	CoAuthors bool
+	// Subdir only counts the commits changing files under this directory of the repositories,
+	// normalized with CleanSubdir (if empty, all commits). Tags have no changes, so it doesn't apply to them.
+	Subdir string
+	// ExcludeWeekends skips the commits authored on a Saturday or a Sunday, in the time zone of their author.
+	// The weekend is always Saturday and Sunday, whatever the layout of the graph.
+	ExcludeWeekends bool
+	// Identities maps normalized emails to the canonical email of their author, so the aliases
+	// of a person are filtered and counted as one (if nil, every email is its own author)
+	Identities map[string]string
+	// Trace receives a line for every skipped commit with the reason (if nil, nothing is written).
+	// It must be safe for concurrent use, as repositories are processed concurrently.
+	Trace io.Writer
+}
+
+// Reasons commits are skipped for, as reported to Filter.Trace
+const (
+	SkipBot           = "bot author"
+	SkipExcluded      = "excluded email"
+	SkipEmailMismatch = "email mismatch"
+	SkipOutOfRange    = "out of range"
+	SkipOutsideSubdir = "outside subdirectory"
+	SkipWeekend       = "weekend"
+)
+
+// Matches reports whether a commit authored by the given email passes the filter.
+//
+// Parameters:
+//   - email: The author email of the commit
+//
+// Returns:
+//   - bool: true if the commit should be counted, false otherwise
+func (f Filter) Matches(email string) bool {
+	return f.rejectReason(email) == ""
+}
+
+// Identity returns the canonical email of the author of the given email, see Filter.Identities.
+//
+// Parameters:
+//   - email: The email to look up
+//
+// Returns:
+//   - string: The canonical email, or the email itself (sanitized, see SanitizeEmail) if it isn't an alias
+func (f Filter) Identity(email string) string {
+	if canonical, ok := f.Identities[NormalizeEmail(email)]; ok {
+		return canonical
+	}
+	return SanitizeEmail(email)
+}
+
+// rejectReason returns why a commit authored by the given email is skipped, or an empty string.
+func (f Filter) rejectReason(email string) string {
+	if f.ExcludeBots && f.IsBot(email) {
+		return SkipBot
+	}
+
+	// Emails are compared trimmed and lowercased, aliases as their canonical email
+	normalized := NormalizeEmail(f.Identity(email))
+	for _, e := range f.ExcludeEmails {
+		if NormalizeEmail(f.Identity(e)) == normalized {
+			return SkipExcluded
+		}
+	}
+
+	if len(f.Emails) == 0 {
+		return ""
+	}
+
+	for _, e := range f.Emails {
+		if NormalizeEmail(f.Identity(e)) == normalized {
+			return ""
+		}
+	}
+
+	return SkipEmailMismatch
+}
+
+// MatchesCommit reports whether a commit passes the filter, either through its author
+// or, when CoAuthors is set, through one of its Co-authored-by trailers.
+//
+// Parameters:
+//   - author: The author email of the commit
+//   - message: The full commit message
+//
+// Returns:
+//   - bool: true if the commit should be counted, false otherwise
+func (f Filter) MatchesCommit(author string, message string) bool {
+	return f.RejectReason(author, message) == ""
+}
+
+// RejectReason returns why a commit is skipped by the filter, see MatchesCommit.
+//
+// Parameters:
+//   - author: The author email of the commit
+//   - message: The full commit message
+//
+// Returns:
+//   - string: SkipBot, SkipExcluded or SkipEmailMismatch, empty if the commit should be counted
+func (f Filter) RejectReason(author string, message string) string {
+	reason := f.rejectReason(author)
+	if reason == "" {
+		return ""
+	}
+
+	if f.CoAuthors {
+		for _, coAuthor := range ParseCoAuthors(message) {
+			if f.Matches(coAuthor) {
+				return ""
+			}
+		}
+	}
+
+	return reason
+}
+
+// trace reports a skipped commit (or tag) to Trace, if set.
+func (f Filter) trace(path string, hash plumbing.Hash, signature object.Signature, reason string) {
+	if f.Trace == nil {
+		return
+	}
+	_, _ = fmt.Fprintf(f.Trace, "%s: skipped %s <%s> %s: %s\n",
+		path, hash.String()[:7], SanitizeEmail(signature.Email), signature.When.Format("2006-01-02"), reason)
+}
+
+// coAuthorTrailer is the key of the trailers crediting the co-authors of a commit.
+const coAuthorTrailer = "co-authored-by:"
+
+// ParseCoAuthors extracts the emails of the "Co-authored-by: Name <email>" trailers
+// of a commit message. The key is matched case-insensitively, as GitHub does.
+//
+// Parameters:
+//   - message: The full commit message
+//
+// Returns:
+//   - []string: The co-author emails, in message order
+func ParseCoAuthors(message string) []string {
+	var emails []string
+
+	for _, line := range strings.Split(message, "\n") {
+		line = strings.TrimSpace(line)
+		if len(line) < len(coAuthorTrailer) || !strings.EqualFold(line[:len(coAuthorTrailer)], coAuthorTrailer) {
+			continue
+		}
+
+		// The email is enclosed in angle brackets after the name
+		start := strings.LastIndex(line, "<")
+		end := strings.LastIndex(line, ">")
+		if start < 0 || end < start {
+			continue
+		}
+
+		if email := strings.TrimSpace(line[start+1 : end]); email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	return emails
+}
+
+// zeroWidth removes the invisible characters copy-pasted emails sometimes carry:
+// zero-width space, non-joiner and joiner, word joiner and byte order mark.
+var zeroWidth = strings.NewReplacer("\u200b", "", "\u200c", "", "\u200d", "", "\u2060", "", "\ufeff", "")
+
+// SanitizeEmail returns the email with its invalid UTF-8 bytes and control characters replaced by U+FFFD,
+// so the malformed author fields of some old commits can't garble the output (e.g. escape sequences in a table).
+//
+// Parameters:
+//   - email: The email to sanitize, as read from a commit
+//
+// Returns:
+//   - string: The sanitized email, valid UTF-8
+func SanitizeEmail(email string) string {
+	if utf8.ValidString(email) && strings.IndexFunc(email, unicode.IsControl) < 0 {
+		return email
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return utf8.RuneError
+		}
+		return r
+	}, strings.ToValidUTF8(email, string(utf8.RuneError)))
+}
+
+// NormalizeEmail returns the email in the form used for comparisons and grouping: sanitized
+// (see SanitizeEmail), without zero-width characters, in Unicode normalization form C (so composed and decomposed accents
+// are the same), trimmed and lowercased. Git emails are effectively case-insensitive,
+// and people capitalize them inconsistently.
+//
+// Parameters:
+//   - email: The email to normalize
+//
+// Returns:
+//   - string: The normalized email
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(norm.NFC.String(zeroWidth.Replace(SanitizeEmail(strings.TrimSpace(email))))))
+}
+
 // GetBeginningOfDay returns a new time.Time with the same date as the input time
 // but with the time set to 00:00:00.
 //
@@ -35,28 +303,46 @@ func GetBeginningOfDay(t time.Time) time.Time {
 }
 
 // CountDaysSinceDate calculates the number of days between the given date and today.
-// If the difference is greater than DaysInLastSixMonths, it returns OutOfRange.
+// If the difference is greater than the days of the window, it returns OutOfRange.
 //
 // Parameters:
 //   - date: The starting date to count from
+//   - window: The window of the graph (if empty, DefaultGraphRange)
 //
 // Returns:
-//   - int: The number of days since the given date, or OutOfRange if more than DaysInLastSixMonths
-func CountDaysSinceDate(date time.Time) int {
-	// Normalize both dates to the beginning of their respective days
+//   - int: The number of days since the given date, or OutOfRange if before the window
+func CountDaysSinceDate(date time.Time, window GraphRange) int {
+	return countDaysSince(date, GetBeginningOfDay(time.Now()), window.orDefault())
+}
+
+// countDaysSince calculates the number of days between the given date and the beginning
+// of today, see CountDaysSinceDate. Loops over many commits compute today once and call it.
+func countDaysSince(date time.Time, today time.Time, window GraphRange) int {
+	// Normalize the date to the beginning of its day
 	date = GetBeginningOfDay(date)
-	now := GetBeginningOfDay(time.Now())
 
 	// Calculate the difference in days
-	diff := now.Sub(date)
+	diff := today.Sub(date)
 	days := int(diff.Hours() / HoursInDay)
 
-	if days > DaysInLastSixMonths {
+	if days > window.Days {
 		return OutOfRange
 	}
 	return days
 }
 
+// DateOfDay returns the beginning of the day the given number of days ago.
+// It is the inverse of CountDaysSinceDate.
+//
+// Parameters:
+//   - daysAgo: The number of days ago
+//
+// Returns:
+//   - time.Time: The beginning of that day (UTC)
+func DateOfDay(daysAgo int) time.Time {
+	return GetBeginningOfDay(time.Now()).AddDate(0, 0, -daysAgo)
+}
+
 // CalculateWeekdayOffset calculates an offset value based on the current day of the week.
 // This is used for positioning in the contribution graph.
 //
@@ -85,52 +371,341 @@ func CalculateWeekdayOffset() int {
 	return 0 // Should never reach here
 }
 
+// IsBot reports whether the given email matches one of the default or configured bot patterns.
+//
+// Parameters:
+//   - email: The author email of the commit
+//
+// Returns:
+//   - bool: true if the email belongs to a bot, false otherwise
+func (f Filter) IsBot(email string) bool {
+	for _, patterns := range [][]string{DefaultBotPatterns, f.BotPatterns} {
+		for _, pattern := range patterns {
+			if MatchPattern(pattern, email) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// MatchPattern reports whether the value matches a case-insensitive pattern
+// in which '*' matches any sequence of characters. Unlike filepath.Match,
+// every other character (including '[') is matched literally.
+//
+// Parameters:
+//   - pattern: The pattern to match against
+//   - value: The value to match
+//
+// Returns:
+//   - bool: true if the value matches the pattern, false otherwise
+func MatchPattern(pattern string, value string) bool {
+	pattern = strings.ToLower(pattern)
+	value = strings.ToLower(value)
+
+	parts := strings.Split(pattern, "*")
+
+	// Without wildcards, the pattern must match exactly
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	// The first part must be a prefix and the last one a suffix
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	if len(value) < len(last) || !strings.HasSuffix(value, last) {
+		return false
+	}
+	value = value[:len(value)-len(last)]
+
+	// The parts in between must appear in order
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+
+	return true
+}
+
+// ResolveHead returns the reference to start the commit history from.
+// It is HEAD, unless HEAD points to a branch without commits (e.g. in a bare mirror
+// or a freshly fetched repository), in which case it falls back to the default branch
+// of the origin remote (refs/remotes/origin/HEAD), then to the branch configured
+// with init.defaultBranch. If none of them exists but other branches have commits,
+// the error wraps ErrEmptyHead and names one of them, rather than only reporting
+// that HEAD can't be resolved.
+//
+// Parameters:
+//   - repo: The repository to resolve the reference in
+//
+// Returns:
+//   - *plumbing.Reference: The reference to start the commit history from
+//   - error: An error if no reference could be resolved
+func ResolveHead(repo *git.Repository) (*plumbing.Reference, error) {
+	head, err := repo.Head()
+	if err == nil {
+		return head, nil
+	}
+	if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+
+	// Fall back to the default branch of the origin remote
+	if ref, err := repo.Reference(plumbing.NewRemoteHEADReferenceName("origin"), true); err == nil {
+		return ref, nil
+	}
+
+	// Fall back to the configured default branch, locally or on the origin remote
+	cfg, cfgErr := repo.ConfigScoped(config.SystemScope)
+	if cfgErr == nil && cfg.Init.DefaultBranch != "" {
+		names := []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(cfg.Init.DefaultBranch),
+			plumbing.NewRemoteReferenceName("origin", cfg.Init.DefaultBranch),
+		}
+		for _, name := range names {
+			if ref, err := repo.Reference(name, true); err == nil {
+				return ref, nil
+			}
+		}
+	}
+
+	// HEAD points to a branch without commits, tell which branch has some
+	if head, headErr := repo.Storer.Reference(plumbing.HEAD); headErr == nil && head.Type() == plumbing.SymbolicReference {
+		if branch := firstBranch(repo); branch != "" {
+			return nil, fmt.Errorf("%w: HEAD points to %s, but %s has commits, use --all-branches or --head %s",
+				ErrEmptyHead, head.Target().Short(), branch, branch)
+		}
+	}
+
+	return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+}
+
+// firstBranch returns the name of the first local branch in alphabetical order,
+// or an empty string if the repository has none.
+func firstBranch(repo *git.Repository) string {
+	branches, err := repo.Branches()
+	if err != nil {
+		return ""
+	}
+
+	var names []string
+	_ = branches.ForEach(func(ref *plumbing.Reference) error {
+		names = append(names, ref.Name().Short())
+		return nil
+	})
+	if len(names) == 0 {
+		return ""
+	}
+
+	sort.Strings(names)
+	return names[0]
+}
+
+// IsShallow reports whether a repository is a shallow clone, whose history stops at grafted
+// commits (listed in .git/shallow). Commits older than the grafts are missing from its log.
+//
+// Parameters:
+//   - path: The path to the Git repository
+//
+// Returns:
+//   - bool: Whether the repository is a shallow clone
+//   - error: An error if the repository couldn't be opened
+func IsShallow(path string) (bool, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return false, fmt.Errorf("failed to open repository at %s: %w", path, err)
+	}
+
+	grafts, err := repo.Storer.Shallow()
+	if err != nil {
+		return false, fmt.Errorf("failed to read shallow commits of %s: %w", path, err)
+	}
+
+	return len(grafts) > 0, nil
+}
+
 // GetCommitsFromRepo retrieves commit information from a Git repository.
-// If an email is provided, it filters commits by that email address.
+// If the filter lists email addresses, only commits authored by one of them are counted.
 // If no email is provided, it includes commits from all users.
-// It updates the provided commits map with the count of commits per day.
+// It updates the provided commits map with the matched commits of each day.
 //
 // Parameters:
-//   - email: The email address to filter commits by (if empty, includes all commits)
+//   - filter: The filter deciding which commits to count
 //   - path: The path to the Git repository
-//   - commits: A map of days to commit counts to update
+//   - commits: A map of days to matched commits to update
 //
 // Returns:
-//   - map[int]int: The updated commits map
+//   - map[int][]CommitInfo: The updated commits map
 //   - error: An error if any occurred during repository processing
-func GetCommitsFromRepo(email string, path string, commits map[int]int) (map[int]int, error) {
-	// Open the git repository
-	repo, err := git.PlainOpen(path)
+func GetCommitsFromRepo(filter Filter, path string, commits map[int][]CommitInfo) (map[int][]CommitInfo, error) {
+	return GetCommitsFromRevision(filter, path, "", commits)
+}
+
+// GetCommitsFromRevision retrieves commit information like GetCommitsFromRepo, walking
+// the history reachable from the given revision instead of HEAD.
+//
+// Parameters:
+//   - filter: The filter deciding which commits to count
+//   - path: The path to the Git repository
+//   - revision: The branch, tag, commit or any revision git understands (e.g. origin/develop, v2.0, HEAD~10)
+//     to start the history from (if empty, HEAD, see ResolveHead)
+//   - commits: A map of days to matched commits to update
+//
+// Returns:
+//   - map[int][]CommitInfo: The updated commits map
+//   - error: An error if the revision can't be resolved or any occurred during repository processing
+func GetCommitsFromRevision(filter Filter, path string, revision string, commits map[int][]CommitInfo) (map[int][]CommitInfo, error) {
+	return getCommitsFromRevision(filter, path, revision, 0, commits, GetBeginningOfDay(time.Now()), DefaultGraphRange)
+}
+
+// getCommitsFromRevision retrieves commit information like GetCommitsFromRevision, trying
+// to open the repository and resolve the revision again up to retries times on transient errors.
+// The days ago are counted from the given day, within the given window.
+func getCommitsFromRevision(filter Filter, path string, revision string, retries int, commits map[int][]CommitInfo, today time.Time, window GraphRange) (map[int][]CommitInfo, error) {
+	var repo *git.Repository
+	var start plumbing.Hash
+	err := withRetries(retries, func() error {
+		// Open the git repository, following .git files of linked worktrees to their common dir
+		var err error
+		repo, err = git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			return fmt.Errorf("failed to open repository at %s: %w", path, err)
+		}
+
+		start, err = ResolveStart(repo, revision)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to open repository at %s: %w", path, err)
+		return nil, err
 	}
 
-	// Get the HEAD reference
-	ref, err := repo.Head()
+	return getCommits(filter, repo, path, &git.LogOptions{From: start}, commits, today, window)
+}
+
+// GetCommitsFromAllBranches retrieves commit information like GetCommitsFromRepo, walking
+// the history of every branch, remote branch and tag, like git log --all, instead of HEAD.
+// A commit reachable from several of them is counted once.
+//
+// Parameters:
+//   - filter: The filter deciding which commits to count
+//   - path: The path to the Git repository
+//   - commits: A map of days to matched commits to update
+//
+// Returns:
+//   - map[int][]CommitInfo: The updated commits map
+//   - error: An error if any occurred during repository processing
+func GetCommitsFromAllBranches(filter Filter, path string, commits map[int][]CommitInfo) (map[int][]CommitInfo, error) {
+	return getCommitsFromAllBranches(filter, path, 0, commits, GetBeginningOfDay(time.Now()), DefaultGraphRange)
+}
+
+// getCommitsFromAllBranches retrieves commit information like GetCommitsFromAllBranches, trying to open
+// the repository again up to retries times on transient errors. The days ago are counted from the given day,
+// within the given window.
+func getCommitsFromAllBranches(filter Filter, path string, retries int, commits map[int][]CommitInfo, today time.Time, window GraphRange) (map[int][]CommitInfo, error) {
+	var repo *git.Repository
+	err := withRetries(retries, func() error {
+		var err error
+		repo, err = git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			return fmt.Errorf("failed to open repository at %s: %w", path, err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+		return nil, err
 	}
 
-	// Get the commit history starting from HEAD
-	iterator, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	return getCommits(filter, repo, path, &git.LogOptions{All: true}, commits, today, window)
+}
+
+// ResolveStart returns the commit to start the history from: the given revision,
+// or HEAD (see ResolveHead) if it is empty.
+//
+// Parameters:
+//   - repo: The repository to resolve the revision in
+//   - revision: The revision to resolve (if empty, HEAD)
+//
+// Returns:
+//   - plumbing.Hash: The commit to start the history from
+//   - error: An error if the revision can't be resolved
+func ResolveStart(repo *git.Repository, revision string) (plumbing.Hash, error) {
+	if revision == "" {
+		ref, err := ResolveHead(repo)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return ref.Hash(), nil
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to resolve revision %s: %w", revision, err)
+	}
+
+	return *hash, nil
+}
+
+// getCommits walks the history of an opened repository from the given log options, see GetCommitsFromRepo.
+// The beginning of today is computed once by the caller rather than for every commit,
+// which matters on histories of hundreds of thousands of commits.
+func getCommits(filter Filter, repo *git.Repository, path string, from *git.LogOptions, commits map[int][]CommitInfo, today time.Time, window GraphRange) (map[int][]CommitInfo, error) {
+	// Commits authored before the first day of the window are out of range
+	windowStart := today.AddDate(0, 0, -window.Days)
+
+	// Get the commit history starting from the start commit, or every reference
+	iterator, err := repo.Log(from)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get commit log: %w", err)
 	}
 
 	// Iterate through the commits
 	err = iterator.ForEach(func(c *object.Commit) error {
-		// If email is provided, skip commits not authored by the specified email
-		if email != "" && c.Author.Email != email {
+		// Skip commits not authored (or co-authored) by one of the filtered emails, or authored by bots
+		if reason := filter.RejectReason(c.Author.Email, c.Message); reason != "" {
+			filter.trace(path, c.Hash, c.Author, reason)
 			return nil
 		}
 
-		daysAgo := CountDaysSinceDate(c.Author.When)
+		// Only count commits within the window (and not after its end)
+		if c.Author.When.Before(windowStart) || !c.Author.When.Before(today.AddDate(0, 0, 1)) {
+			filter.trace(path, c.Hash, c.Author, SkipOutOfRange)
+			return nil
+		}
 
-		// Only count commits within the last six months
-		if daysAgo != OutOfRange {
-			commits[daysAgo]++
+		// Skip the commits of the weekend, based on the weekday of the author date
+		if filter.ExcludeWeekends && isWeekend(c.Author.When) {
+			filter.trace(path, c.Hash, c.Author, SkipWeekend)
+			return nil
 		}
 
+		// Only count commits changing the subdirectory, checked last as it reads trees
+		if filter.Subdir != "" {
+			touched, err := touchesSubdir(c, filter.Subdir)
+			if err != nil {
+				return fmt.Errorf("failed to compare the trees of commit %s: %w", c.Hash.String()[:7], err)
+			}
+			if !touched {
+				filter.trace(path, c.Hash, c.Author, SkipOutsideSubdir)
+				return nil
+			}
+		}
+
+		daysAgo := countDaysSince(c.Author.When, today, window)
+		commits[daysAgo] = append(commits[daysAgo], CommitInfo{
+			Hash:    c.Hash.String(),
+			Subject: strings.SplitN(strings.TrimSpace(c.Message), "\n", 2)[0],
+			Email:   filter.Identity(c.Author.Email),
+			When:    c.Author.When,
+		})
+
 		return nil
 	})
 
@@ -141,27 +716,23 @@ func GetCommitsFromRepo(email string, path string, commits map[int]int) (map[int
 	return commits, nil
 }
 
-// ProcessRepositories processes a Git repository and collects commit statistics.
-// If an email is provided, it filters commits by that email address.
+// ProcessRepositories processes a Git repository and collects the matched commits.
+// If the filter lists email addresses, only commits authored by one of them are counted.
 // If no email is provided, it includes commits from all users.
 //
 // Parameters:
-//   - email: The email address to filter commits by (if empty, includes all commits)
+//   - filter: The filter deciding which commits to count
 //   - directory: The directory to analyze (should be a Git repository)
+//   - window: The window of the graph (if empty, DefaultGraphRange)
 //
 // Returns:
-//   - map[int]int: A map of days to commit counts
+//   - map[int][]CommitInfo: A map of days to matched commits
 //   - error: An error if any occurred during processing
-func ProcessRepositories(email string, directory string) (map[int]int, error) {
-	// Initialize the commits' map with zeros for all days
-	commits := make(map[int]int, DaysInLastSixMonths)
-	for i := DaysInLastSixMonths; i > 0; i-- {
-		commits[i] = 0
-	}
+func ProcessRepositories(filter Filter, directory string, window GraphRange) (map[int][]CommitInfo, error) {
+	commits := make(map[int][]CommitInfo)
 
 	// Process the repository
-	var err error
-	commits, err = GetCommitsFromRepo(email, directory, commits)
+	commits, err := getCommitsFromRevision(filter, directory, "", 0, commits, GetBeginningOfDay(time.Now()), window.orDefault())
 	if err != nil {
 		return nil, fmt.Errorf("error processing repository at %s: %w", directory, err)
 	}
@@ -169,48 +740,108 @@ func ProcessRepositories(email string, directory string) (map[int]int, error) {
 	return commits, nil
 }
 
+// CountCommits converts the matched commits of each day into commit counts.
+// The returned map covers every day of the window, days without commits being zero.
+//
+// Parameters:
+//   - commits: A map of days to matched commits
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+//
+// Returns:
+//   - map[int]int: A map of days to commit counts
+func CountCommits(commits map[int][]CommitInfo, window GraphRange) map[int]int {
+	window = window.orDefault()
+
+	// Initialize the counts' map with zeros for all days, including today,
+	// so every cell of the graph is backed by an entry
+	counts := make(map[int]int, window.Days+1)
+	for i := window.Days; i >= 0; i-- {
+		counts[i] = 0
+	}
+
+	for day, dayCommits := range commits {
+		counts[day] += len(dayCommits)
+	}
+
+	return counts
+}
+
 // PrintCell prints a single cell in the contribution graph with the appropriate coloring
 // based on the number of commits and whether it represents today.
 //
 // Parameters:
+//   - w: The writer to print to
 //   - val: The number of commits for this cell
 //   - today: Whether this cell represents today
 //   - date: The date for this cell
-//   - showCommitCount: Whether to display the number of commits on each cell
-//   - showDaysOfMonth: Whether to display the days of the month on the graph calendar
-func PrintCell(val int, today bool, date time.Time, showCommitCount bool, showDaysOfMonth bool) {
-	// Light gray for no contributions
-	escape := "\033[0;37;48;5;248m"
-
-	// Set color based on commit count - from lighter to darker green
-	switch {
-	case val > 0 && val < 5:
-		escape = "\033[1;30;48;5;120m" // Light green for few commits
-	case val >= 5 && val < 10:
-		escape = "\033[1;30;48;5;34m" // Medium green for moderate commits
-	case val >= 10:
-		escape = "\033[1;30;48;5;22m" // Dark green for many commits
+//   - opts: The options controlling how the graph is rendered
+func PrintCell(w io.Writer, val int, today bool, date time.Time, opts RenderOptions) {
+	printCell(w, val, today, date, false, opts)
+}
+
+// printCell prints a cell like PrintCell, followed by the stripe separator with Stripe
+// when the next column starts a month.
+func printCell(w io.Writer, val int, today bool, date time.Time, monthEnd bool, opts RenderOptions) {
+	level := opts.level(val)
+	escape := opts.levelEscape(level)
+
+	// Color the change from the day before instead of the number of commits
+	if opts.Delta {
+		escape = opts.deltaEscape(val)
 	}
 
 	// Special color for today's cell
-	if today {
+	if today && !opts.NoColor {
 		escape = "\033[1;37;45m"
 	}
 
 	// Determine what to display in the cell
-	cellContent := "   " // Default empty cell
+	cellContent := fmt.Sprintf(" %s ", opts.glyph(level))
+	separator := "|"
+	if opts.Compact {
+		cellContent = opts.glyph(level)
+	}
+	if opts.Delta {
+		cellContent = fmt.Sprintf(" %s ", opts.deltaGlyph(val))
+		if opts.Compact {
+			cellContent = opts.deltaGlyph(val)
+		}
+	}
+	if opts.Mono {
+		cellContent = fmt.Sprintf(" %s ", opts.monoGlyph(val))
+		if opts.Compact {
+			cellContent = opts.monoGlyph(val)
+		}
+	}
 
-	// Show the commit count if requested
-	if showCommitCount && val > 0 {
-		if val < 10 {
-			cellContent = fmt.Sprintf(" %d ", val) // Single digit with padding
-		} else {
-			cellContent = fmt.Sprintf("%d ", val) // Double-digit with padding
+	// Draw solid blocks colored in the foreground instead of colored backgrounds
+	if opts.blocks() {
+		escape = opts.levelForeground(level)
+		if opts.Delta {
+			escape = opts.deltaForeground(val)
 		}
+		if today {
+			escape = "\033[1;35m"
+		}
+		cellContent = "███"
+		if opts.Compact {
+			cellContent = "█"
+		}
+		separator = " "
+	}
+
+	// Mark the month boundary so the eye can follow the columns
+	if monthEnd && opts.Stripe {
+		separator = opts.stripeSeparator()
+	}
+
+	// Show the commit count if requested, only above the threshold if there is one
+	if opts.annotated(val) {
+		cellContent = opts.countLabel(val)
 	}
 
 	// Show day of the month if requested
-	if showDaysOfMonth {
+	if opts.ShowDaysOfMonth {
 		day := date.Day()
 		if day < 10 {
 			cellContent = fmt.Sprintf(" %d ", day) // Single digit with padding
@@ -219,21 +850,26 @@ func PrintCell(val int, today bool, date time.Time, showCommitCount bool, showDa
 		}
 	}
 
-	// Print cell with a pipe separator
-	fmt.Printf("%s%s%s|", escape, cellContent, "\033[0m")
+	// Print cell with its separator
+	fmt.Fprintf(w, "%s%s%s%s", escape, cellContent, opts.reset(), separator)
 }
 
 // PrintCommitsStats displays a visual representation of commit statistics in a calendar-like grid.
 // It processes the commits' map, builds the columns, and prints the cells.
 //
 // Parameters:
+//   - w: The writer to print to
 //   - commits: A map of days to commit counts
-//   - showCommitCount: Whether to display the number of commits on each cell
-//   - showDaysOfMonth: Whether to display the days of the month on the graph calendar
-func PrintCommitsStats(commits map[int]int, showCommitCount bool, showDaysOfMonth bool) {
+//   - opts: The options controlling how the graph is rendered
+func PrintCommitsStats(w io.Writer, commits map[int]int, opts RenderOptions) {
+	// Relative levels need the highest daily count first
+	if opts.Relative && opts.MaxCount == 0 {
+		opts.MaxCount = MaxCount(commits)
+	}
+
 	keys := SortMapIntoSlice(commits)
-	cols := BuildCols(keys, commits)
-	PrintCells(cols, showCommitCount, showDaysOfMonth)
+	cols := buildCols(keys, commits, opts.today(), opts.AlignToToday, opts.window())
+	PrintCells(w, cols, opts)
 }
 
 // SortMapIntoSlice extracts the keys from a map and returns them as a sorted slice.
@@ -258,27 +894,43 @@ func SortMapIntoSlice(m map[int]int) []int {
 // Parameters:
 //   - keys: A sorted slice of day indices
 //   - commits: A map of days to commit counts
+//   - window: The window of the graph (if empty, DefaultGraphRange)
 //
 // Returns:
 //   - map[int]Column: A map of week numbers to columns of commit counts
-func BuildCols(keys []int, commits map[int]int) map[int]Column {
-	cols := make(map[int]Column)
+func BuildCols(keys []int, commits map[int]int, window GraphRange) map[int]Column {
+	return buildCols(keys, commits, GetBeginningOfDay(time.Now()), false, window.orDefault())
+}
 
-	// Get today's date
-	today := GetBeginningOfDay(time.Now())
+// graphStart returns the first day of the graph window and the start (Sunday) of its first week, week window.Weeks.
+// By default, the window starts window.Days before today, the days of its first week before it being left out.
+// When aligned to today, the first week is counted back from the current week instead, and drawn in full.
+// Either way the current week is week 0, the last column: a window a few days longer than its whole weeks
+// would push today out of the graph, so its oldest days are left out instead.
+func graphStart(today time.Time, alignToToday bool, window GraphRange) (time.Time, time.Time) {
+	startOfCurrentWeek := today.AddDate(0, 0, -int(today.Weekday()))
+	earliestWeek := startOfCurrentWeek.AddDate(0, 0, -window.Weeks*DaysInWeek)
+	if alignToToday {
+		return earliestWeek, earliestWeek
+	}
 
-	// Initialize a map to group commits by week and day
-	weekDayCommits := make(map[int]map[int]int)
+	// Calculate the start date for the contribution graph and the start of its week
+	startDate := today.AddDate(0, 0, -window.Days)
+	startOfFirstWeek := startDate.AddDate(0, 0, -int(startDate.Weekday()))
+	if startOfFirstWeek.Before(earliestWeek) {
+		return earliestWeek, earliestWeek
+	}
+	return startDate, startOfFirstWeek
+}
 
-	// Calculate the current weekday
-	_ = int(today.Weekday())
+// buildCols organizes commit data into columns relative to the given day, see BuildCols.
+func buildCols(keys []int, commits map[int]int, today time.Time, alignToToday bool, window GraphRange) map[int]Column {
+	cols := make(map[int]Column)
 
-	// Calculate the start date for the contribution graph (6 months ago)
-	startDate := today.AddDate(0, -6, 0)
+	// Initialize a map to group commits by week and day
+	weekDayCommits := make(map[int]map[int]int)
 
-	// Calculate the start of the week for the start date
-	daysToStartSunday := int(startDate.Weekday())
-	startOfFirstWeek := startDate.AddDate(0, 0, -daysToStartSunday)
+	startDate, startOfFirstWeek := graphStart(today, alignToToday, window)
 
 	for _, k := range keys {
 		// Calculate the actual date for this key (days ago)
@@ -296,7 +948,7 @@ func BuildCols(keys []int, commits map[int]int) map[int]Column {
 		weeksSinceStart := int(date.Sub(startOfFirstWeek).Hours() / (HoursInDay * DaysInWeek))
 
 		// The week number is the number of weeks from the start of the graph
-		week := WeeksInLastSixMonths - weeksSinceStart
+		week := window.Weeks - weeksSinceStart
 
 		// Initialize the week map if it doesn't exist
 		if _, ok := weekDayCommits[week]; !ok {
@@ -327,19 +979,19 @@ func BuildCols(keys []int, commits map[int]int) map[int]Column {
 //
 // Parameters:
 //   - cols: A map of week numbers to columns of commit counts
+//   - alignToToday: Whether the current week is the last column
+//   - weeks: The exact number of columns to display, see RenderOptions.Weeks (if zero, as many as needed)
+//   - window: The window of the graph
 //
 // Returns:
 //   - time.Time: The start of the first week in the graph
 //   - int: The week number that contains today
 //   - int: The maximum week number to display
-func calculateGraphParameters(cols map[int]Column) (time.Time, int, int) {
+func calculateGraphParameters(cols map[int]Column, today time.Time, alignToToday bool, weeks int, window GraphRange) (time.Time, int, int) {
 	// Calculate which week today is in
-	today := GetBeginningOfDay(time.Now())
-	startDate := today.AddDate(0, -6, 0)
-	daysToStartSunday := int(startDate.Weekday())
-	startOfFirstWeek := startDate.AddDate(0, 0, -daysToStartSunday)
+	_, startOfFirstWeek := graphStart(today, alignToToday, window)
 	weeksSinceStart := int(today.Sub(startOfFirstWeek).Hours() / (HoursInDay * DaysInWeek))
-	todayWeek := WeeksInLastSixMonths - weeksSinceStart
+	todayWeek := window.Weeks - weeksSinceStart
 
 	// Find the maximum week number in the col map
 	maxWeek := 0
@@ -349,9 +1001,14 @@ func calculateGraphParameters(cols map[int]Column) (time.Time, int, int) {
 		}
 	}
 
-	// Ensure we display at least WeeksInLastSixMonths+1 columns
-	if maxWeek < WeeksInLastSixMonths {
-		maxWeek = WeeksInLastSixMonths
+	// Ensure we display at least the columns of the window
+	if maxWeek < window.Weeks {
+		maxWeek = window.Weeks
+	}
+
+	// A fixed number of columns wins over the data, the older weeks are left out or padded
+	if weeks > 0 {
+		maxWeek = weeks - 1
 	}
 
 	return startOfFirstWeek, todayWeek, maxWeek
@@ -360,16 +1017,17 @@ func calculateGraphParameters(cols map[int]Column) (time.Time, int, int) {
 // printCellForPosition prints the appropriate cell for a given position in the contribution graph.
 //
 // Parameters:
+//   - w: The writer to print to
 //   - cols: A map of week numbers to columns of commit counts
 //   - weekNum: The week number for this cell
 //   - dayNum: The day number for this cell
 //   - todayWeek: The week number that contains today
 //   - cellDate: The date for this cell
-//   - showCommitCount: Whether to display the number of commits on each cell
-//   - showDaysOfMonth: Whether to display the days of the month on the graph calendar
-func printCellForPosition(cols map[int]Column, weekNum int, dayNum int, todayWeek int, cellDate time.Time, showCommitCount bool, showDaysOfMonth bool) {
+//   - monthEnd: Whether the next column starts a month
+//   - opts: The options controlling how the graph is rendered
+func printCellForPosition(w io.Writer, cols map[int]Column, weekNum int, dayNum int, todayWeek int, cellDate time.Time, monthEnd bool, opts RenderOptions) {
 	// Check if this cell represents today
-	isToday := weekNum == todayWeek && dayNum == CalculateWeekdayOffset()
+	isToday := weekNum == todayWeek && dayNum == int(opts.today().Weekday())
 
 	// Get a commit count for this cell if available
 	commitCount := 0
@@ -378,112 +1036,271 @@ func printCellForPosition(cols map[int]Column, weekNum int, dayNum int, todayWee
 	}
 
 	// Print the cell with appropriate styling
-	PrintCell(commitCount, isToday, cellDate, showCommitCount, showDaysOfMonth)
+	printCell(w, commitCount, isToday, cellDate, monthEnd, opts)
 }
 
 // printWeekRow prints a single row (day of the week) in the contribution graph.
 //
 // Parameters:
+//   - w: The writer to print to
 //   - cols: A map of week numbers to columns of commit counts
 //   - dayNum: The day number (0-6) to print
 //   - startOfFirstWeek: The start date of the first week in the graph
 //   - todayWeek: The week number that contains today
 //   - maxWeek: The maximum week number to display
-//   - showCommitCount: Whether to display the number of commits on each cell
-//   - showDaysOfMonth: Whether to display the days of the month on the graph calendar
-func printWeekRow(cols map[int]Column, dayNum int, startOfFirstWeek time.Time, todayWeek int, maxWeek int, showCommitCount bool, showDaysOfMonth bool) {
+//   - opts: The options controlling how the graph is rendered
+func printWeekRow(w io.Writer, cols map[int]Column, dayNum int, startOfFirstWeek time.Time, todayWeek int, maxWeek int, opts RenderOptions) {
+	months := monthStarts(startOfFirstWeek, maxWeek, opts.window())
+
 	// Iterate through weeks (columns)
 	for weekNum := maxWeek + 1; weekNum >= 0; weekNum-- {
 		// Print day labels in the first column
 		if weekNum == maxWeek+1 {
-			PrintDayCol(dayNum)
+			if !opts.GraphOnly && opts.CollapseEmptyRows {
+				printShortDayCol(w, dayNum)
+			} else if !opts.GraphOnly {
+				PrintDayCol(w, dayNum)
+			}
+			continue
+		}
+
+		// Leave out the days after today, so the current week ends the row
+		if opts.AlignToToday && weekNum == todayWeek && dayNum > int(opts.today().Weekday()) {
 			continue
 		}
 
 		// Calculate the date for this cell
-		weekOffset := WeeksInLastSixMonths - weekNum
+		weekOffset := opts.window().Weeks - weekNum
 		cellDate := startOfFirstWeek.AddDate(0, 0, weekOffset*7+dayNum)
 
 		// Print the appropriate cell for this position
-		printCellForPosition(cols, weekNum, dayNum, todayWeek, cellDate, showCommitCount, showDaysOfMonth)
+		_, monthEnd := months[weekNum-1]
+		printCellForPosition(w, cols, weekNum, dayNum, todayWeek, cellDate, monthEnd, opts)
 	}
-	fmt.Printf("\n")
+	fmt.Fprintf(w, "\n")
 }
 
 // PrintCells renders the contribution graph by printing all cells in a grid format.
 // It first prints the month labels, then iterates through each day of the week and each week,
-// printing the appropriate cell for each position.
+// printing the appropriate cell for each position. With GraphOnly, the labels are left out.
 //
 // Parameters:
+//   - w: The writer to print to
 //   - cols: A map of week numbers to columns of commit counts
-//   - showCommitCount: Whether to display the number of commits on each cell
-//   - showDaysOfMonth: Whether to display the days of the month on the graph calendar
-func PrintCells(cols map[int]Column, showCommitCount bool, showDaysOfMonth bool) {
-	PrintMonths()
+//   - opts: The options controlling how the graph is rendered
+func PrintCells(w io.Writer, cols map[int]Column, opts RenderOptions) {
+	if !opts.GraphOnly {
+		PrintMonths(w, opts)
+	}
 
 	// Calculate graph parameters
-	startOfFirstWeek, todayWeek, maxWeek := calculateGraphParameters(cols)
+	startOfFirstWeek, todayWeek, maxWeek := calculateGraphParameters(cols, opts.today(), opts.AlignToToday, opts.Weeks, opts.window())
+
+	if opts.WeekNumbers && !opts.GraphOnly {
+		PrintWeekNumbers(w, startOfFirstWeek, maxWeek, opts.window())
+	}
+
+	// Find the days of the week to leave out before printing any row
+	var empty [DaysInWeek]bool
+	if opts.CollapseEmptyRows {
+		empty = emptyWeekdays(cols)
+	}
 
 	// Iterate through days of the week (rows)
 	for dayNum := 0; dayNum <= 6; dayNum++ {
-		printWeekRow(cols, dayNum, startOfFirstWeek, todayWeek, maxWeek, showCommitCount, showDaysOfMonth)
+		if empty[dayNum] {
+			continue
+		}
+		printWeekRow(w, cols, dayNum, startOfFirstWeek, todayWeek, maxWeek, opts)
 	}
 }
 
-// PrintMonths prints the month labels at the top of the contribution graph.
-// It places month names on columns with the first day of that month.
-func PrintMonths() {
-	// Started from 6 months ago
-	startDate := GetBeginningOfDay(time.Now()).AddDate(0, -6, 0)
+// emptyWeekdays reports the days of the week (rows) without commits in any week of the graph.
+// When there are no commits at all, no row is reported so the graph doesn't vanish.
+func emptyWeekdays(cols map[int]Column) [DaysInWeek]bool {
+	var empty [DaysInWeek]bool
+	for i := range empty {
+		empty[i] = true
+	}
 
-	// Calculate the start of the week for the start date
-	daysToSunday := int(startDate.Weekday())
-	startOfWeek := startDate.AddDate(0, 0, -daysToSunday)
+	active := false
+	for _, col := range cols {
+		for dayNum, count := range col {
+			if count != 0 && dayNum < DaysInWeek {
+				empty[dayNum] = false
+				active = true
+			}
+		}
+	}
 
-	// Print initial spacing
-	fmt.Printf("         ")
+	if !active {
+		return [DaysInWeek]bool{}
+	}
+	return empty
+}
+
+// PrintWeeklyStats renders the contribution graph as a single row of weeks,
+// each cell showing the total number of commits of its week.
+// The columns are the same as the daily graph's, so the month labels line up.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - commits: A map of days to commit counts
+//   - opts: The options controlling how the graph is rendered
+func PrintWeeklyStats(w io.Writer, commits map[int]int, opts RenderOptions) {
+	opts.Weekly = true
 
+	cols := buildCols(SortMapIntoSlice(commits), commits, opts.today(), opts.AlignToToday, opts.window())
+	totals := WeeklyTotals(cols)
+
+	// Relative levels need the highest weekly total first
+	if opts.Relative && opts.MaxCount == 0 {
+		opts.MaxCount = MaxCount(totals)
+	}
+
+	if !opts.GraphOnly {
+		PrintMonths(w, opts)
+	}
+
+	startOfFirstWeek, todayWeek, maxWeek := calculateGraphParameters(cols, opts.today(), opts.AlignToToday, opts.Weeks, opts.window())
+
+	if opts.WeekNumbers && !opts.GraphOnly {
+		PrintWeekNumbers(w, startOfFirstWeek, maxWeek, opts.window())
+	}
+
+	if !opts.GraphOnly {
+		fmt.Fprintf(w, "  Wk ")
+	}
+	months := monthStarts(startOfFirstWeek, maxWeek, opts.window())
+	for weekNum := maxWeek; weekNum >= 0; weekNum-- {
+		startOfWeek := startOfFirstWeek.AddDate(0, 0, (opts.window().Weeks-weekNum)*DaysInWeek)
+		_, monthEnd := months[weekNum-1]
+		printCell(w, totals[weekNum], weekNum == todayWeek, startOfWeek, monthEnd, opts)
+	}
+	fmt.Fprintf(w, "\n")
+}
+
+// WeeklyCounts returns the total number of commits of each week of the graph.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//   - opts: The options controlling the columns, see RenderOptions.AlignToToday, RenderOptions.Until and RenderOptions.Range
+//
+// Returns:
+//   - map[int]int: A map of week numbers to the total number of commits of the week
+func WeeklyCounts(commits map[int]int, opts RenderOptions) map[int]int {
+	return WeeklyTotals(buildCols(SortMapIntoSlice(commits), commits, opts.today(), opts.AlignToToday, opts.window()))
+}
+
+// WeeklyTotals sums the days of each column of the graph.
+//
+// Parameters:
+//   - cols: A map of week numbers to columns of commit counts
+//
+// Returns:
+//   - map[int]int: A map of week numbers to the total number of commits of the week
+func WeeklyTotals(cols map[int]Column) map[int]int {
+	totals := make(map[int]int, len(cols))
+	for week, col := range cols {
+		for _, count := range col {
+			totals[week] += count
+		}
+	}
+	return totals
+}
+
+// PrintMonths prints the month labels at the top of the contribution graph.
+// It places month names on columns with the first day of that month, over as many columns
+// as the window of the graph has, see RenderOptions.Range.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - opts: The options controlling how the graph is rendered
+func PrintMonths(w io.Writer, opts RenderOptions) {
+	// Started from the first week of the graph
+	_, startOfWeek := graphStart(opts.today(), opts.AlignToToday, opts.window())
+	maxWeek := opts.weekColumns() - 1
+	monthLabels := monthStarts(startOfWeek, maxWeek, opts.window())
+
+	// Place the month labels above the column of their week, they may overflow
+	// the next columns with compact cells
+	line := []byte(strings.Repeat(" ", opts.GridWidth()))
+	for weekNum, label := range monthLabels {
+		copy(line[dayColWidth+opts.cellWidth()*(maxWeek-weekNum):], label)
+	}
+
+	fmt.Fprintf(w, "%s\n", strings.TrimRight(string(line), " "))
+}
+
+// monthStarts returns the columns (week numbers) containing the first day of a month,
+// with the label of that month. The oldest column is left out, its label would overlap the day labels.
+//
+// Parameters:
+//   - startOfWeek: The start (Sunday) of the first week of the window, week window.Weeks
+//   - maxWeek: The week number of the oldest column, window.Weeks unless RenderOptions.Weeks is set
+//   - window: The window of the graph
+//
+// Returns:
+//   - map[int]string: The three-letter month label of each column starting a month
+func monthStarts(startOfWeek time.Time, maxWeek int, window GraphRange) map[int]string {
 	// Map to store week numbers that contain the first day of a month
 	monthLabels := make(map[int]string)
 
-	// Iterate through each day in the 6-month period to find the first days of months
-	for weekNum := WeeksInLastSixMonths; weekNum >= 0; weekNum-- {
+	// Iterate through each day of the displayed weeks to find the first days of months
+	for weekNum := maxWeek; weekNum >= 0; weekNum-- {
 		for dayInWeek := 0; dayInWeek < 7; dayInWeek++ {
 			// Calculate the date for this cell
-			cellDate := startOfWeek.AddDate(0, 0, (WeeksInLastSixMonths-weekNum)*7+dayInWeek)
+			cellDate := startOfWeek.AddDate(0, 0, (window.Weeks-weekNum)*7+dayInWeek)
 
 			// If this is the first day of a month, store the month label for the previous week
 			if cellDate.Day() == 1 {
-				// Only store the label if we're not at the oldest week (to avoid out of bounds)
-				if weekNum < WeeksInLastSixMonths {
-					monthLabels[weekNum+1] = cellDate.Month().String()[:3]
+				// Only store the label if we're not at the oldest week, where it would overlap the day labels
+				if weekNum < maxWeek {
+					monthLabels[weekNum] = cellDate.Month().String()[:3]
 				}
 				break // Found first day of the month in this week, move to next week
 			}
 		}
 	}
 
-	// Print month labels
-	for weekNum := WeeksInLastSixMonths; weekNum >= 0; weekNum-- {
-		if label, ok := monthLabels[weekNum]; ok {
-			fmt.Printf("%s ", label)
-		} else {
-			fmt.Printf("    ")
-		}
-	}
+	return monthLabels
+}
 
-	// Add an extra column for the current week
-	fmt.Printf("    ")
+// isoWeekLabel returns the 3-character label of the ISO week of a column.
+// Columns start on Sunday and ISO weeks on Monday, so the week of the column's Monday is used.
+func isoWeekLabel(startOfWeek time.Time) string {
+	_, week := startOfWeek.AddDate(0, 0, 1).ISOWeek()
+	if week < 10 {
+		return fmt.Sprintf(" %d ", week) // Single digit with padding
+	}
+	return fmt.Sprintf("%d ", week) // Double-digit with padding
+}
 
-	fmt.Printf("\n")
+// PrintWeekNumbers prints the ISO week number of each column of the contribution graph,
+// aligned with the cells.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - startOfFirstWeek: The start (Sunday) of the first week of the window
+//   - maxWeek: The week number of the oldest column
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+func PrintWeekNumbers(w io.Writer, startOfFirstWeek time.Time, maxWeek int, window GraphRange) {
+	window = window.orDefault()
+	fmt.Fprintf(w, " ISO ")
+	for weekNum := maxWeek; weekNum >= 0; weekNum-- {
+		startOfWeek := startOfFirstWeek.AddDate(0, 0, (window.Weeks-weekNum)*DaysInWeek)
+		fmt.Fprintf(w, "%s ", isoWeekLabel(startOfWeek))
+	}
+	fmt.Fprintf(w, "\n")
 }
 
 // PrintDayCol prints the day labels on the left side of the contribution graph.
 // It displays the first letter of each day of the week.
 //
 // Parameters:
+//   - w: The writer to print to
 //   - day: The day index (0-6) to print a label for
-func PrintDayCol(day int) {
+func PrintDayCol(w io.Writer, day int) {
 	out := "     " // Default empty label
 
 	switch day {
@@ -503,5 +1320,18 @@ func PrintDayCol(day int) {
 		out = "  S  "
 	}
 
-	fmt.Printf("%s", out)
+	fmt.Fprintf(w, "%s", out)
+}
+
+// shortDayNames holds the two-letter names of the days of the week, starting on Sunday.
+var shortDayNames = [DaysInWeek]string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"}
+
+// printShortDayCol prints the two-letter label of a day of the week, with the width of PrintDayCol.
+// With rows left out, single letters would be ambiguous (e.g. T for Tuesday and Thursday).
+func printShortDayCol(w io.Writer, day int) {
+	if day < 0 || day >= DaysInWeek {
+		fmt.Fprintf(w, "     ")
+		return
+	}
+	fmt.Fprintf(w, "  %s ", shortDayNames[day])
 }
@@ -1,9 +1,21 @@
 package stats
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
+
+	"github.com/acheddir/git-contrib/pkg/testutil"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 )
 
 // TestGetBeginningOfDay tests the GetBeginningOfDay function
@@ -40,35 +52,35 @@ func TestCountDaysSinceDate(t *testing.T) {
 
 	// Test case 1: Today
 	today := GetBeginningOfDay(now)
-	result := CountDaysSinceDate(today)
+	result := CountDaysSinceDate(today, DefaultGraphRange)
 	if result != 0 {
 		t.Errorf("Expected 0 days for today, got %d", result)
 	}
 
 	// Test case 2: Yesterday
 	yesterday := today.Add(-24 * time.Hour)
-	result = CountDaysSinceDate(yesterday)
+	result = CountDaysSinceDate(yesterday, DefaultGraphRange)
 	if result != 1 {
 		t.Errorf("Expected 1 day for yesterday, got %d", result)
 	}
 
 	// Test case 3: 10 days ago
 	tenDaysAgo := today.Add(-10 * 24 * time.Hour)
-	result = CountDaysSinceDate(tenDaysAgo)
+	result = CountDaysSinceDate(tenDaysAgo, DefaultGraphRange)
 	if result != 10 {
 		t.Errorf("Expected 10 days for 10 days ago, got %d", result)
 	}
 
 	// Test case 4: Future date
 	tomorrow := today.Add(24 * time.Hour)
-	result = CountDaysSinceDate(tomorrow)
+	result = CountDaysSinceDate(tomorrow, DefaultGraphRange)
 	if result != -1 {
 		t.Errorf("Expected -1 day for tomorrow, got %d", result)
 	}
 
 	// Test case 5: Out of range (more than DaysInLastSixMonths)
 	outOfRange := today.Add(-time.Duration(DaysInLastSixMonths+1) * 24 * time.Hour)
-	result = CountDaysSinceDate(outOfRange)
+	result = CountDaysSinceDate(outOfRange, DefaultGraphRange)
 	if result != OutOfRange {
 		t.Errorf("Expected OutOfRange (%d) for date beyond six months, got %d", OutOfRange, result)
 	}
@@ -108,6 +120,549 @@ func TestCalculateWeekdayOffset(t *testing.T) {
 	}
 }
 
+// TestFilterMatches tests the Filter.Matches method
+func TestFilterMatches(t *testing.T) {
+	// Test case 1: Empty filter matches everyone
+	filter := Filter{}
+	if !filter.Matches("anyone@example.com") {
+		t.Errorf("Expected empty filter to match any email")
+	}
+
+	// Test case 2: Filter with several emails
+	filter = Filter{Emails: []string{"alice@example.com", "bob@example.com"}}
+	if !filter.Matches("bob@example.com") {
+		t.Errorf("Expected filter to match bob@example.com")
+	}
+	if filter.Matches("carol@example.com") {
+		t.Errorf("Expected filter not to match carol@example.com")
+	}
+}
+
+// TestFilterMatchesMixedCase tests that Filter.Matches ignores the case of emails
+func TestFilterMatchesMixedCase(t *testing.T) {
+	filter := Filter{Emails: []string{"me@example.com"}}
+	if !filter.Matches("Me@Example.COM") {
+		t.Errorf("Expected Me@Example.COM to match me@example.com")
+	}
+
+	filter = Filter{Emails: []string{"Me@Example.com"}}
+	if !filter.Matches("me@example.com") {
+		t.Errorf("Expected me@example.com to match Me@Example.com")
+	}
+}
+
+// TestFilterIdentities tests that the aliases of an author are filtered as one
+func TestFilterIdentities(t *testing.T) {
+	identities := map[string]string{
+		"me@work.com": "me@home.com",
+		"me@home.com": "me@home.com",
+	}
+
+	filter := Filter{Emails: []string{"me@home.com"}, Identities: identities}
+	if !filter.Matches("Me@Work.com") {
+		t.Errorf("Expected the alias Me@Work.com to match me@home.com")
+	}
+	if filter.Matches("bob@work.com") {
+		t.Errorf("Expected bob@work.com not to match me@home.com")
+	}
+
+	// Excluding an alias excludes the whole author
+	filter = Filter{ExcludeEmails: []string{"me@work.com"}, Identities: identities}
+	if filter.Matches("me@home.com") {
+		t.Errorf("Expected me@home.com to be excluded through its alias")
+	}
+
+	if got := filter.Identity("ME@WORK.COM"); got != "me@home.com" {
+		t.Errorf("Expected the canonical email me@home.com, got %q", got)
+	}
+	if got := filter.Identity("Bob@work.com"); got != "Bob@work.com" {
+		t.Errorf("Expected an email without alias to be kept, got %q", got)
+	}
+}
+
+// TestNormalizeEmail tests that invisible differences don't split an author
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		email    string
+		expected string
+	}{
+		{"me@example.com ", "me@example.com"},
+		{"me\u200b@example.com", "me@example.com"},
+		{"\ufeffMe@Example.com\u200d ", "me@example.com"},
+		// A decomposed accent (e + combining acute) is the same as the composed one
+		{"jose\u0301@example.com", "jos\u00e9@example.com"},
+	}
+
+	for _, test := range tests {
+		if result := NormalizeEmail(test.email); result != test.expected {
+			t.Errorf("NormalizeEmail(%q) = %q, expected %q", test.email, result, test.expected)
+		}
+	}
+
+	// The commits of a copy-pasted email are grouped and filtered with the others
+	commits := map[int][]CommitInfo{0: {{Email: "me@example.com"}, {Email: "me@example.com\u200b "}}}
+	if authors := CountByAuthor(commits); len(authors) != 1 || authors[0].Count != 2 {
+		t.Errorf("Expected a single author with 2 commits, got %v", authors)
+	}
+	if !(Filter{Emails: []string{"me@example.com"}}).Matches("me@example.com\u200b ") {
+		t.Errorf("Expected the email with a zero-width character to match")
+	}
+}
+
+// TestSanitizeEmail tests that invalid UTF-8 bytes and control characters are replaced
+func TestSanitizeEmail(t *testing.T) {
+	tests := []struct {
+		email    string
+		expected string
+	}{
+		{"me@example.com", "me@example.com"},
+		{"jos\u00e9@example.com", "jos\u00e9@example.com"},
+		{"jos\xe9@example.com", "jos\ufffd@example.com"},
+		{"me\x1b[31m@example.com", "me\ufffd[31m@example.com"},
+	}
+
+	for _, test := range tests {
+		if result := SanitizeEmail(test.email); result != test.expected {
+			t.Errorf("SanitizeEmail(%q) = %q, expected %q", test.email, result, test.expected)
+		}
+	}
+}
+
+// TestGetCommitsFromRepoInvalidEmail tests that a commit with invalid UTF-8 author bytes is read,
+// filtered and reported with a sanitized email
+func TestGetCommitsFromRepoInvalidEmail(t *testing.T) {
+	// A Latin-1 encoded accent, as written by some ancient tools
+	dir := createTestRepo(t,
+		testCommit{"jos\xe9@example.com", "Old tool commit", time.Now()},
+		testCommit{"other@example.com", "Other commit", time.Now()},
+	)
+
+	commits, err := GetCommitsFromRepo(Filter{}, dir, make(map[int][]CommitInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	authors := CountByAuthor(commits)
+	if len(authors) != 2 || authors[0].Email != "jos\ufffd@example.com" {
+		t.Errorf("Expected the sanitized email among the authors, got %v", authors)
+	}
+	for _, c := range commits[0] {
+		if !utf8.ValidString(c.Email) {
+			t.Errorf("Expected a valid UTF-8 email, got %q", c.Email)
+		}
+	}
+
+	// The email still matches filters, and the trace stays valid UTF-8
+	var trace bytes.Buffer
+	filter := Filter{Emails: []string{"jos\xe9@example.com"}, Trace: &trace}
+	if commits, err = GetCommitsFromRepo(filter, dir, make(map[int][]CommitInfo)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(commits[0]) != 1 {
+		t.Errorf("Expected the commit to match, got %v", commits)
+	}
+	filter.Emails = []string{"other@example.com"}
+	if _, err = GetCommitsFromRepo(filter, dir, make(map[int][]CommitInfo)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !utf8.ValidString(trace.String()) || !strings.Contains(trace.String(), "<jos\ufffd@example.com>") {
+		t.Errorf("Expected the sanitized email in the trace, got %q", trace.String())
+	}
+}
+
+// TestFilterExcludeEmails tests that Filter.Matches skips excluded emails
+func TestFilterExcludeEmails(t *testing.T) {
+	// Test case 1: Exclusions without a positive filter
+	filter := Filter{ExcludeEmails: []string{" Robot@Example.com "}}
+	if filter.Matches("robot@example.com") {
+		t.Errorf("Expected robot@example.com to be excluded")
+	}
+	if !filter.Matches("alice@example.com") {
+		t.Errorf("Expected alice@example.com to be included")
+	}
+
+	// Test case 2: Exclusions combined with a positive filter
+	filter = Filter{
+		Emails:        []string{"alice@example.com", "bob@example.com"},
+		ExcludeEmails: []string{"bob@example.com"},
+	}
+	if !filter.Matches("alice@example.com") {
+		t.Errorf("Expected alice@example.com to be included")
+	}
+	if filter.Matches("bob@example.com") {
+		t.Errorf("Expected bob@example.com to be excluded")
+	}
+}
+
+// TestFilterExcludeBots tests that Filter.Matches skips bots when requested
+func TestFilterExcludeBots(t *testing.T) {
+	bot := "49699333+dependabot[bot]@users.noreply.github.com"
+
+	// Test case 1: Bots are kept by default
+	filter := Filter{}
+	if !filter.Matches(bot) {
+		t.Errorf("Expected bots to be included by default")
+	}
+
+	// Test case 2: Default bot patterns
+	filter = Filter{ExcludeBots: true}
+	if filter.Matches(bot) {
+		t.Errorf("Expected %s to be excluded", bot)
+	}
+	if !filter.Matches("12345+someone@users.noreply.github.com") {
+		t.Errorf("Expected human noreply emails to be included")
+	}
+
+	// Test case 3: Configured bot patterns extend the defaults
+	filter = Filter{ExcludeBots: true, BotPatterns: []string{"ci-*@example.com"}}
+	if filter.Matches("ci-runner@example.com") || filter.Matches(bot) {
+		t.Errorf("Expected configured and default bots to be excluded")
+	}
+}
+
+// TestMatchPattern tests the MatchPattern function
+func TestMatchPattern(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{"me@example.com", "me@example.com", true},
+		{"me@example.com", "Me@Example.com", true},
+		{"me@example.com", "me@example.org", false},
+		{"*[bot]*", "renovate[bot]@example.com", true},
+		{"*[bot]*", "bot@example.com", false},
+		{"*@example.com", "me@example.com", true},
+		{"ci-*", "ci-runner", true},
+		{"a*b*c", "abc", true},
+		{"a*b*c", "acb", false},
+		{"ab*ba", "aba", false},
+	}
+
+	for _, tc := range testCases {
+		result := MatchPattern(tc.pattern, tc.value)
+		if result != tc.expected {
+			t.Errorf("MatchPattern(%q, %q): expected %v, got %v", tc.pattern, tc.value, tc.expected, result)
+		}
+	}
+}
+
+// TestParseCoAuthors tests the ParseCoAuthors function
+func TestParseCoAuthors(t *testing.T) {
+	message := "Pair on the parser\n\nBody mentioning <not@trailer.com>\n\n" +
+		"Co-authored-by: Alice <alice@example.com>\n" +
+		"co-authored-by: Bob Smith <Bob@Example.com>  \n" +
+		"Signed-off-by: Carol <carol@example.com>\n" +
+		"Co-authored-by: Nobody\n"
+
+	expected := []string{"alice@example.com", "Bob@Example.com"}
+	if result := ParseCoAuthors(message); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	if result := ParseCoAuthors("No trailers"); len(result) != 0 {
+		t.Errorf("Expected no co-authors, got %v", result)
+	}
+}
+
+// TestGetCommitsFromRepoCoAuthors tests that co-authored commits are counted with CoAuthors
+func TestGetCommitsFromRepoCoAuthors(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t,
+		testCommit{"alice@example.com", "Solo commit", now},
+		testCommit{"bob@example.com", "Paired commit\n\nCo-authored-by: Alice <alice@example.com>\nCo-authored-by: Carol <carol@example.com>", now},
+		testCommit{"bob@example.com", "Bob's commit\n\nCo-authored-by: Carol <carol@example.com>", now},
+	)
+
+	testCases := []struct {
+		filter   Filter
+		expected int
+	}{
+		{Filter{Emails: []string{"alice@example.com"}}, 1},
+		{Filter{Emails: []string{"alice@example.com"}, CoAuthors: true}, 2},
+		{Filter{Emails: []string{"carol@example.com"}, CoAuthors: true}, 2},
+		// Commits are counted once, even when matched by several people
+		{Filter{Emails: []string{"alice@example.com", "bob@example.com", "carol@example.com"}, CoAuthors: true}, 3},
+	}
+
+	for _, tc := range testCases {
+		commits, err := GetCommitsFromRepo(tc.filter, dir, make(map[int][]CommitInfo))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(commits[0]) != tc.expected {
+			t.Errorf("Filter %+v: expected %d commits, got %d", tc.filter, tc.expected, len(commits[0]))
+		}
+	}
+}
+
+// TestGetCommitsFromRepoExcludeWeekends tests that weekend commits are skipped by the weekday of their author date
+func TestGetCommitsFromRepoExcludeWeekends(t *testing.T) {
+	// A Saturday at least a week ago, early in the morning in Tokyo (still Friday in UTC)
+	now := time.Now()
+	tokyo := time.FixedZone("JST", 9*60*60)
+	saturday := now.AddDate(0, 0, -(int(now.Weekday())+1)%7-DaysInWeek)
+	saturday = time.Date(saturday.Year(), saturday.Month(), saturday.Day(), 1, 0, 0, 0, tokyo)
+	dir := createTestRepo(t,
+		testCommit{"me@example.com", "Saturday commit", saturday},
+		testCommit{"me@example.com", "Sunday commit", saturday.AddDate(0, 0, 1)},
+		testCommit{"me@example.com", "Monday commit", saturday.AddDate(0, 0, 2)},
+	)
+
+	var trace bytes.Buffer
+	filter := Filter{ExcludeWeekends: true, Trace: &trace}
+	commits, err := GetCommitsFromRepo(filter, dir, make(map[int][]CommitInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var subjects []string
+	for _, day := range commits {
+		for _, commit := range day {
+			subjects = append(subjects, commit.Subject)
+		}
+	}
+	if !reflect.DeepEqual(subjects, []string{"Monday commit"}) {
+		t.Errorf("Expected only the Monday commit, got %v", subjects)
+	}
+	if strings.Count(trace.String(), ": "+SkipWeekend+"\n") != 2 {
+		t.Errorf("Expected 2 commits skipped for the weekend, got %q", trace.String())
+	}
+
+	// Weekends are counted without the option
+	filter = Filter{}
+	if commits, err = GetCommitsFromRepo(filter, dir, make(map[int][]CommitInfo)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Errorf("Expected commits on 3 days, got %v", commits)
+	}
+}
+
+// TestGetCommitsFromRepoTrace tests that skipped commits are reported with their reason
+func TestGetCommitsFromRepoTrace(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t,
+		testCommit{"me@example.com", "Old commit", now.AddDate(-1, 0, 0)},
+		testCommit{"renovate[bot]@example.com", "Bump deps", now},
+		testCommit{"excluded@example.com", "Excluded commit", now},
+		testCommit{"other@example.com", "Other commit", now},
+		testCommit{"me@example.com", "My commit", now},
+	)
+
+	var trace bytes.Buffer
+	filter := Filter{
+		Emails:        []string{"me@example.com", "renovate[bot]@example.com", "excluded@example.com"},
+		ExcludeEmails: []string{"excluded@example.com"},
+		ExcludeBots:   true,
+		Trace:         &trace,
+	}
+	commits, err := GetCommitsFromRepo(filter, dir, make(map[int][]CommitInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(commits[0]) != 1 {
+		t.Errorf("Expected 1 commit, got %d", len(commits[0]))
+	}
+
+	lines := strings.Split(strings.TrimSpace(trace.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 skipped commits, got %q", trace.String())
+	}
+	for _, expected := range []string{SkipEmailMismatch, SkipExcluded, SkipBot, SkipOutOfRange} {
+		if !strings.Contains(trace.String(), ": "+expected+"\n") {
+			t.Errorf("Expected a commit skipped for %q, got %q", expected, trace.String())
+		}
+	}
+
+	// Nothing is reported without Trace
+	filter.Trace = nil
+	if _, err := GetCommitsFromRepo(filter, dir, make(map[int][]CommitInfo)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+// TestGetCommitsFromRevision tests walking the history from a given revision
+func TestGetCommitsFromRevision(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t,
+		testCommit{"me@example.com", "First", now},
+		testCommit{"me@example.com", "Second", now},
+		testCommit{"me@example.com", "Third", now},
+	)
+
+	testCases := []struct {
+		revision string
+		expected int
+	}{
+		{"", 3},
+		{"HEAD", 3},
+		{"HEAD~2", 1},
+		{"master~1", 2},
+	}
+	for _, tc := range testCases {
+		commits, err := GetCommitsFromRevision(Filter{}, dir, tc.revision, make(map[int][]CommitInfo))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.revision, err)
+		}
+		if len(commits[0]) != tc.expected {
+			t.Errorf("%q: expected %d commits, got %d", tc.revision, tc.expected, len(commits[0]))
+		}
+	}
+
+	if _, err := GetCommitsFromRevision(Filter{}, dir, "origin/develop", make(map[int][]CommitInfo)); err == nil {
+		t.Errorf("Expected an error for an unknown revision")
+	}
+}
+
+// TestGetCommitsFromAllBranches tests that every branch is walked, counting shared commits once
+func TestGetCommitsFromAllBranches(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t,
+		testCommit{"me@example.com", "First", now},
+		testCommit{"me@example.com", "Second", now},
+	)
+
+	// Commit on a feature branch, then go back to master
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	feature := plumbing.NewBranchReferenceName("feature")
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: feature, Create: true}); err != nil {
+		t.Fatalf("Failed to create the feature branch: %v", err)
+	}
+	if _, err := worktree.Commit("Feature", &git.CommitOptions{
+		Author:            &object.Signature{Name: "Me", Email: "me@example.com", When: now},
+		AllowEmptyCommits: true,
+	}); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if err := worktree.Checkout(&git.CheckoutOptions{Branch: plumbing.Master}); err != nil {
+		t.Fatalf("Failed to check out master: %v", err)
+	}
+
+	commits, err := GetCommitsFromRepo(Filter{}, dir, make(map[int][]CommitInfo))
+	if err != nil || len(commits[0]) != 2 {
+		t.Fatalf("Expected 2 commits from HEAD, got %d (%v)", len(commits[0]), err)
+	}
+
+	commits, err = GetCommitsFromAllBranches(Filter{}, dir, make(map[int][]CommitInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(commits[0]) != 3 {
+		t.Errorf("Expected 3 commits from all the branches, got %d", len(commits[0]))
+	}
+}
+
+// TestCountCommits tests the CountCommits function
+func TestCountCommits(t *testing.T) {
+	commits := map[int][]CommitInfo{
+		0: {{Hash: "a"}},
+		3: {{Hash: "b"}, {Hash: "c"}},
+	}
+
+	result := CountCommits(commits, DefaultGraphRange)
+	if result[0] != 1 || result[3] != 2 {
+		t.Errorf("Expected counts of 1 and 2, got %d and %d", result[0], result[3])
+	}
+
+	// Every day of the window is present, from today to the oldest day
+	for _, day := range []int{1, DaysInLastSixMonths} {
+		if count, ok := result[day]; !ok || count != 0 {
+			t.Errorf("Expected day %d to be zero, got %d (present: %v)", day, count, ok)
+		}
+	}
+	if len(result) != DaysInLastSixMonths+1 {
+		t.Errorf("Expected %d days, got %d", DaysInLastSixMonths+1, len(result))
+	}
+}
+
+// TestCountCommitsToday tests that today without commits still produces a cell
+func TestCountCommitsToday(t *testing.T) {
+	counts := CountCommits(map[int][]CommitInfo{}, DefaultGraphRange)
+	if count, ok := counts[0]; !ok || count != 0 {
+		t.Fatalf("Expected today to be zero, got %d (present: %v)", count, ok)
+	}
+
+	cols := BuildCols(SortMapIntoSlice(counts), counts, DefaultGraphRange)
+	_, todayWeek, _ := calculateGraphParameters(cols, GetBeginningOfDay(time.Now()), false, 0, DefaultGraphRange)
+	col, ok := cols[todayWeek]
+	if !ok || len(col) != DaysInWeek {
+		t.Errorf("Expected a full column for the current week, got %v", col)
+	}
+}
+
+// TestResolveHead tests the ResolveHead function
+func TestResolveHead(t *testing.T) {
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	hash, err := worktree.Commit("Initial commit", &git.CommitOptions{
+		Author:            &object.Signature{Name: "Me", Email: "me@example.com", When: time.Now()},
+		AllowEmptyCommits: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	// Test case 1: HEAD points to a branch with commits
+	ref, err := ResolveHead(repo)
+	if err != nil || ref.Hash() != hash {
+		t.Errorf("Expected HEAD to resolve to %s, got %v (%v)", hash, ref, err)
+	}
+
+	// Test case 2: HEAD points to a branch without commits, like in a mirror
+	originMain := plumbing.NewRemoteReferenceName("origin", "main")
+	originHead := plumbing.NewRemoteHEADReferenceName("origin")
+	refs := []*plumbing.Reference{
+		plumbing.NewHashReference(originMain, hash),
+		plumbing.NewSymbolicReference(originHead, originMain),
+		plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("unborn")),
+	}
+	for _, r := range refs {
+		if err := repo.Storer.SetReference(r); err != nil {
+			t.Fatalf("Failed to set reference %s: %v", r.Name(), err)
+		}
+	}
+
+	ref, err = ResolveHead(repo)
+	if err != nil || ref.Hash() != hash {
+		t.Errorf("Expected fallback to origin/HEAD at %s, got %v (%v)", hash, ref, err)
+	}
+
+	// Test case 3: Nothing to fall back to
+	for _, name := range []plumbing.ReferenceName{originHead, originMain, plumbing.Master} {
+		if err := repo.Storer.RemoveReference(name); err != nil {
+			t.Fatalf("Failed to remove reference %s: %v", name, err)
+		}
+	}
+
+	_, err = ResolveHead(repo)
+	if err == nil || errors.Is(err, ErrEmptyHead) {
+		t.Errorf("Expected an error without any reference to fall back to, got %v", err)
+	}
+
+	// Test case 4: HEAD points to an orphan branch, but another branch has commits
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("feature"), hash)); err != nil {
+		t.Fatalf("Failed to set the feature branch: %v", err)
+	}
+
+	_, err = ResolveHead(repo)
+	if !errors.Is(err, ErrEmptyHead) || !strings.Contains(err.Error(), "--head feature") {
+		t.Errorf("Expected ErrEmptyHead naming the feature branch, got %v", err)
+	}
+}
+
 // TestSortMapIntoSlice tests the SortMapIntoSlice function
 func TestSortMapIntoSlice(t *testing.T) {
 	// Test case 1: Empty map
@@ -139,7 +694,7 @@ func TestBuildCols(t *testing.T) {
 	// Test case 1: Empty keys and commits
 	keys := []int{}
 	commits := map[int]int{}
-	result := BuildCols(keys, commits)
+	result := BuildCols(keys, commits, DefaultGraphRange)
 	if len(result) != 0 {
 		t.Errorf("Expected empty columns for empty input, got %v", result)
 	}
@@ -162,7 +717,7 @@ func TestBuildCols(t *testing.T) {
 			5: 6, // Friday
 			6: 7, // Saturday
 		}
-		result = BuildCols(keys, commits)
+		result = BuildCols(keys, commits, DefaultGraphRange)
 		expected := map[int]Column{
 			0: {1, 2, 3, 4, 5, 6, 7},
 		}
@@ -188,7 +743,7 @@ func TestBuildCols(t *testing.T) {
 			12: 13, // Week 1, Friday
 			13: 14, // Week 1, Saturday
 		}
-		result = BuildCols(keys, commits)
+		result = BuildCols(keys, commits, DefaultGraphRange)
 		expected = map[int]Column{
 			0: {1, 2, 3, 4, 5, 6, 7},
 			1: {8, 9, 10, 11, 12, 13, 14},
@@ -199,6 +754,58 @@ func TestBuildCols(t *testing.T) {
 	*/
 }
 
+// TestBuildColsAlignToToday tests that the current week is the last column when aligned to today
+func TestBuildColsAlignToToday(t *testing.T) {
+	// Use a fixed Wednesday for testing
+	today := time.Date(2023, 5, 17, 0, 0, 0, 0, time.UTC)
+
+	// Today, last Sunday, last Saturday, and the oldest day of the window
+	oldest := WeeksInLastSixMonths*DaysInWeek + 3
+	commits := map[int]int{0: 1, 3: 2, 4: 3, oldest: 4, oldest + 1: 5}
+	result := buildCols(SortMapIntoSlice(commits), commits, today, true, DefaultGraphRange)
+
+	expected := map[int]Column{
+		0:                    {2, 0, 0, 1, 0, 0, 0},
+		1:                    {0, 0, 0, 0, 0, 0, 3},
+		WeeksInLastSixMonths: {4, 0, 0, 0, 0, 0, 0},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// Today is always in week 0
+	_, todayWeek, maxWeek := calculateGraphParameters(BuildCols(nil, nil, DefaultGraphRange), GetBeginningOfDay(time.Now()), true, 0, DefaultGraphRange)
+	if todayWeek != 0 || maxWeek != WeeksInLastSixMonths {
+		t.Errorf("Expected today in week 0 of %d, got week %d of %d", WeeksInLastSixMonths, todayWeek, maxWeek)
+	}
+}
+
+// TestWeeklyTotals tests the WeeklyTotals function
+func TestWeeklyTotals(t *testing.T) {
+	cols := map[int]Column{
+		0: {1, 2, 3, 0, 0, 0, 0},
+		3: {0, 0, 0, 0, 0, 0, 10},
+	}
+
+	expected := map[int]int{0: 6, 3: 10}
+	if result := WeeklyTotals(cols); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	counts := map[int]int{0: 1, 1: 2, 100: 5}
+	total := 0
+	for _, count := range WeeklyCounts(counts, RenderOptions{AlignToToday: true}) {
+		total += count
+	}
+	if total != 8 {
+		t.Errorf("Expected weekly counts totaling 8, got %d", total)
+	}
+
+	// This just ensures the weekly graph doesn't panic
+	PrintWeeklyStats(io.Discard, map[int]int{0: 1, 8: 40}, RenderOptions{})
+	PrintWeeklyStats(io.Discard, map[int]int{0: 1, 8: 40}, RenderOptions{Relative: true, ShowCommitCount: true})
+}
+
 // Note: The following functions are primarily concerned with output formatting
 // and would typically be tested with integration tests or visual inspection.
 // For unit tests, we'll focus on ensuring they don't panic.
@@ -227,7 +834,8 @@ func TestPrintCell(t *testing.T) {
 
 	for _, tc := range testCases {
 		// This test just ensures the function doesn't panic
-		PrintCell(tc.val, tc.today, testDate, false, false)
+		PrintCell(io.Discard, tc.val, tc.today, testDate, RenderOptions{})
+		PrintCell(io.Discard, tc.val, tc.today, testDate, RenderOptions{Style: StyleBlocks, ShowCommitCount: true})
 	}
 }
 
@@ -241,7 +849,7 @@ func TestPrintCommitsStats(t *testing.T) {
 	}
 
 	// This test just ensures the function doesn't panic
-	PrintCommitsStats(commits, false, false)
+	PrintCommitsStats(io.Discard, commits, RenderOptions{})
 }
 
 // TestPrintCells tests that PrintCells doesn't panic
@@ -253,13 +861,89 @@ func TestPrintCells(t *testing.T) {
 	}
 
 	// This test just ensures the function doesn't panic
-	PrintCells(cols, false, false)
+	PrintCells(io.Discard, cols, RenderOptions{})
+	PrintCells(io.Discard, cols, RenderOptions{AlignToToday: true})
+	PrintCells(io.Discard, cols, RenderOptions{GraphOnly: true})
 }
 
 // TestPrintMonths tests that PrintMonths doesn't panic
 func TestPrintMonths(t *testing.T) {
 	// This test just ensures the function doesn't panic
-	PrintMonths()
+	PrintMonths(io.Discard, RenderOptions{})
+	PrintMonths(io.Discard, RenderOptions{AlignToToday: true})
+}
+
+// TestMonthStarts tests the columns starting a month
+func TestMonthStarts(t *testing.T) {
+	// The graph starts on Sunday 2023-01-01, whose column overlaps the day labels
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	months := monthStarts(start, WeeksInLastSixMonths, DefaultGraphRange)
+
+	// February starts in the 5th column, March in the 9th
+	if months[WeeksInLastSixMonths-4] != "Feb" || months[WeeksInLastSixMonths-8] != "Mar" {
+		t.Errorf("Unexpected month columns: %v", months)
+	}
+	if _, ok := months[WeeksInLastSixMonths]; ok {
+		t.Errorf("Expected no label on the oldest column, got %v", months)
+	}
+	if len(months) != 6 {
+		t.Errorf("Expected 6 months, got %d: %v", len(months), months)
+	}
+}
+
+// TestPrintCellsStripe tests that PrintCells doesn't panic with stripes
+func TestPrintCellsStripe(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	cols := map[int]Column{0: {0, 1, 0, 0, 0, 0, 0}}
+	PrintCells(io.Discard, cols, RenderOptions{Stripe: true})
+	PrintCells(io.Discard, cols, RenderOptions{Stripe: true, Style: StyleBlocks, Compact: true})
+	PrintWeeklyStats(io.Discard, map[int]int{0: 3}, RenderOptions{Stripe: true})
+}
+
+// TestFixedWeeks tests that a fixed number of weeks sets the number of columns, whatever the data
+func TestFixedWeeks(t *testing.T) {
+	cols := map[int]Column{0: {0, 1, 0, 0, 0, 0, 0}, 20: {2, 0, 0, 0, 0, 0, 0}}
+
+	for _, weeks := range []int{53, 10} {
+		if _, _, maxWeek := calculateGraphParameters(cols, GetBeginningOfDay(time.Now()), true, weeks, DefaultGraphRange); maxWeek != weeks-1 {
+			t.Errorf("Expected %d columns, got %d", weeks, maxWeek+1)
+		}
+	}
+
+	// The labels and the cells span the padded columns
+	opts := RenderOptions{Weeks: 53}
+	if width := opts.GridWidth(); width != dayColWidth+opts.cellWidth()*53+3 {
+		t.Errorf("Expected the width of 53 columns, got %d", width)
+	}
+	PrintCells(io.Discard, cols, opts)
+	PrintWeeklyStats(io.Discard, map[int]int{0: 3}, RenderOptions{Weeks: 10, WeekNumbers: true})
+}
+
+// TestEmptyWeekdays tests the detection of the rows without commits
+func TestEmptyWeekdays(t *testing.T) {
+	// Test case 1: Commits on weekdays only, Sunday (0) and Saturday (6) are empty
+	cols := map[int]Column{
+		0: {0, 1, 0, 2, 0, 0, 0},
+		1: {0, 0, 3, 0, 1, 4},
+	}
+	expected := [DaysInWeek]bool{true, false, false, false, false, false, true}
+	if result := emptyWeekdays(cols); result != expected {
+		t.Errorf("Expected %v, got %v", expected, result)
+	}
+
+	// Test case 2: Without commits, no row is left out
+	cols = map[int]Column{0: {0, 0, 0, 0, 0, 0, 0}}
+	if result := emptyWeekdays(cols); result != ([DaysInWeek]bool{}) {
+		t.Errorf("Expected no empty rows, got %v", result)
+	}
+}
+
+// TestPrintCellsCollapseEmptyRows tests that PrintCells doesn't panic with rows left out
+func TestPrintCellsCollapseEmptyRows(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	cols := map[int]Column{0: {0, 1, 0, 0, 0, 0, 0}}
+	PrintCells(io.Discard, cols, RenderOptions{CollapseEmptyRows: true})
+	PrintCells(io.Discard, cols, RenderOptions{CollapseEmptyRows: true, GraphOnly: true})
 }
 
 // TestPrintDayCol tests that PrintDayCol doesn't panic
@@ -267,11 +951,144 @@ func TestPrintDayCol(t *testing.T) {
 	// Test all day values
 	for day := 0; day <= 6; day++ {
 		// This test just ensures the function doesn't panic
-		PrintDayCol(day)
+		PrintDayCol(io.Discard, day)
+	}
+}
+
+// TestGetCommitsEmailFilter tests that only the commits of the filtered emails are collected
+func TestGetCommitsEmailFilter(t *testing.T) {
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+	repo := testutil.NewMemoryRepo(t,
+		testutil.Commit{Email: "alice@example.com", Message: "Older", When: today.AddDate(0, 0, -3).Add(10 * time.Hour)},
+		testutil.Commit{Email: "bob@example.com", Message: "Bob's", When: today.AddDate(0, 0, -2).Add(10 * time.Hour)},
+		testutil.Commit{Email: "Alice@Example.com", Message: "Newer\n\nWith a body", When: today.Add(9 * time.Hour)},
+	)
+	start, err := ResolveStart(repo, "")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+
+	// Test case 1: Only Alice's commits, whatever the case of her email
+	commits, err := getCommits(Filter{Emails: []string{"alice@example.com"}}, repo, "mem", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts := CountCommits(commits, DefaultGraphRange); counts[0] != 1 || counts[2] != 0 || counts[3] != 1 {
+		t.Errorf("Expected a commit today and 3 days ago, got %v", commits)
+	}
+	if len(commits[0]) != 1 || commits[0][0].Subject != "Newer" {
+		t.Errorf("Expected today's commit to have the subject Newer, got %+v", commits[0])
+	}
+
+	// Test case 2: Every author without emails
+	commits, err = getCommits(Filter{}, repo, "mem", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts := CountCommits(commits, DefaultGraphRange); counts[0] != 1 || counts[2] != 1 || counts[3] != 1 {
+		t.Errorf("Expected a commit today, 2 and 3 days ago, got %v", commits)
+	}
+
+	// Test case 3: Nobody's commits
+	commits, err = getCommits(Filter{Emails: []string{"carol@example.com"}}, repo, "mem", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange)
+	if err != nil || len(commits) != 0 {
+		t.Errorf("Expected no commits, got %v (%v)", commits, err)
 	}
 }
 
-// Note: GetCommitsFromRepo and ProcessRepositories are more complex to test
-// as they interact with Git repositories and the file system.
-// These would typically be tested with integration tests or mocks.
-// For now, we'll skip detailed unit tests for these functions.
+// TestGetCommitsOutOfRange tests that the commits before the window are left out, and traced
+func TestGetCommitsOutOfRange(t *testing.T) {
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+	repo := testutil.NewMemoryRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Too old", When: today.AddDate(0, 0, -DaysInLastSixMonths-1).Add(23 * time.Hour)},
+		testutil.Commit{Email: "me@example.com", Message: "First day", When: today.AddDate(0, 0, -DaysInLastSixMonths)},
+		testutil.Commit{Email: "me@example.com", Message: "Today", When: today.Add(23 * time.Hour)},
+	)
+	start, err := ResolveStart(repo, "")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+
+	var trace bytes.Buffer
+	commits, err := getCommits(Filter{Trace: &trace}, repo, "mem", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(commits) != 2 || len(commits[DaysInLastSixMonths]) != 1 || len(commits[0]) != 1 {
+		t.Errorf("Expected a commit on the first day of the window and today, got %v", commits)
+	}
+	if _, ok := commits[OutOfRange]; ok {
+		t.Errorf("Expected no commits out of range, got %v", commits[OutOfRange])
+	}
+	if !strings.Contains(trace.String(), SkipOutOfRange) {
+		t.Errorf("Expected the old commit to be traced as out of range, got %q", trace.String())
+	}
+}
+
+// TestProcessRepositories tests the collection of the commits of a repository on disk
+func TestProcessRepositories(t *testing.T) {
+	now := time.Now()
+	dir := testutil.NewRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Mine", When: now.AddDate(0, 0, -1)},
+		testutil.Commit{Email: "other@example.com", Message: "Theirs", When: now},
+	)
+
+	commits, err := ProcessRepositories(Filter{Emails: []string{"me@example.com"}}, dir, DefaultGraphRange)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if counts := CountCommits(commits, DefaultGraphRange); counts[0] != 0 || counts[1] != 1 {
+		t.Errorf("Expected a single commit yesterday, got %v", commits)
+	}
+
+	// Folders that aren't repositories are errors
+	if _, err := ProcessRepositories(Filter{}, t.TempDir(), DefaultGraphRange); err == nil {
+		t.Errorf("Expected an error for a folder without repository, got nil")
+	}
+}
+
+// BenchmarkGetCommitsFromRepo measures the walk of a synthetic in-memory history,
+// with commits spread over a year so both sides of the window are exercised
+func BenchmarkGetCommitsFromRepo(b *testing.B) {
+	now := time.Now()
+	history := make([]testutil.Commit, 2000)
+	for i := range history {
+		history[i] = testutil.Commit{Email: "me@example.com", Message: "Commit", When: now.Add(-time.Duration(len(history)-1-i) * 4 * time.Hour)}
+	}
+	repo := testutil.NewMemoryRepo(b, history...)
+
+	start, err := ResolveStart(repo, "")
+	if err != nil {
+		b.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+
+	filter := Filter{Emails: []string{"me@example.com"}}
+	today := GetBeginningOfDay(now)
+	for b.Loop() {
+		if _, err := getCommits(filter, repo, "bench", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+	}
+}
+
+// TestISOWeekLabel tests the labels of the week numbers row
+func TestISOWeekLabel(t *testing.T) {
+	testCases := []struct {
+		startOfWeek time.Time
+		expected    string
+	}{
+		// Sunday 2023-01-01 belongs to 2022-W52, its Monday to 2023-W01
+		{time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), " 1 "},
+		{time.Date(2023, 5, 14, 0, 0, 0, 0, time.UTC), "20 "},
+		{time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC), "53 "},
+	}
+
+	for _, tc := range testCases {
+		if result := isoWeekLabel(tc.startOfWeek); result != tc.expected {
+			t.Errorf("isoWeekLabel(%s): expected %q, got %q", tc.startOfWeek.Format("2006-01-02"), tc.expected, result)
+		}
+	}
+
+	// This just ensures the row doesn't panic
+	PrintWeekNumbers(io.Discard, time.Now().AddDate(0, 0, -WeeksInLastSixMonths*DaysInWeek), WeeksInLastSixMonths, DefaultGraphRange)
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// CleanSubdir normalizes a directory of a repository for Filter.Subdir: slash-separated,
+// relative to the root of the repository and without trailing slash.
+//
+// Parameters:
+//   - dir: The directory, e.g. ./packages/ui/
+//
+// Returns:
+//   - string: The normalized directory, e.g. packages/ui, or an empty string for the root
+func CleanSubdir(dir string) string {
+	dir = path.Clean("/" + strings.ReplaceAll(strings.TrimSpace(dir), "\\", "/"))
+	return strings.TrimPrefix(dir, "/")
+}
+
+// subtreeHash returns the hash of the tree (or file) at the given path of the commit,
+// or the zero hash if the path doesn't exist in the commit.
+func subtreeHash(c *object.Commit, dir string) (plumbing.Hash, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	entry, err := tree.FindEntry(dir)
+	if errors.Is(err, object.ErrDirectoryNotFound) || errors.Is(err, object.ErrEntryNotFound) {
+		return plumbing.ZeroHash, nil
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return entry.Hash, nil
+}
+
+// touchesSubdir reports whether a commit changed files under the given directory.
+// Trees are compared by hash rather than diffed file by file, which is cheap.
+// Like git log -- <dir>, a merge only counts when it differs from every parent under the directory,
+// so merging changes already counted on a branch doesn't count them twice.
+func touchesSubdir(c *object.Commit, dir string) (bool, error) {
+	hash, err := subtreeHash(c, dir)
+	if err != nil {
+		return false, err
+	}
+
+	// A root commit touches the directory if it creates it
+	if c.NumParents() == 0 {
+		return !hash.IsZero(), nil
+	}
+
+	parents := c.Parents()
+	defer parents.Close()
+
+	touched := true
+	err = parents.ForEach(func(parent *object.Commit) error {
+		parentHash, err := subtreeHash(parent, dir)
+		if err != nil {
+			return err
+		}
+		if parentHash == hash {
+			touched = false
+		}
+		return nil
+	})
+	return touched, err
+}
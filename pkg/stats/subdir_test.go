@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/acheddir/git-contrib/pkg/testutil"
+	"github.com/go-git/go-git/v5"
+)
+
+// TestCleanSubdir tests the CleanSubdir function
+func TestCleanSubdir(t *testing.T) {
+	testCases := []struct {
+		dir      string
+		expected string
+	}{
+		{"packages/ui", "packages/ui"},
+		{"./packages/ui/", "packages/ui"},
+		{"/packages//ui", "packages/ui"},
+		{`packages\ui`, "packages/ui"},
+		{".", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if result := CleanSubdir(tc.dir); result != tc.expected {
+			t.Errorf("CleanSubdir(%q): expected %q, got %q", tc.dir, tc.expected, result)
+		}
+	}
+}
+
+// TestGetCommitsSubdir tests that only the commits changing the subdirectory are counted
+func TestGetCommitsSubdir(t *testing.T) {
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+	at := func(daysAgo int) time.Time { return today.AddDate(0, 0, -daysAgo).Add(12 * time.Hour) }
+
+	repo := testutil.NewMemoryRepo(t,
+		testutil.Commit{Email: "me@example.com", Message: "Create the API", When: at(5), Files: map[string]string{"packages/api/main.go": "v1"}},
+		testutil.Commit{Email: "me@example.com", Message: "Create the UI", When: at(4), Files: map[string]string{"packages/ui/app.tsx": "v1"}},
+		testutil.Commit{Email: "me@example.com", Message: "Change the API", When: at(3), Files: map[string]string{"packages/api/main.go": "v2"}},
+		testutil.Commit{Email: "me@example.com", Message: "Empty", When: at(2)},
+		testutil.Commit{Email: "me@example.com", Message: "Change both", When: at(1), Files: map[string]string{"packages/api/main.go": "v3", "packages/ui/nested/button.tsx": "v1"}},
+	)
+	start, err := ResolveStart(repo, "")
+	if err != nil {
+		t.Fatalf("Failed to resolve HEAD: %v", err)
+	}
+
+	commits, err := getCommits(Filter{Subdir: "packages/ui"}, repo, "mem", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(commits) != 2 || len(commits[4]) != 1 || len(commits[1]) != 1 {
+		t.Errorf("Expected the commits of 4 and 1 days ago, got %v", commits)
+	}
+
+	// A subdirectory that never existed has no commits
+	commits, err = getCommits(Filter{Subdir: "docs"}, repo, "mem", &git.LogOptions{From: start}, make(map[int][]CommitInfo), today, DefaultGraphRange)
+	if err != nil || len(commits) != 0 {
+		t.Errorf("Expected no commits, got %v (%v)", commits, err)
+	}
+}
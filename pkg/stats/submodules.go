@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// Submodules returns the paths of the checked-out submodules of a repository, including
+// the submodules of submodules. Submodules that aren't initialized (empty folder, or a
+// checkout without commits) are skipped, as they have no history to walk.
+//
+// Parameters:
+//   - path: The path of the repository
+//
+// Returns:
+//   - []string: The paths of the checked-out submodules
+//   - error: An error if the repository or its .gitmodules file can't be read
+func Submodules(path string) ([]string, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, err
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	submodules, err := worktree.Submodules()
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, submodule := range submodules {
+		subPath := filepath.Join(path, filepath.FromSlash(submodule.Config().Path))
+
+		// Opening the checkout rather than submodule.Repository(), which would initialize it
+		subRepo, err := git.PlainOpenWithOptions(subPath, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			continue
+		}
+		if _, err := subRepo.Head(); err != nil {
+			continue
+		}
+
+		paths = append(paths, subPath)
+		if nested, err := Submodules(subPath); err == nil {
+			paths = append(paths, nested...)
+		}
+	}
+
+	return paths, nil
+}
+
+// withSubmodules appends the checked-out submodules of the repositories to them,
+// without duplicates (a submodule may also be tracked on its own, e.g. found by scan).
+func withSubmodules(repos []string) []string {
+	seen := make(map[string]bool, len(repos))
+	for _, repo := range repos {
+		seen[filepath.Clean(repo)] = true
+	}
+
+	result := append([]string(nil), repos...)
+	for _, repo := range repos {
+		// A repository that can't be read is reported when its commits are read
+		submodules, err := Submodules(repo)
+		if err != nil {
+			continue
+		}
+
+		for _, submodule := range submodules {
+			if !seen[submodule] {
+				seen[submodule] = true
+				result = append(result, submodule)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,77 @@
+package stats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TestSubmodules tests that the commits of checked-out submodules are counted
+func TestSubmodules(t *testing.T) {
+	now := time.Now()
+	parent := createTestRepo(t, testCommit{"dev@example.com", "Parent commit", now})
+
+	// A checked-out submodule with its own history
+	lib := filepath.Join(parent, "lib")
+	repo, err := git.PlainInit(lib, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+	for _, message := range []string{"First lib commit", "Second lib commit"} {
+		signature := &object.Signature{Name: "dev", Email: "dev@example.com", When: now}
+		_, err := worktree.Commit(message, &git.CommitOptions{Author: signature, Committer: signature, AllowEmptyCommits: true})
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	// An uninitialized submodule is an empty folder
+	if err := os.MkdirAll(filepath.Join(parent, "missing"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+
+	gitmodules := `[submodule "lib"]
+	path = lib
+	url = https://example.com/lib.git
+[submodule "missing"]
+	path = missing
+	url = https://example.com/missing.git
+`
+	if err := os.WriteFile(filepath.Join(parent, ".gitmodules"), []byte(gitmodules), 0644); err != nil {
+		t.Fatalf("Failed to write .gitmodules: %v", err)
+	}
+
+	submodules, err := Submodules(parent)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(submodules) != 1 || submodules[0] != lib {
+		t.Errorf("Expected only %s, got %v", lib, submodules)
+	}
+
+	// Tracking the submodule on its own doesn't count its commits twice
+	result, err := Analyze(Options{Repositories: []string{parent, lib}, Submodules: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Summary.Total != 3 || len(result.Repositories) != 2 {
+		t.Errorf("Expected 3 commits in 2 repositories, got %d in %d", result.Summary.Total, len(result.Repositories))
+	}
+
+	// Without the option, only the parent is walked
+	result, err = Analyze(Options{Repositories: []string{parent}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Summary.Total != 1 {
+		t.Errorf("Expected 1 commit, got %d", result.Summary.Total)
+	}
+}
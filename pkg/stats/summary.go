@@ -0,0 +1,213 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Summary holds aggregated statistics about the commits displayed in the contribution graph.
+type Summary struct {
+	// Total is the number of commits in the graph window
+	Total int
+	// ActiveDays is the number of days with at least one commit
+	ActiveDays int
+	// CurrentStreak is the number of consecutive days with commits ending today (or yesterday)
+	CurrentStreak int
+	// LongestStreak is the longest number of consecutive days with commits in the window
+	LongestStreak int
+	// Years holds the commits of each calendar year the window overlaps, oldest first
+	Years []PeriodCount
+	// PreviousHalf is the number of commits in the older half of the window
+	PreviousHalf int
+	// RecentHalf is the number of commits in the more recent half of the window
+	RecentHalf int
+	// LongestGap is the longest number of consecutive days without commits in the window
+	LongestGap int
+	// GapStart is the first day of the longest gap (zero if there is no gap)
+	GapStart time.Time
+	// GapEnd is the last day of the longest gap (zero if there is no gap)
+	GapEnd time.Time
+	// Bursts holds the commits made within a short window of another one (only set with Options.BurstWindow)
+	Bursts *Bursts
+}
+
+// Trend returns the change of activity between the two halves of the window, in percent.
+//
+// Returns:
+//   - float64: The change in percent, positive when the activity rises
+//   - bool: false if the older half has no commits, so no percentage can be computed
+func (s Summary) Trend() (float64, bool) {
+	if s.PreviousHalf == 0 {
+		return 0, false
+	}
+	return float64(s.RecentHalf-s.PreviousHalf) / float64(s.PreviousHalf) * 100, true
+}
+
+// PerActiveDay returns the average number of commits of the days with commits, a measure of
+// focus that ignores idle days, unlike the average over the whole window.
+//
+// Returns:
+//   - float64: The commits per active day, zero without active days
+func (s Summary) PerActiveDay() float64 {
+	if s.ActiveDays == 0 {
+		return 0
+	}
+	return float64(s.Total) / float64(s.ActiveDays)
+}
+
+// Summarize computes totals and streaks from a map of days to commit counts.
+// When workingDaysOnly is set, days falling on a Saturday or Sunday without commits
+// don't break a streak (weekend commits still extend it). Public holidays are not
+// considered, a working day without commits always breaks the streak.
+//
+// Parameters:
+//   - commits: A map of days to commit counts
+//   - workingDaysOnly: Whether quiet weekends should be ignored by the streak calculations
+//   - window: The window of the graph (if empty, DefaultGraphRange)
+//
+// Returns:
+//   - Summary: The computed summary
+func Summarize(commits map[int]int, workingDaysOnly bool, window GraphRange) Summary {
+	return summarize(commits, workingDaysOnly, GetBeginningOfDay(time.Now()), window.orDefault())
+}
+
+// summarize computes the summary relative to the given day, see Summarize.
+// The longest gap is the inverse of the longest streak: a gap at the start of the window
+// is cut by it (the days before aren't known), and today only ends a gap once it has commits
+// since it isn't over yet. Quiet weekends always count, even with workingDaysOnly.
+func summarize(commits map[int]int, workingDaysOnly bool, today time.Time, window GraphRange) Summary {
+	summary := Summary{Years: countByYear(commits, today, window)}
+
+	// Skips a day without commits instead of breaking the streak
+	skippable := func(daysAgo int) bool {
+		return workingDaysOnly && isWeekend(today.AddDate(0, 0, -daysAgo))
+	}
+
+	// Walk from the oldest day to today to compute totals, the longest streak and gap
+	streak := 0
+	gap := 0
+	for daysAgo := window.Days; daysAgo >= 0; daysAgo-- {
+		count := commits[daysAgo]
+		summary.Total += count
+
+		if count == 0 && daysAgo > 0 {
+			gap++
+			if gap > summary.LongestGap {
+				summary.LongestGap = gap
+				summary.GapStart = today.AddDate(0, 0, -(daysAgo + gap - 1))
+				summary.GapEnd = today.AddDate(0, 0, -daysAgo)
+			}
+		} else {
+			gap = 0
+		}
+
+		// Split the window in two halves of equal length to compare them
+		if daysAgo < (window.Days+1)/2 {
+			summary.RecentHalf += count
+		} else {
+			summary.PreviousHalf += count
+		}
+
+		switch {
+		case count > 0:
+			summary.ActiveDays++
+			streak++
+			if streak > summary.LongestStreak {
+				summary.LongestStreak = streak
+			}
+		case !skippable(daysAgo):
+			streak = 0
+		}
+	}
+
+	// Walk back from today to compute the current streak
+	for daysAgo := 0; daysAgo <= window.Days; daysAgo++ {
+		count := commits[daysAgo]
+
+		// Today isn't over yet, so no commits today doesn't break the streak
+		if daysAgo == 0 && count == 0 {
+			continue
+		}
+
+		if count > 0 {
+			summary.CurrentStreak++
+		} else if !skippable(daysAgo) {
+			break
+		}
+	}
+
+	return summary
+}
+
+// printTrend prints whether the activity rises or falls between the two halves of the window.
+func printTrend(w io.Writer, summary Summary) {
+	trend, ok := summary.Trend()
+	switch {
+	case !ok && summary.RecentHalf > 0:
+		fmt.Fprintf(w, "Activity up vs the previous period (no commits before).\n")
+	case !ok:
+		// No commits at all, nothing to compare
+	case math.Round(trend) > 0:
+		fmt.Fprintf(w, "Activity up %.0f%% vs the previous period.\n", trend)
+	case math.Round(trend) < 0:
+		fmt.Fprintf(w, "Activity down %.0f%% vs the previous period.\n", -trend)
+	default:
+		fmt.Fprintf(w, "Activity unchanged vs the previous period.\n")
+	}
+}
+
+// isWeekend reports whether the given date falls on a Saturday or a Sunday.
+func isWeekend(date time.Time) bool {
+	weekday := date.Weekday()
+	return weekday == time.Saturday || weekday == time.Sunday
+}
+
+// PrintSummary prints the summary below the contribution graph.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - summary: The summary to print
+func PrintSummary(w io.Writer, summary Summary) {
+	fmt.Fprintf(w, "\n")
+	fmt.Fprintf(w, "Total commits:  %d\n", summary.Total)
+	fmt.Fprintf(w, "Active days:    %d\n", summary.ActiveDays)
+	if summary.ActiveDays > 0 {
+		fmt.Fprintf(w, "Per active day: %.2f commits\n", summary.PerActiveDay())
+	}
+	fmt.Fprintf(w, "Current streak: %d days\n", summary.CurrentStreak)
+	fmt.Fprintf(w, "Longest streak: %d days\n", summary.LongestStreak)
+	if summary.LongestGap > 0 {
+		fmt.Fprintf(w, "Longest gap:    %d days (%s to %s)\n", summary.LongestGap,
+			summary.GapStart.Format("2006-01-02"), summary.GapEnd.Format("2006-01-02"))
+	}
+
+	printTrend(w, summary)
+	printBursts(w, summary)
+
+	// Break the total down when the window crosses a year boundary
+	if len(summary.Years) > 1 {
+		for _, year := range summary.Years {
+			fmt.Fprintf(w, "Commits in %s: %d\n", year.Label, year.Count)
+		}
+	}
+}
+
+// GoalMessage returns a nudge comparing the commits of today to a daily goal.
+//
+// Parameters:
+//   - today: The number of commits of today
+//   - goal: The number of commits aimed for each day (must be positive)
+//
+// Returns:
+//   - string: A congratulation when the goal is met, a warning without commits, the remaining commits otherwise
+func GoalMessage(today int, goal int) string {
+	switch {
+	case today >= goal:
+		return fmt.Sprintf("🎯 Goal met: %d/%d commits today", today, goal)
+	case today == 0:
+		return "⚠️ 0 commits today."
+	}
+	return fmt.Sprintf("%d/%d commits today, %d to go.", today, goal, goal-today)
+}
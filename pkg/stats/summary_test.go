@@ -0,0 +1,156 @@
+package stats
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestSummarize tests the summarize function
+func TestSummarize(t *testing.T) {
+	// Use a fixed Monday for testing
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	// Commits on Monday (today), last Friday and last Thursday
+	commits := map[int]int{0: 1, 3: 2, 4: 1}
+
+	// Test case 1: The quiet weekend breaks the streak
+	years := []PeriodCount{{Label: "2022", Count: 0}, {Label: "2023", Count: 4}}
+
+	// The quiet days from the start of the window to 5 days ago
+	gapStart, gapEnd := today.AddDate(0, 0, -DaysInLastSixMonths), today.AddDate(0, 0, -5)
+
+	result := summarize(commits, false, today, DefaultGraphRange)
+	expected := Summary{Total: 4, ActiveDays: 3, CurrentStreak: 1, LongestStreak: 2, Years: years, RecentHalf: 4,
+		LongestGap: DaysInLastSixMonths - 4, GapStart: gapStart, GapEnd: gapEnd}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	// Test case 2: The quiet weekend is ignored with working days only
+	result = summarize(commits, true, today, DefaultGraphRange)
+	expected = Summary{Total: 4, ActiveDays: 3, CurrentStreak: 3, LongestStreak: 3, Years: years, RecentHalf: 4,
+		LongestGap: DaysInLastSixMonths - 4, GapStart: gapStart, GapEnd: gapEnd}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, result)
+	}
+
+	// Test case 3: No commits today doesn't break the current streak
+	commits = map[int]int{1: 1, 2: 1}
+	result = summarize(commits, false, today, DefaultGraphRange)
+	if result.CurrentStreak != 2 {
+		t.Errorf("Expected current streak of 2, got %d", result.CurrentStreak)
+	}
+
+	// Test case 4: A quiet working day breaks the streak even with working days only
+	commits = map[int]int{3: 1, 5: 1}
+	result = summarize(commits, true, today, DefaultGraphRange)
+	if result.LongestStreak != 1 {
+		t.Errorf("Expected longest streak of 1, got %d", result.LongestStreak)
+	}
+}
+
+// TestSummaryLongestGap tests the longest run of days without commits
+func TestSummaryLongestGap(t *testing.T) {
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+
+	// Test case 1: A gap between two commits
+	commits := map[int]int{DaysInLastSixMonths: 1, 20: 1, 10: 1, 0: 1}
+	for daysAgo := DaysInLastSixMonths - 1; daysAgo > 20; daysAgo-- {
+		commits[daysAgo] = 1
+	}
+	summary := summarize(commits, false, today, DefaultGraphRange)
+	if summary.LongestGap != 9 || !summary.GapStart.Equal(today.AddDate(0, 0, -19)) || !summary.GapEnd.Equal(today.AddDate(0, 0, -11)) {
+		t.Errorf("Expected a 9-day gap from 19 to 11 days ago, got %d from %v to %v", summary.LongestGap, summary.GapStart, summary.GapEnd)
+	}
+
+	// Test case 2: Today without commits doesn't extend a trailing gap
+	delete(commits, 0)
+	commits[10] = 0
+	summary = summarize(commits, false, today, DefaultGraphRange)
+	if summary.LongestGap != 19 || !summary.GapEnd.Equal(today.AddDate(0, 0, -1)) {
+		t.Errorf("Expected a 19-day gap ending yesterday, got %d ending %v", summary.LongestGap, summary.GapEnd)
+	}
+
+	// Test case 3: No gap when every day has commits
+	for daysAgo := 0; daysAgo <= DaysInLastSixMonths; daysAgo++ {
+		commits[daysAgo] = 1
+	}
+	summary = summarize(commits, false, today, DefaultGraphRange)
+	if summary.LongestGap != 0 || !summary.GapStart.IsZero() {
+		t.Errorf("Expected no gap, got %d", summary.LongestGap)
+	}
+}
+
+// TestSummaryTrend tests the comparison of the two halves of the window
+func TestSummaryTrend(t *testing.T) {
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+	half := (DaysInLastSixMonths + 1) / 2
+
+	// 10 commits in the older half, 13 in the recent one
+	commits := map[int]int{DaysInLastSixMonths: 4, half: 6, half - 1: 10, 0: 3}
+	summary := summarize(commits, false, today, DefaultGraphRange)
+	if summary.PreviousHalf != 10 || summary.RecentHalf != 13 {
+		t.Fatalf("Expected halves of 10 and 13 commits, got %d and %d", summary.PreviousHalf, summary.RecentHalf)
+	}
+	if trend, ok := summary.Trend(); !ok || trend != 30 {
+		t.Errorf("Expected a trend of 30%%, got %v (%v)", trend, ok)
+	}
+
+	// Falling activity
+	if trend, ok := (Summary{PreviousHalf: 8, RecentHalf: 2}).Trend(); !ok || trend != -75 {
+		t.Errorf("Expected a trend of -75%%, got %v (%v)", trend, ok)
+	}
+
+	// No percentage without commits in the older half
+	if _, ok := (Summary{RecentHalf: 2}).Trend(); ok {
+		t.Errorf("Expected no trend without previous commits")
+	}
+}
+
+// TestSummaryPerActiveDay tests that the commits are averaged over the active days only
+func TestSummaryPerActiveDay(t *testing.T) {
+	today := time.Date(2023, 5, 15, 0, 0, 0, 0, time.UTC)
+	summary := summarize(map[int]int{0: 3, 5: 1, 40: 6}, false, today, DefaultGraphRange)
+	if summary.ActiveDays != 3 || summary.PerActiveDay() != 10.0/3 {
+		t.Errorf("Expected 10 commits over 3 active days, got %v over %d", summary.PerActiveDay(), summary.ActiveDays)
+	}
+
+	// No division by zero without commits
+	if perDay := (Summary{}).PerActiveDay(); perDay != 0 {
+		t.Errorf("Expected 0 without active days, got %v", perDay)
+	}
+}
+
+// TestPrintSummary tests that PrintSummary doesn't panic
+func TestPrintSummary(t *testing.T) {
+	// This test just ensures the function doesn't panic
+	PrintSummary(io.Discard, Summary{Total: 4, ActiveDays: 3, CurrentStreak: 1, LongestStreak: 2})
+	PrintSummary(io.Discard, Summary{Total: 4, Years: []PeriodCount{{Label: "2022", Count: 1}, {Label: "2023", Count: 3}}})
+	PrintSummary(io.Discard, Summary{Total: 10, PreviousHalf: 8, RecentHalf: 2})
+	PrintSummary(io.Discard, Summary{Total: 2, RecentHalf: 2})
+	PrintSummary(io.Discard, Summary{Total: 2, LongestGap: 3, GapStart: time.Now().AddDate(0, 0, -4), GapEnd: time.Now().AddDate(0, 0, -2)})
+	PrintSummary(io.Discard, Summary{Total: 5, Bursts: &Bursts{Clustered: 3, Total: 5, Window: DefaultBurstWindow}})
+	PrintSummary(io.Discard, Summary{Bursts: &Bursts{Window: DefaultBurstWindow}})
+}
+
+// TestGoalMessage tests the nudges of the daily goal
+func TestGoalMessage(t *testing.T) {
+	tests := []struct {
+		today    int
+		goal     int
+		expected string
+	}{
+		{5, 3, "🎯 Goal met: 5/3 commits today"},
+		{3, 3, "🎯 Goal met: 3/3 commits today"},
+		{0, 3, "⚠️ 0 commits today."},
+		{1, 3, "1/3 commits today, 2 to go."},
+	}
+
+	for _, test := range tests {
+		if message := GoalMessage(test.today, test.goal); message != test.expected {
+			t.Errorf("GoalMessage(%d, %d) = %q, expected %q", test.today, test.goal, message, test.expected)
+		}
+	}
+}
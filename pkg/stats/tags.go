@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// GetTagsFromRepo retrieves the annotated tags of a Git repository, as the releases cut.
+// Tags are filtered by tagger email like commits are by author email, and dated by their
+// tagger date. Lightweight tags have no tagger, so they are skipped.
+// It updates the provided map with the matched tags of each day, the tag name as subject.
+//
+// Parameters:
+//   - filter: The filter deciding which tags to count
+//   - path: The path to the Git repository
+//   - tags: A map of days to matched tags to update
+//
+// Returns:
+//   - map[int][]CommitInfo: The updated tags map
+//   - error: An error if any occurred during repository processing
+func GetTagsFromRepo(filter Filter, path string, tags map[int][]CommitInfo) (map[int][]CommitInfo, error) {
+	return getTagsFromRepo(filter, path, tags, DefaultGraphRange)
+}
+
+// getTagsFromRepo retrieves the annotated tags like GetTagsFromRepo, within the given window.
+func getTagsFromRepo(filter Filter, path string, tags map[int][]CommitInfo, window GraphRange) (map[int][]CommitInfo, error) {
+	repo, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository at %s: %w", path, err)
+	}
+
+	today := GetBeginningOfDay(time.Now())
+
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag, err := repo.TagObject(ref.Hash())
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			// Lightweight tags point directly at commits
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get tag %s: %w", ref.Name().Short(), err)
+		}
+
+		if reason := filter.RejectReason(tag.Tagger.Email, tag.Message); reason != "" {
+			filter.trace(path, tag.Hash, tag.Tagger, reason)
+			return nil
+		}
+
+		daysAgo := countDaysSince(tag.Tagger.When, today, window)
+		if daysAgo == OutOfRange {
+			filter.trace(path, tag.Hash, tag.Tagger, SkipOutOfRange)
+			return nil
+		}
+		if filter.ExcludeWeekends && isWeekend(tag.Tagger.When) {
+			filter.trace(path, tag.Hash, tag.Tagger, SkipWeekend)
+			return nil
+		}
+
+		tags[daysAgo] = append(tags[daysAgo], CommitInfo{
+			Hash:    tag.Hash.String(),
+			Subject: tag.Name,
+			Email:   filter.Identity(tag.Tagger.Email),
+			When:    tag.Tagger.When,
+		})
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("error processing tags: %w", err)
+	}
+
+	return tags, nil
+}
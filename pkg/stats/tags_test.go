@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// TestGetTagsFromRepo tests that annotated tags are counted by tagger
+func TestGetTagsFromRepo(t *testing.T) {
+	now := time.Now()
+	dir := createTestRepo(t, testCommit{"dev@example.com", "Initial commit", now})
+
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("Failed to open repository: %v", err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("Failed to get HEAD: %v", err)
+	}
+
+	tags := []struct {
+		name   string
+		tagger string
+		when   time.Time
+	}{
+		{"v1.0.0", "release@example.com", now},
+		{"v1.1.0", "release@example.com", now.AddDate(0, 0, -2)},
+		{"v0.1.0", "release@example.com", now.AddDate(-1, 0, 0)},
+		{"nightly", "ci@example.com", now},
+	}
+	for _, tag := range tags {
+		_, err := repo.CreateTag(tag.name, head.Hash(), &git.CreateTagOptions{
+			Tagger:  &object.Signature{Name: tag.tagger, Email: tag.tagger, When: tag.when},
+			Message: "Release " + tag.name,
+		})
+		if err != nil {
+			t.Fatalf("Failed to create tag: %v", err)
+		}
+	}
+
+	// A lightweight tag has no tagger
+	if _, err := repo.CreateTag("lightweight", head.Hash(), nil); err != nil {
+		t.Fatalf("Failed to create tag: %v", err)
+	}
+
+	result, err := GetTagsFromRepo(Filter{Emails: []string{"release@example.com"}}, dir, make(map[int][]CommitInfo))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result[0]) != 1 || result[0][0].Subject != "v1.0.0" || len(result[2]) != 1 {
+		t.Errorf("Expected v1.0.0 today and v1.1.0 two days ago, got %v", result)
+	}
+
+	// The tags are analyzed like commits
+	analysis, err := Analyze(Options{Repositories: []string{dir}, Tags: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if analysis.Summary.Total != 3 {
+		t.Errorf("Expected 3 tags in the window, got %d", analysis.Summary.Total)
+	}
+}
@@ -0,0 +1,84 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ZoneCount holds the number of commits authored in a UTC offset.
+type ZoneCount struct {
+	// Offset is the UTC offset the commits were authored in, in seconds east of UTC
+	Offset int
+	// Count is the number of commits authored in that offset
+	Count int
+}
+
+// Label returns the UTC offset as UTC+hh:mm (or UTC for an offset of zero).
+func (z ZoneCount) Label() string {
+	if z.Offset == 0 {
+		return "UTC"
+	}
+
+	sign := "+"
+	offset := z.Offset
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, offset/3600, offset%3600/60)
+}
+
+// CountByTimezone buckets the commits by the UTC offset of their author date,
+// as recorded by Git when they were authored.
+//
+// Parameters:
+//   - commits: A map of days to matched commits
+//
+// Returns:
+//   - []ZoneCount: The number of commits of each offset, from west to east, without empty offsets
+func CountByTimezone(commits map[int][]CommitInfo) []ZoneCount {
+	counts := make(map[int]int)
+	for _, dayCommits := range commits {
+		for _, c := range dayCommits {
+			_, offset := c.When.Zone()
+			counts[offset]++
+		}
+	}
+
+	zones := make([]ZoneCount, 0, len(counts))
+	for offset, count := range counts {
+		zones = append(zones, ZoneCount{Offset: offset, Count: count})
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Offset < zones[j].Offset
+	})
+
+	return zones
+}
+
+// PrintTimezones prints the number and share of commits of each UTC offset, with a bar
+// scaled to the largest offset.
+//
+// Parameters:
+//   - w: The writer to print to
+//   - zones: The number of commits of each offset
+func PrintTimezones(w io.Writer, zones []ZoneCount) {
+	total := 0
+	maxCount := 0
+	for _, zone := range zones {
+		total += zone.Count
+		if zone.Count > maxCount {
+			maxCount = zone.Count
+		}
+	}
+
+	const barWidth = 40
+
+	fmt.Fprintf(w, "\n")
+	for _, zone := range zones {
+		bar := zone.Count * barWidth / maxCount
+		fmt.Fprintf(w, "%-10s %6d %4.0f%%  %s\n", zone.Label(), zone.Count, float64(zone.Count)*100/float64(total), strings.Repeat("#", bar))
+	}
+}
@@ -0,0 +1,44 @@
+package stats
+
+import (
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestCountByTimezone tests the CountByTimezone function
+func TestCountByTimezone(t *testing.T) {
+	paris := time.FixedZone("CEST", 2*3600)
+	newYork := time.FixedZone("EDT", -4*3600)
+	commits := map[int][]CommitInfo{
+		0: {{When: time.Date(2023, 5, 15, 9, 0, 0, 0, paris)}, {When: time.Date(2023, 5, 15, 9, 0, 0, 0, newYork)}},
+		1: {{When: time.Date(2023, 5, 14, 9, 0, 0, 0, paris)}, {When: time.Date(2023, 5, 14, 9, 0, 0, 0, time.UTC)}},
+	}
+
+	expected := []ZoneCount{{Offset: -4 * 3600, Count: 1}, {Offset: 0, Count: 1}, {Offset: 2 * 3600, Count: 2}}
+	if zones := CountByTimezone(commits); !reflect.DeepEqual(zones, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, zones)
+	}
+
+	// No commits, no offsets
+	if zones := CountByTimezone(nil); len(zones) != 0 {
+		t.Errorf("Expected no offsets, got %+v", zones)
+	}
+}
+
+// TestZoneCountLabel tests the labels of the UTC offsets
+func TestZoneCountLabel(t *testing.T) {
+	tests := map[int]string{0: "UTC", 2 * 3600: "UTC+02:00", -4 * 3600: "UTC-04:00", 5*3600 + 1800: "UTC+05:30", -(9*3600 + 1800): "UTC-09:30"}
+	for offset, expected := range tests {
+		if label := (ZoneCount{Offset: offset}).Label(); label != expected {
+			t.Errorf("Expected %q for %d, got %q", expected, offset, label)
+		}
+	}
+}
+
+// TestPrintTimezones tests that PrintTimezones doesn't panic
+func TestPrintTimezones(t *testing.T) {
+	PrintTimezones(io.Discard, []ZoneCount{{Offset: 0, Count: 3}, {Offset: 3600, Count: 1}})
+	PrintTimezones(io.Discard, nil)
+}
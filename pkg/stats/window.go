@@ -0,0 +1,78 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+)
+
+// GraphRange is the look-back window of the contribution graph: the days counted before today,
+// and the week columns drawn before the current week.
+type GraphRange struct {
+	// Days is the number of days of the window before today, today being day 0 and the first day of the window day Days
+	Days int
+	// Weeks is the number of week columns before the current week, the current week being week 0
+	// and the oldest column week Weeks
+	Weeks int
+}
+
+// DefaultGraphRange is the window of the last six months, used when no other window is chosen.
+var DefaultGraphRange = GraphRange{Days: DaysInLastSixMonths, Weeks: WeeksInLastSixMonths}
+
+// DaysRange returns the window of the given number of days before today,
+// with as many week columns as there are whole weeks in it, like DefaultGraphRange.
+//
+// Parameters:
+//   - days: The number of days of the window before today
+//
+// Returns:
+//   - GraphRange: The window of the graph
+func DaysRange(days int) GraphRange {
+	return GraphRange{Days: days, Weeks: days / DaysInWeek}
+}
+
+// MonthsRange returns the window of the given number of months before today,
+// so its first day is the same day of the month, e.g. 12 months ending 2024-05-14 start 2023-05-14.
+//
+// Parameters:
+//   - months: The number of months of the window before today
+//   - today: The last day of the window
+//
+// Returns:
+//   - GraphRange: The window of the graph
+func MonthsRange(months int, today time.Time) GraphRange {
+	today = GetBeginningOfDay(today)
+	return DaysRange(daysBetween(today.AddDate(0, -months, 0), today))
+}
+
+// SinceRange returns the window starting on the given day and ending today.
+//
+// Parameters:
+//   - since: The first day of the window
+//   - today: The last day of the window
+//
+// Returns:
+//   - GraphRange: The window of the graph
+func SinceRange(since time.Time, today time.Time) GraphRange {
+	return DaysRange(daysBetween(GetBeginningOfDay(since), GetBeginningOfDay(today)))
+}
+
+// daysBetween returns the number of days from the beginning of a day to the beginning of a later one.
+func daysBetween(from time.Time, to time.Time) int {
+	return int(to.Sub(from).Hours() / HoursInDay)
+}
+
+// orDefault returns the window, or DefaultGraphRange if it is empty (the zero value of the options).
+func (r GraphRange) orDefault() GraphRange {
+	if r.Days <= 0 {
+		return DefaultGraphRange
+	}
+	return r
+}
+
+// String describes the window for messages, e.g. "the last 6 months" or "the last 30 days".
+func (r GraphRange) String() string {
+	if r.orDefault() == DefaultGraphRange {
+		return "the last 6 months"
+	}
+	return fmt.Sprintf("the last %d days", r.Days)
+}
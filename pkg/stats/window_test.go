@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+// TestMonthsRange tests that the window of some months starts on the same day of the month
+func TestMonthsRange(t *testing.T) {
+	today := time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC)
+
+	// 2023-05-14 to 2024-05-14, through February 29th
+	if window := MonthsRange(12, today); window != (GraphRange{Days: 366, Weeks: 52}) {
+		t.Errorf("Expected 366 days in 52 weeks, got %+v", window)
+	}
+	if window := MonthsRange(6, today); window.Days != 182 || today.AddDate(0, 0, -window.Days) != today.AddDate(0, -6, 0) {
+		t.Errorf("Expected the window to start 6 months ago, got %+v", window)
+	}
+
+	since := time.Date(2024, 4, 14, 12, 30, 0, 0, time.UTC)
+	if window := SinceRange(since, today); window != (GraphRange{Days: 30, Weeks: 4}) {
+		t.Errorf("Expected 30 days in 4 weeks, got %+v", window)
+	}
+
+	// The empty window is the default one
+	if (GraphRange{}).orDefault() != DefaultGraphRange || DaysRange(DaysInLastSixMonths) != DefaultGraphRange {
+		t.Errorf("Expected the default window of %+v", DefaultGraphRange)
+	}
+	if s := (GraphRange{}).String(); s != "the last 6 months" {
+		t.Errorf("Expected the last 6 months, got %q", s)
+	}
+	if s := DaysRange(30).String(); s != "the last 30 days" {
+		t.Errorf("Expected the last 30 days, got %q", s)
+	}
+}
+
+// TestCountDaysSinceDateWindow tests that the days out of range depend on the window
+func TestCountDaysSinceDateWindow(t *testing.T) {
+	today := GetBeginningOfDay(time.Now())
+	year := MonthsRange(12, today)
+	month := DaysRange(30)
+
+	tests := []struct {
+		window   GraphRange
+		daysAgo  int
+		expected int
+	}{
+		{year, 200, 200},
+		{year, year.Days, year.Days},
+		{year, year.Days + 1, OutOfRange},
+		{month, 30, 30},
+		{month, 31, OutOfRange},
+		{month, DaysInLastSixMonths, OutOfRange},
+	}
+
+	for _, test := range tests {
+		if result := CountDaysSinceDate(today.AddDate(0, 0, -test.daysAgo), test.window); result != test.expected {
+			t.Errorf("%d days ago in %+v: expected %d, got %d", test.daysAgo, test.window, test.expected, result)
+		}
+	}
+}
+
+// TestBuildColsWindow tests that the cells of 12-month and 30-day windows land on their dates,
+// with today in the last column, whatever the day of the week
+func TestBuildColsWindow(t *testing.T) {
+	for _, months := range []int{12, 0} {
+		for weekday := 0; weekday < DaysInWeek; weekday++ {
+			// From Sunday 2024-05-12 to Saturday 2024-05-18
+			today := time.Date(2024, 5, 12+weekday, 0, 0, 0, 0, time.UTC)
+			window := DaysRange(30)
+			if months > 0 {
+				window = MonthsRange(months, today)
+			}
+
+			// Every day of the window gets its own number of commits, so each cell tells its day
+			counts := make(map[int]int)
+			for daysAgo := 0; daysAgo <= window.Days; daysAgo++ {
+				counts[daysAgo] = daysAgo + 1
+			}
+			cols := buildCols(SortMapIntoSlice(counts), counts, today, false, window)
+
+			startOfFirstWeek, todayWeek, maxWeek := calculateGraphParameters(cols, today, false, 0, window)
+			if todayWeek != 0 || maxWeek != window.Weeks {
+				t.Fatalf("%s in %+v: expected today in week 0 of %d, got week %d of %d",
+					today.Format("2006-01-02"), window, window.Weeks, todayWeek, maxWeek)
+			}
+
+			// Only the oldest days of a window longer than its whole weeks may be left out
+			drawn := 0
+			for week, col := range cols {
+				for day, count := range col {
+					if count == 0 {
+						continue
+					}
+					drawn++
+					date := startOfFirstWeek.AddDate(0, 0, (window.Weeks-week)*DaysInWeek+day)
+					if daysAgo := daysBetween(date, today); daysAgo != count-1 {
+						t.Errorf("%s in %+v: week %d day %d shows day %d, expected day %d",
+							today.Format("2006-01-02"), window, week, day, count-1, daysAgo)
+					}
+				}
+			}
+			if drawn < window.Days+1-DaysInWeek || drawn > window.Days+1 {
+				t.Errorf("%s in %+v: expected the %d days of the window, got %d", today.Format("2006-01-02"), window, window.Days+1, drawn)
+			}
+		}
+	}
+}
+
+// TestPrintMonthsWindow tests that the month labels span the columns of the window
+func TestPrintMonthsWindow(t *testing.T) {
+	today := time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC)
+
+	// The 53 columns of a year are labelled with the 12 months starting after the oldest one, May 1st two weeks ago
+	year := RenderOptions{Range: MonthsRange(12, today), Until: today}
+	if columns := year.weekColumns(); columns != 53 {
+		t.Errorf("Expected 53 columns, got %d", columns)
+	}
+	_, startOfWeek := graphStart(today, false, year.window())
+	if months := monthStarts(startOfWeek, year.weekColumns()-1, year.window()); len(months) != 12 || months[2] != "May" {
+		t.Errorf("Expected 12 months ending with May, got %v", months)
+	}
+
+	month := RenderOptions{Range: DaysRange(30), Until: today}
+	if width := month.GridWidth(); width != dayColWidth+month.cellWidth()*5+3 {
+		t.Errorf("Expected the width of 5 columns, got %d", width)
+	}
+
+	// This just ensures the labels of the windows don't panic
+	PrintMonths(io.Discard, year)
+	PrintMonths(io.Discard, month)
+}
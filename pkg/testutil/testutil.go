@@ -0,0 +1,105 @@
+// Package testutil builds Git repositories with known commits for the tests of the other packages.
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// Commit describes a commit to create in a test repository.
+type Commit struct {
+	// Email is the email of the author (and committer), also used as their name
+	Email string
+	// Message is the commit message
+	Message string
+	// When is the author (and commit) date
+	When time.Time
+	// Files maps the slash-separated paths of the files to write before committing to their content
+	// (if empty, the commit is empty)
+	Files map[string]string
+}
+
+// NewMemoryRepo creates an in-memory Git repository with the given commits, in order,
+// for the tests working on an opened repository.
+//
+// Parameters:
+//   - t: The test (or benchmark) the repository is created for
+//   - commits: The commits to create, oldest first
+//
+// Returns:
+//   - *git.Repository: The repository
+func NewMemoryRepo(t testing.TB, commits ...Commit) *git.Repository {
+	t.Helper()
+
+	repo, err := git.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	AddCommits(t, repo, commits...)
+
+	return repo
+}
+
+// NewRepo creates a Git repository in a temporary directory with the given commits, in order,
+// for the tests working on the path of a repository. The directory is removed after the test.
+//
+// Parameters:
+//   - t: The test (or benchmark) the repository is created for
+//   - commits: The commits to create, oldest first
+//
+// Returns:
+//   - string: The path of the repository
+func NewRepo(t testing.TB, commits ...Commit) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("Failed to create repository: %v", err)
+	}
+	AddCommits(t, repo, commits...)
+
+	return dir
+}
+
+// AddCommits creates commits on the current branch of a repository, in order.
+//
+// Parameters:
+//   - t: The test (or benchmark) the commits are created for
+//   - repo: The repository to commit to
+//   - commits: The commits to create, oldest first
+func AddCommits(t testing.TB, repo *git.Repository, commits ...Commit) {
+	t.Helper()
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("Failed to get worktree: %v", err)
+	}
+
+	for _, c := range commits {
+		for path, content := range c.Files {
+			if err := util.WriteFile(worktree.Filesystem, path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write %s: %v", path, err)
+			}
+			if _, err := worktree.Add(path); err != nil {
+				t.Fatalf("Failed to add %s: %v", path, err)
+			}
+		}
+
+		signature := &object.Signature{Name: c.Email, Email: c.Email, When: c.When}
+		_, err := worktree.Commit(c.Message, &git.CommitOptions{
+			Author:            signature,
+			Committer:         signature,
+			AllowEmptyCommits: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+}